@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/doganarif/k9sight/internal/bundle"
+	"github.com/doganarif/k9sight/internal/config"
+	"github.com/doganarif/k9sight/internal/diagnose"
+	"github.com/doganarif/k9sight/internal/k8s"
+)
+
+const bundleUsage = `Usage: k9sight bundle <kind>/<name> [-n namespace] [-f file.tar.gz]
+
+Writes a support bundle for a pod or workload: the YAML of the pods and
+their owner chain, current and previous container logs, events, kubectl
+describe output, metrics, related Services, Ingresses and ConfigMap and
+Secret names, and the diagnostic findings, with an index.json at the top.
+Literal values of env vars that look like credentials are redacted.
+
+Open a bundle without cluster access with k9sight --bundle <file>.
+
+Options:
+`
+
+// runBundle implements the bundle subcommand and returns its exit code.
+func runBundle(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bundle", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, bundleUsage)
+		fs.PrintDefaults()
+	}
+
+	opts := bundle.DefaultOptions()
+	var namespace, file string
+	fs.StringVar(&namespace, "n", "", "namespace (default: the last namespace used in k9sight)")
+	fs.StringVar(&namespace, "namespace", "", "namespace")
+	fs.StringVar(&file, "f", "", "output file, - for stdout (default: k9sight-<namespace>-<kind>-<name>-<time>.tar.gz)")
+	fs.StringVar(&file, "file", "", "output file")
+	fs.Int64Var(&opts.LogLines, "tail", opts.LogLines, "log lines per container and run")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if namespace == "" {
+		namespace = cfg.LastNamespace
+	}
+
+	target, err := diagnose.ParseTarget(positional[0], namespace)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	if target.Type == k8s.ResourceNodes {
+		fmt.Fprintln(stderr, "Error: bundles are written for pods and workloads, not nodes")
+		return 1
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+
+	b, err := bundle.Collect(context.Background(), client, loadRules(cfg, stderr), target.Type, target.Namespace, target.Name, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: collecting %s: %v\n", target, err)
+		return 1
+	}
+	for _, e := range b.Index.Errors {
+		fmt.Fprintln(stderr, "Warning: not collected:", e)
+	}
+
+	if file == "-" {
+		if err := bundle.Write(stdout, b); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+	if file == "" {
+		file = b.FileName()
+	}
+	if err := bundle.Save(file, b); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "Wrote %s (%d pods)\n", file, len(b.Pods))
+	return 0
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/doganarif/k9sight/internal/config"
+	"github.com/doganarif/k9sight/internal/diagnose"
+	"github.com/doganarif/k9sight/internal/k8s"
+)
+
+const diagnoseUsage = `Usage: k9sight diagnose <kind>/<name> [-n namespace] [-o text|json|markdown]
+
+Prints what the dashboard shows for a pod or workload: pod and container
+state, last terminations, events, related resources, metrics, diagnostic
+findings and recent error log lines.
+
+Exit status: 0 nothing above Info, 2 Warning, 3 Medium, 4 High,
+1 the report could not be produced.
+
+Options:
+`
+
+// runDiagnose implements the diagnose subcommand and returns its exit code.
+func runDiagnose(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diagnose", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, diagnoseUsage)
+		fs.PrintDefaults()
+	}
+
+	opts := diagnose.DefaultOptions()
+	var namespace, output string
+	fs.StringVar(&namespace, "n", "", "namespace (default: the last namespace used in k9sight)")
+	fs.StringVar(&namespace, "namespace", "", "namespace")
+	fs.StringVar(&output, "o", "text", "output format: text, json or markdown")
+	fs.StringVar(&output, "output", "text", "output format")
+	fs.IntVar(&opts.ErrorLines, "errors", opts.ErrorLines, "error log lines to include per pod")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return diagnose.ExitOK
+		}
+		return diagnose.ExitError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return diagnose.ExitError
+	}
+
+	format, err := diagnose.ParseFormat(output)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if namespace == "" {
+		namespace = cfg.LastNamespace
+	}
+
+	target, err := diagnose.ParseTarget(positional[0], namespace)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+
+	report, err := diagnose.Gather(context.Background(), client, loadRules(cfg, stderr), target, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: diagnosing %s: %v\n", target, err)
+		return diagnose.ExitError
+	}
+
+	if err := diagnose.Write(stdout, report, format); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+	return report.ExitCode()
+}
+
+// loadRules returns the built-in rules extended by the custom rule files.
+// Broken rule files are reported but the remaining rules still apply.
+func loadRules(cfg *config.Config, stderr io.Writer) *k8s.RuleEngine {
+	engine := k8s.NewRuleEngine(k8s.DefaultRules())
+	if dir, err := cfg.RulesPath(); err == nil {
+		if engine, err = k8s.LoadRuleEngine(dir); err != nil {
+			fmt.Fprintln(stderr, "Warning:", err)
+		}
+	}
+	return engine
+}
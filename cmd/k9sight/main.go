@@ -1,11 +1,16 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/doganarif/k9sight/internal/app"
+	"github.com/doganarif/k9sight/internal/bundle"
+	"github.com/doganarif/k9sight/internal/diagnose"
+	"github.com/doganarif/k9sight/internal/k8s"
 )
 
 const version = "0.1.0"
@@ -19,15 +24,42 @@ func main() {
 		case "--help", "-h":
 			printHelp()
 			os.Exit(0)
+		case "diagnose":
+			os.Exit(runDiagnose(os.Args[2:], os.Stdout, os.Stderr))
+		case "wait":
+			os.Exit(runWait(os.Args[2:], os.Stdout, os.Stderr))
+		case "bundle":
+			os.Exit(runBundle(os.Args[2:], os.Stdout, os.Stderr))
 		}
 	}
 
-	model, err := app.New()
+	opts, err := parseStartArgs(os.Args[1:])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
+		if err == flag.ErrHelp {
+			printHelp()
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	var model tea.Model
+	if opts.Bundle != "" {
+		b, err := bundle.Load(opts.Bundle)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening bundle: %v\n", err)
+			os.Exit(1)
+		}
+		model = app.NewBundleViewer(b)
+	} else {
+		m, err := app.New(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
+			os.Exit(1)
+		}
+		model = m
+	}
+
 	p := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),
@@ -40,6 +72,69 @@ func main() {
 	}
 }
 
+// parseStartArgs reads the optional resource to open at startup, such as
+// pod/api-7d9f or deploy/api, and the -n, --container, --bundle and
+// --manifests flags.
+func parseStartArgs(args []string) (app.Options, error) {
+	var opts app.Options
+	fs := flag.NewFlagSet("k9sight", flag.ContinueOnError)
+	fs.StringVar(&opts.Namespace, "n", "", "namespace")
+	fs.StringVar(&opts.Namespace, "namespace", "", "namespace")
+	fs.StringVar(&opts.Container, "c", "", "logs container")
+	fs.StringVar(&opts.Container, "container", "", "logs container")
+	fs.StringVar(&opts.Bundle, "bundle", "", "support bundle to browse")
+	fs.StringVar(&opts.Manifests, "manifests", "", "manifests to check drift against")
+	// Errors are printed by the caller
+	fs.SetOutput(io.Discard)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return opts, err
+	}
+	if opts.Bundle != "" {
+		if len(positional) > 0 || opts.Namespace != "" || opts.Container != "" || opts.Manifests != "" {
+			return opts, fmt.Errorf("--bundle cannot be combined with a resource, --namespace, --container or --manifests")
+		}
+		return opts, nil
+	}
+	switch len(positional) {
+	case 0:
+		if opts.Container != "" {
+			return opts, fmt.Errorf("--container needs a pod or workload to open")
+		}
+		return opts, nil
+	case 1:
+	default:
+		return opts, fmt.Errorf("expected one resource to open, got %d", len(positional))
+	}
+
+	target, err := diagnose.ParseTarget(positional[0], opts.Namespace)
+	if err != nil {
+		return opts, err
+	}
+	if target.Type == k8s.ResourceNodes && opts.Container != "" {
+		return opts, fmt.Errorf("--container needs a pod or workload to open")
+	}
+	opts.Open = &target
+	return opts, nil
+}
+
+// parseInterspersed parses args allowing flags before or after the
+// positional arguments, as with kubectl, and returns the positionals.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
 func printHelp() {
 	help := `k9sight - Kubernetes Manifest Debugger TUI
 
@@ -47,10 +142,29 @@ One screen to see why your pod is broken.
 
 USAGE:
     k9sight [OPTIONS]
+    k9sight <kind>/<name> [-n namespace] [-c container]
+    k9sight --manifests <file|dir>
+    k9sight --bundle <file>
+    k9sight diagnose <kind>/<name> [-n namespace] [-o text|json|markdown]
+    k9sight wait <kind>/<name> [-n namespace] [--timeout 5m]
+    k9sight bundle <kind>/<name> [-n namespace] [-f file.tar.gz]
 
 OPTIONS:
     -h, --help       Show this help message
     -v, --version    Show version information
+    -n, --namespace  Namespace to start in
+    -c, --container  Logs container to preselect when opening a pod or
+                     workload
+    --bundle         Browse a support bundle written by k9sight bundle or
+                     B on a dashboard, without cluster access
+    --manifests      Manifest file or directory (YAML or JSON, searched
+                     recursively) to check workloads for drift against;
+                     without it the last-applied-configuration annotation
+                     is used
+
+    A pod (pod/<name> or a bare name) opens its dashboard, a workload
+    (deploy/, sts/, ds/, job/, cj/) its pod list and a node (node/<name>)
+    its detail.
 
 KEYBOARD SHORTCUTS:
     Navigation:
@@ -64,6 +178,9 @@ KEYBOARD SHORTCUTS:
     Actions:
         n            Change namespace
         t            Change resource type
+        e            Edit the selected workload or pod in $EDITOR
+        D            Show how the selected workload or pod drifted from
+                     its manifest
         r            Refresh data
         /            Search
         *            Toggle favorite
@@ -72,15 +189,29 @@ KEYBOARD SHORTCUTS:
         L            Focus logs panel
         E            Focus events panel
         M            Focus manifest panel
+        d            Cycle manifest modes, including the resolved
+                     container environment and raw YAML/JSON
         m            Focus metrics panel
         F            Toggle log following
         e            Jump to next error
         w            Toggle all events
+        B            Write a support bundle of the pod
 
     General:
+        I            Status history, including revealed secrets
         ?            Show help
         q            Quit
 
+COMMANDS:
+    diagnose     Print a diagnosis of a pod or workload and exit with a
+                 code reflecting the highest severity found
+                 (see k9sight diagnose -h)
+    wait         Wait until a workload has rolled out and its pods are
+                 healthy; on failure or timeout print the diagnosis of the
+                 failing pods (see k9sight wait -h)
+    bundle       Write a support bundle of a pod or workload for
+                 attaching to a ticket (see k9sight bundle -h)
+
 CONFIGURATION:
     Config file: ~/.config/k9sight/config.json
 
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/doganarif/k9sight/internal/config"
+	"github.com/doganarif/k9sight/internal/diagnose"
+	"github.com/doganarif/k9sight/internal/k8s"
+)
+
+const waitUsage = `Usage: k9sight wait <kind>/<name> [-n namespace] [--timeout 5m] [-o text|json|markdown]
+
+Waits until a workload has rolled out with all its pods available, a job
+has completed, or a pod is ready. If the rollout fails or the timeout
+passes, prints the diagnosis of the failing pods: findings, warning events
+and recent error log lines.
+
+Exit status: 0 healthy, 1 error, 2 timed out, 3 the rollout failed.
+
+Options:
+`
+
+const (
+	waitExitTimeout = 2
+	waitExitFailed  = 3
+
+	waitPollInterval = 2 * time.Second
+)
+
+// runWait implements the wait subcommand and returns its exit code.
+func runWait(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("wait", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, waitUsage)
+		fs.PrintDefaults()
+	}
+
+	opts := diagnose.DefaultOptions()
+	opts.FailingOnly = true
+	var namespace, output string
+	var timeout time.Duration
+	fs.StringVar(&namespace, "n", "", "namespace (default: the last namespace used in k9sight)")
+	fs.StringVar(&namespace, "namespace", "", "namespace")
+	fs.DurationVar(&timeout, "timeout", 5*time.Minute, "how long to wait before giving up")
+	fs.StringVar(&output, "o", "text", "failure report format: text, json or markdown")
+	fs.StringVar(&output, "output", "text", "failure report format")
+	fs.IntVar(&opts.ErrorLines, "errors", opts.ErrorLines, "error log lines to include per failing pod")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return diagnose.ExitOK
+		}
+		return diagnose.ExitError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return diagnose.ExitError
+	}
+
+	format, err := diagnose.ParseFormat(output)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if namespace == "" {
+		namespace = cfg.LastNamespace
+	}
+
+	target, err := diagnose.ParseTarget(positional[0], namespace)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+	switch target.Type {
+	case k8s.ResourceNodes, k8s.ResourceCronJobs:
+		fmt.Fprintf(stderr, "Error: cannot wait for %s, use a pod, deployment, statefulset, daemonset or job\n", target)
+		return diagnose.ExitError
+	}
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return diagnose.ExitError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	var last string
+	checked := false
+	for {
+		state, msg, err := rolloutStatus(ctx, client, target)
+		switch {
+		case err != nil && !checked:
+			// The target has to exist; later errors may be transient
+			fmt.Fprintf(stderr, "Error: %s: %v\n", target, err)
+			return diagnose.ExitError
+		case err != nil && ctx.Err() != nil:
+			msg = last
+		case err != nil:
+			msg = "error: " + err.Error()
+		}
+		checked = true
+
+		if msg != last {
+			fmt.Fprintf(stderr, "%s: %s\n", target, msg)
+			last = msg
+		}
+
+		switch state {
+		case k8s.RolloutComplete:
+			fmt.Fprintf(stdout, "%s is healthy\n", target)
+			return diagnose.ExitOK
+		case k8s.RolloutFailed:
+			fmt.Fprintf(stderr, "%s failed: %s\n", target, msg)
+			if !reportWaitFailure(client, loadRules(cfg, stderr), target, opts, format, stdout, stderr) {
+				return diagnose.ExitError
+			}
+			return waitExitFailed
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintf(stderr, "%s is not healthy after %s: %s\n", target, timeout, msg)
+			if !reportWaitFailure(client, loadRules(cfg, stderr), target, opts, format, stdout, stderr) {
+				return diagnose.ExitError
+			}
+			return waitExitTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+// rolloutStatus reads the current state of target.
+func rolloutStatus(ctx context.Context, client *k8s.Client, target diagnose.Target) (k8s.RolloutState, string, error) {
+	if target.Type == k8s.ResourcePods {
+		pod, err := k8s.GetPod(ctx, client.Clientset(), target.Namespace, target.Name)
+		if err != nil {
+			return k8s.RolloutProgressing, "", err
+		}
+		state, msg := k8s.PodRolloutStatus(pod)
+		return state, msg, nil
+	}
+
+	workload, err := k8s.GetWorkload(ctx, client.Clientset(), target.Namespace, target.Type, target.Name)
+	if err != nil {
+		return k8s.RolloutProgressing, "", err
+	}
+	state, msg := k8s.RolloutStatus(workload)
+	return state, msg, nil
+}
+
+// reportWaitFailure prints the diagnosis of the failing pods. It reports
+// false when the diagnosis could not be produced.
+func reportWaitFailure(client *k8s.Client, engine *k8s.RuleEngine, target diagnose.Target, opts diagnose.Options, format diagnose.Format, stdout, stderr io.Writer) bool {
+	report, err := diagnose.Gather(context.Background(), client, engine, target, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: diagnosing %s: %v\n", target, err)
+		return false
+	}
+	if err := diagnose.Write(stdout, report, format); err != nil {
+		fmt.Fprintln(stderr, "Error:", err)
+		return false
+	}
+	return true
+}
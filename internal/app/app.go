@@ -3,13 +3,16 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/bundle"
 	"github.com/doganarif/k9sight/internal/config"
+	"github.com/doganarif/k9sight/internal/diagnose"
 	"github.com/doganarif/k9sight/internal/k8s"
 	"github.com/doganarif/k9sight/internal/ui/components"
 	"github.com/doganarif/k9sight/internal/ui/keys"
@@ -22,6 +25,11 @@ type ViewState int
 const (
 	ViewNavigator ViewState = iota
 	ViewDashboard
+	ViewWorkload
+	ViewWarnings
+	ViewTop
+	ViewTriage
+	ViewNodes
 )
 
 type Model struct {
@@ -29,11 +37,18 @@ type Model struct {
 	config             *config.Config
 	navigator          components.Navigator
 	dashboard          views.Dashboard
+	workloadView       components.WorkloadView
+	warningsView       components.WarningsView
+	topView            components.TopView
+	triageView         components.TriageView
+	nodesView          components.NodesView
 	statusBar          components.StatusBar
 	help               components.HelpPanel
 	spinner            spinner.Model
 	workloadActionMenu components.WorkloadActionMenu
 	confirmDialog      components.ConfirmDialog
+	resultViewer       components.ResultViewer
+	dataViewer         components.DataViewer
 	view               ViewState
 	width              int
 	height             int
@@ -44,9 +59,52 @@ type Model struct {
 	pod                *k8s.PodInfo
 	statusMsg          string // Status message for navigator view
 
+	// Optional source for logs of pods the kubelet no longer has
+	logProvider k8s.LogProvider
+
+	// Per-container metrics samples recorded on every dashboard refresh
+	metricsHistory *k8s.MetricsHistory
+
+	// Optional source of metrics over longer ranges than the session
+	metricsProvider   k8s.MetricsProvider
+	lastMetricsWindow time.Duration
+	lastMetricsRange  time.Time
+
+	// Built-in and custom diagnostic rules behind the debug hints
+	rules *k8s.RuleEngine
+
+	// Live event stream for the pod shown in the dashboard
+	eventWatch *k8s.EventWatch
+
+	// Live Warning event stream for the warnings feed
+	warningsWatch     *k8s.EventWatch
+	warningsNamespace string
+
+	// Namespace the top view was last listed for ("" = all)
+	topNamespace string
+
+	// Namespace the triage view was last scanned for ("" = all)
+	triageNamespace string
+
+	// View to return to when leaving the dashboard
+	dashboardReturn ViewState
+
+	// Local manifests drift is checked against, re-read on every scan
+	manifestsPath string
+
+	// Messages recorded with recordStatus, oldest first
+	statusHistory []statusEntry
+
+	// View opened from the startup arguments, and the logs container
+	// preselected in dashboards of the startup workload's pods
+	startCmd       tea.Cmd
+	startContainer string
+
 	// State tracking for reactive log fetching
-	lastShowPrevious bool
-	lastLogContainer string
+	lastShowPrevious  bool
+	lastShowHistory   bool
+	lastHistoryWindow time.Duration
+	lastLogContainer  string
 }
 
 type loadedMsg struct {
@@ -55,6 +113,25 @@ type loadedMsg struct {
 	err        error
 }
 
+type bundleWrittenMsg struct {
+	path    string
+	missing int
+	err     error
+}
+
+type envLoadedMsg struct {
+	namespace string
+	pod       string
+	envs      []k8s.ContainerEnv
+	err       error
+}
+
+type manifestLoadedMsg struct {
+	ref    k8s.ObjectReference
+	fields map[string]interface{}
+	err    error
+}
+
 type podsLoadedMsg struct {
 	pods []k8s.PodInfo
 	err  error
@@ -68,6 +145,91 @@ type dashboardDataMsg struct {
 	helpers []k8s.DebugHelper
 }
 
+type workloadEventsMsg struct {
+	workload string
+	info     *k8s.WorkloadInfo
+	objects  []k8s.ObjectReference
+	events   []k8s.EventInfo
+	err      error
+}
+
+type warningsLoadedMsg struct {
+	namespace string
+	events    []k8s.EventInfo
+	err       error
+}
+
+type topLoadedMsg struct {
+	namespace string
+	usages    []k8s.PodUsage
+	metrics   []k8s.PodMetrics
+	err       error
+}
+
+type triageLoadedMsg struct {
+	namespace string
+	results   []k8s.PodTriage
+	err       error
+}
+
+type nodesLoadedMsg struct {
+	nodes []k8s.NodeInfo
+	err   error
+}
+
+type nodePodsMsg struct {
+	node string
+	pods []k8s.PodInfo
+	err  error
+}
+
+type nodeActionMsg struct {
+	node   string
+	action string
+	err    error
+}
+
+// nodeDrainMsg reports one eviction pass of a drain. evicted counts the
+// pods evicted over all passes so far.
+type nodeDrainMsg struct {
+	node    string
+	round   int
+	evicted int
+	result  k8s.DrainResult
+	err     error
+}
+
+// drainRetryMsg starts the next pass of a drain.
+type drainRetryMsg struct {
+	node    string
+	round   int
+	evicted int
+}
+
+const (
+	drainRetryInterval = 5 * time.Second
+	// drainMaxRounds bounds how long a drain keeps retrying blocked
+	// evictions and waiting for pods to terminate (about three minutes)
+	drainMaxRounds = 36
+)
+
+// warningTargetMsg carries the pod, workload or node an entry in the
+// warnings feed resolved to.
+type warningTargetMsg struct {
+	pod      *k8s.PodInfo
+	workload *k8s.WorkloadInfo
+	node     string
+	err      error
+}
+
+type metricsRangeMsg struct {
+	namespace string
+	podName   string
+	window    time.Duration
+	data      *k8s.PodRangeMetrics
+	err       error
+}
+
 type logsUpdatedMsg struct {
 	logs []k8s.LogLine
 }
@@ -87,9 +249,49 @@ type workloadActionMsg struct {
 	err          error
 }
 
+type eventWatchStartedMsg struct {
+	watch   *k8s.EventWatch
+	podName string
+	// Set for the warnings feed watch, which is keyed by namespace instead
+	warnings  bool
+	namespace string
+	err       error
+}
+
+type eventReceivedMsg struct {
+	watch *k8s.EventWatch
+	event k8s.EventInfo
+}
+
+type eventWatchClosedMsg struct {
+	watch *k8s.EventWatch
+}
+
 type tickMsg time.Time
 
-func New() (*Model, error) {
+// metricsRangeRefresh is how often range data is refetched while the
+// dashboard is open; the series step is at least this coarse anyway.
+const metricsRangeRefresh = 30 * time.Second
+
+// Options are the startup arguments of the TUI.
+type Options struct {
+	// Namespace replaces the last namespace used
+	Namespace string
+	// Open is shown instead of the workload list: a pod's dashboard, a
+	// workload's pods or a node's detail
+	Open *diagnose.Target
+	// Container preselects the logs container of the dashboards opened
+	// from Open
+	Container string
+	// Bundle is the path of a support bundle to browse instead of the
+	// cluster, with NewBundleViewer
+	Bundle string
+	// Manifests is a manifest file or directory workloads are checked
+	// for drift against, before their last-applied annotation
+	Manifests string
+}
+
+func New(opts Options) (*Model, error) {
 	client, err := k8s.NewClient()
 	if err != nil {
 		return nil, err
@@ -100,35 +302,186 @@ func New() (*Model, error) {
 		cfg = config.DefaultConfig()
 	}
 
+	if opts.Namespace != "" {
+		cfg.SetLastNamespace(opts.Namespace)
+	}
 	client.SetNamespace(cfg.LastNamespace)
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = styles.SpinnerStyle
 
-	return &Model{
+	var logProvider k8s.LogProvider
+	if cfg.LokiURL != "" {
+		logProvider = k8s.NewLokiProvider(cfg.LokiURL, cfg.LokiTenant)
+	}
+
+	var metricsProvider k8s.MetricsProvider
+	if cfg.PrometheusURL != "" {
+		metricsProvider = k8s.NewPrometheusProvider(cfg.PrometheusURL)
+	}
+
+	if cfg.MetricsHistoryMinutes <= 0 {
+		cfg.MetricsHistoryMinutes = config.DefaultConfig().MetricsHistoryMinutes
+	}
+	// Enough room for one sample per refresh over the configured window
+	refresh := cfg.RefreshInterval
+	if refresh <= 0 {
+		refresh = 1
+	}
+	metricsHistory := k8s.NewMetricsHistory(cfg.MetricsHistoryMinutes*60/refresh, time.Duration(cfg.MetricsHistoryMinutes)*time.Minute)
+
+	// Custom rules extend or override the built-in ones; a broken rules file
+	// is reported but does not stop startup
+	var statusMsg string
+	rules := k8s.NewRuleEngine(k8s.DefaultRules())
+	if dir, err := cfg.RulesPath(); err == nil {
+		rules, err = k8s.LoadRuleEngine(dir)
+		if err != nil {
+			statusMsg = "Error: " + err.Error()
+		}
+	}
+
+	dashboard := views.NewDashboard()
+	dashboard.SetLogsHistoryAvailable(logProvider != nil)
+	dashboard.SetMetricsRangeAvailable(metricsProvider != nil)
+
+	m := &Model{
 		k8sClient:          client,
 		config:             cfg,
 		navigator:          components.NewNavigator(),
-		dashboard:          views.NewDashboard(),
+		dashboard:          dashboard,
+		workloadView:       components.NewWorkloadView(),
+		warningsView:       components.NewWarningsView(),
+		topView:            components.NewTopView(),
+		triageView:         components.NewTriageView(),
+		nodesView:          components.NewNodesView(),
 		statusBar:          components.NewStatusBar(),
 		help:               components.NewHelpPanel(),
 		spinner:            s,
 		workloadActionMenu: components.NewWorkloadActionMenu(),
 		confirmDialog:      components.NewConfirmDialog(),
+		resultViewer:       components.NewResultViewer(),
+		dataViewer:         components.NewDataViewer(),
 		view:               ViewNavigator,
 		loading:            true,
 		keys:      keys.DefaultKeyMap(),
-	}, nil
+		logProvider:        logProvider,
+		metricsHistory:     metricsHistory,
+		metricsProvider:    metricsProvider,
+		rules:              rules,
+		statusMsg:          statusMsg,
+		manifestsPath:      opts.Manifests,
+	}
+
+	// A manifests path that cannot be read is reported before the TUI
+	// starts, like an unknown startup resource
+	if _, err := m.localManifests(); err != nil {
+		return nil, err
+	}
+
+	if opts.Open != nil {
+		if err := m.openTarget(*opts.Open, opts.Container); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
 		m.loadInitialData(),
+		m.tickCmd(),
+		m.startCmd,
 	)
 }
 
+// openTarget switches to the view of a resource named on the command line.
+// The resource is looked up first so a typo is reported before the TUI
+// starts rather than as an empty screen.
+func (m *Model) openTarget(target diagnose.Target, container string) error {
+	ctx := context.Background()
+	cs := m.k8sClient.Clientset()
+	if target.Namespace == "" {
+		target.Namespace = m.k8sClient.Namespace()
+	}
+
+	switch target.Type {
+	case k8s.ResourcePods:
+		pod, err := k8s.GetPod(ctx, cs, target.Namespace, target.Name)
+		if err != nil {
+			return fmt.Errorf("cannot open %s in namespace %s: %w", target, target.Namespace, err)
+		}
+		m.startCmd = m.openPodDashboard(pod, ViewNavigator, string(k8s.ResourcePods))
+		if container != "" && !m.dashboard.SelectLogsContainer(container) {
+			return fmt.Errorf("pod %s has no container %q (containers: %s)", pod.Name, container, strings.Join(pod.ContainerNames(), ", "))
+		}
+		return nil
+
+	case k8s.ResourceNodes:
+		if _, err := k8s.GetNode(ctx, cs, target.Name); err != nil {
+			return fmt.Errorf("cannot open %s: %w", target, err)
+		}
+		m.nodesView.OpenNode(target.Name)
+		m.startCmd = tea.Batch(m.openNodes(), m.loadNodePods(target.Name))
+		return nil
+	}
+
+	workload, err := k8s.GetWorkload(ctx, cs, target.Namespace, target.Type, target.Name)
+	if err != nil {
+		return fmt.Errorf("cannot open %s in namespace %s: %w", target, target.Namespace, err)
+	}
+	if container != "" {
+		names, err := m.workloadContainerNames(ctx, workload)
+		if err != nil {
+			return fmt.Errorf("cannot open %s in namespace %s: %w", target, target.Namespace, err)
+		}
+		found := false
+		for _, name := range names {
+			if name == container {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s has no container %q (containers: %s)", target, container, strings.Join(names, ", "))
+		}
+	}
+	// loadInitialData lists the workloads of the last resource type
+	m.navigator.SetResourceType(target.Type)
+	m.config.SetLastResourceType(string(target.Type))
+	m.workload = workload
+	m.startContainer = container
+	m.startCmd = m.loadPods(workload)
+	return nil
+}
+
+// workloadContainerNames returns the containers --container can pick for a
+// workload: those of its pods, init containers and injected sidecars
+// included, or of its pod template when it has no pods.
+func (m *Model) workloadContainerNames(ctx context.Context, workload *k8s.WorkloadInfo) ([]string, error) {
+	cs := m.k8sClient.Clientset()
+	pods, err := k8s.GetWorkloadPods(ctx, cs, *workload)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return k8s.WorkloadContainers(ctx, cs, workload.Namespace, workload.Type, workload.Name)
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, p := range pods {
+		for _, name := range p.ContainerNames() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -139,8 +492,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.navigator.SetSize(msg.Width, msg.Height-2)
 		m.dashboard.SetSize(msg.Width, msg.Height-2)
+		m.workloadView.SetSize(msg.Width, msg.Height-4)
+		m.warningsView.SetSize(msg.Width, msg.Height-2)
+		m.topView.SetSize(msg.Width, msg.Height-2)
+		m.triageView.SetSize(msg.Width, msg.Height-2)
+		m.nodesView.SetSize(msg.Width, msg.Height-2)
 		m.statusBar.SetWidth(msg.Width)
 		m.help.SetSize(msg.Width, msg.Height)
+		if m.dataViewer.IsVisible() {
+			m.dataViewer.SetSize(msg.Width-4, msg.Height-4)
+		}
 		return m, nil
 
 	case spinner.TickMsg:
@@ -155,6 +516,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.navigator.SetWorkloads(msg.workloads)
 		m.navigator.SetNamespaces(msg.namespaces)
+		return m, m.scanDrift()
+
+	case driftScannedMsg:
+		m.driftScanned(msg)
+		return m, nil
+
+	case driftMsg:
+		m.driftLoaded(msg)
 		return m, nil
 
 	case podsLoadedMsg:
@@ -169,9 +538,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case dashboardDataMsg:
 		m.loading = false
-		m.dashboard.SetLogs(msg.logs)
+		// Historical logs are only refetched when the history window changes
+		if !m.dashboard.LogsShowHistory() {
+			m.dashboard.SetLogs(msg.logs)
+		}
 		m.dashboard.SetEvents(msg.events)
 		m.dashboard.SetMetrics(msg.metrics)
+		m.recordMetrics(msg.metrics)
 		m.dashboard.SetRelated(msg.related)
 		m.dashboard.SetHelpers(msg.helpers)
 		return m, nil
@@ -180,16 +553,194 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dashboard.SetLogs(msg.logs)
 		return m, nil
 
+	case metricsRangeMsg:
+		// Drop responses for a pod or window that is no longer shown
+		if m.pod != nil && m.pod.Namespace == msg.namespace && m.pod.Name == msg.podName &&
+			msg.window == m.dashboard.MetricsRangeWindow() {
+			m.dashboard.SetMetricsRange(msg.data, msg.err)
+		}
+		return m, nil
+
+	case workloadEventsMsg:
+		m.loading = false
+		if w := m.workloadView.Workload(); w != nil && w.Name == msg.workload {
+			if msg.info != nil {
+				m.workloadView.UpdateWorkload(msg.info)
+			}
+			m.workloadView.SetData(msg.objects, msg.events, msg.err)
+		}
+		return m, nil
+
+	case warningsLoadedMsg:
+		m.loading = false
+		if msg.namespace == m.warningsView.WatchNamespace() {
+			m.warningsView.SetEvents(msg.events, msg.err)
+		}
+		return m, nil
+
+	case topLoadedMsg:
+		m.loading = false
+		// Every listing doubles as a metrics sample for the pods' history
+		now := time.Now()
+		for i := range msg.metrics {
+			m.metricsHistory.Record(&msg.metrics[i], now)
+		}
+		if m.view == ViewTop && msg.namespace == m.topView.ListNamespace() {
+			m.topView.SetUsages(msg.usages, msg.err)
+		}
+		return m, nil
+
+	case triageLoadedMsg:
+		m.loading = false
+		if msg.namespace == m.triageView.ScanNamespace() {
+			m.triageView.SetResults(msg.results, msg.err)
+		}
+		return m, nil
+
+	case nodesLoadedMsg:
+		m.loading = false
+		m.nodesView.SetNodes(msg.nodes, msg.err)
+		return m, nil
+
+	case nodePodsMsg:
+		m.nodesView.SetPods(msg.node, msg.pods, msg.err)
+		return m, nil
+
+	case warningTargetMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+			return m, nil
+		}
+		if msg.node != "" {
+			m.stopWarningsWatch()
+			m.nodesView.OpenNode(msg.node)
+			return m, tea.Batch(m.openNodes(), m.loadNodePods(msg.node))
+		}
+		if msg.pod != nil {
+			m.stopWarningsWatch()
+			m.workload = nil
+			return m, m.openPodDashboard(msg.pod, ViewWarnings, "warnings")
+		}
+		if msg.workload != nil {
+			m.stopWarningsWatch()
+			m.statusMsg = ""
+			if msg.workload.Namespace != m.k8sClient.Namespace() {
+				m.k8sClient.SetNamespace(msg.workload.Namespace)
+				m.config.SetLastNamespace(msg.workload.Namespace)
+			}
+			m.navigator.SetResourceType(msg.workload.Type)
+			m.workload = msg.workload
+			m.view = ViewNavigator
+			m.loading = true
+			return m, m.loadPods(msg.workload)
+		}
+		return m, nil
+
+	case eventWatchStartedMsg:
+		if msg.err != nil {
+			// Events still refresh on every tick without a watch
+			return m, nil
+		}
+		if msg.warnings {
+			if m.view != ViewWarnings || m.warningsView.WatchNamespace() != msg.namespace {
+				msg.watch.Stop()
+				return m, nil
+			}
+			m.stopWarningsWatch()
+			m.warningsWatch = msg.watch
+			return m, waitForEvent(msg.watch)
+		}
+		if m.view != ViewDashboard || m.pod == nil || m.pod.Name != msg.podName {
+			msg.watch.Stop()
+			return m, nil
+		}
+		m.stopEventWatch()
+		m.eventWatch = msg.watch
+		return m, waitForEvent(msg.watch)
+
+	case eventReceivedMsg:
+		switch msg.watch {
+		case m.eventWatch:
+			m.dashboard.AddEvent(msg.event)
+		case m.warningsWatch:
+			m.warningsView.AddEvent(msg.event)
+		default:
+			return m, nil
+		}
+		return m, waitForEvent(msg.watch)
+
+	case eventWatchClosedMsg:
+		// The server ends watches periodically; the next tick starts a new one
+		switch msg.watch {
+		case m.eventWatch:
+			m.eventWatch = nil
+		case m.warningsWatch:
+			m.warningsWatch = nil
+		}
+		return m, nil
+
 	case views.DeletePodRequest:
 		return m, m.deletePod(msg.Namespace, msg.PodName)
 
+	case components.LoadManifestMsg:
+		return m, m.loadManifest(msg.Ref)
+
+	case components.LoadEnvMsg:
+		return m, m.loadEnv(msg.Namespace, msg.Pod)
+
+	case views.ViewConfigDataRequest:
+		return m, m.loadConfigData(msg.Ref)
+
+	case configDataMsg:
+		m.configDataLoaded(msg)
+		return m, nil
+
+	case components.SecretAccessMsg:
+		m.secretAccessed(msg)
+		return m, nil
+
+	case views.EditResourceRequest:
+		return m, m.editResource(msg.Ref)
+
+	case editOpenedMsg:
+		return m, m.editOpened(msg)
+
+	case editClosedMsg:
+		return m, m.editClosed(msg)
+
+	case editDryRunMsg:
+		m.editDryRunDone(msg)
+		return m, nil
+
+	case editAppliedMsg:
+		return m, m.editApplied(msg)
+
+	case manifestLoadedMsg:
+		m.dashboard.SetManifestObject(msg.ref, msg.fields, msg.err)
+		return m, nil
+
+	case envLoadedMsg:
+		m.dashboard.SetManifestEnv(msg.namespace, msg.pod, msg.envs, msg.err)
+		return m, nil
+
+	case views.RightSizingRequest:
+		return m, m.checkRightSizing(msg.Request)
+
+	case views.ApplyResourcesRequest:
+		return m, m.applyResources(msg)
+
 	case podDeletedMsg:
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			// Go back to navigator after deletion
-			m.view = ViewNavigator
+			// Go back to where the dashboard was opened from after deletion
+			m.stopEventWatch()
 			m.pod = nil
+			if m.dashboardReturn != ViewNavigator {
+				return m, m.returnFromDashboard()
+			}
+			m.view = ViewNavigator
 			if m.workload != nil {
 				return m, m.loadPods(m.workload)
 			}
@@ -198,6 +749,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case components.WorkloadActionMenuResult:
+		if m.view == ViewNodes {
+			return m, m.handleNodeAction(msg.Item)
+		}
 		workload := m.navigator.SelectedWorkload()
 		if workload == nil {
 			return m, nil
@@ -217,6 +771,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case components.ConfirmResult:
+		if msg.Action == "apply-edit" {
+			if s, ok := msg.Data.(*editSession); ok {
+				if !msg.Confirmed {
+					m.statusMsg = "Not applied; your edit is kept in " + s.path
+					return m, nil
+				}
+				m.statusMsg = "Applying to " + s.target() + "..."
+				return m, m.applyEdit(s)
+			}
+		}
 		// Handle workload restart at app level
 		if msg.Confirmed && msg.Action == "restart" {
 			if workload, ok := msg.Data.(*k8s.WorkloadInfo); ok {
@@ -225,6 +789,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.restartWorkload(workload)
 			}
 		}
+		if node, ok := msg.Data.(string); ok && msg.Confirmed {
+			switch msg.Action {
+			case "cordon-node", "uncordon-node":
+				m.statusMsg = ""
+				return m, m.setNodeUnschedulable(node, msg.Action == "cordon-node")
+			case "drain-node":
+				m.statusMsg = "Draining " + node + "..."
+				return m, m.drainNode(node, 1, 0)
+			}
+		}
 		// Forward other confirm results (exec, port-forward, delete) to dashboard
 		if m.view == ViewDashboard {
 			var cmd tea.Cmd
@@ -242,6 +816,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case views.ApplyResourcesResult:
+		if m.view == ViewDashboard {
+			var cmd tea.Cmd
+			m.dashboard, cmd = m.dashboard.Update(msg)
+			return m, cmd
+		}
+		if msg.Err != nil {
+			m.statusMsg = "Error: " + msg.Err.Error()
+		} else {
+			m.statusMsg = "Resources updated on " + msg.Workload
+		}
+		return m, nil
+
+	case bundleWrittenMsg:
+		switch {
+		case msg.err != nil:
+			m.statusMsg = "Error: " + msg.err.Error()
+		case msg.missing > 0:
+			m.statusMsg = fmt.Sprintf("Bundle written to %s (%d sources missing, see index.json)", msg.path, msg.missing)
+		default:
+			m.statusMsg = "Bundle written to " + msg.path
+		}
+		return m, nil
+
 	case views.DescribeOutputMsg:
 		// Forward describe output to dashboard
 		if m.view == ViewDashboard {
@@ -251,6 +849,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case nodeActionMsg:
+		if msg.err != nil {
+			m.statusMsg = "Error: " + msg.err.Error()
+		} else if msg.action == "cordon" {
+			m.statusMsg = "Cordoned " + msg.node
+		} else {
+			m.statusMsg = "Uncordoned " + msg.node
+		}
+		if m.view == ViewNodes {
+			return m, m.loadNodes()
+		}
+		return m, nil
+
+	case nodeDrainMsg:
+		var cmds []tea.Cmd
+		if m.view == ViewNodes {
+			cmds = append(cmds, m.loadNodes())
+			if m.nodesView.DetailName() == msg.node {
+				cmds = append(cmds, m.loadNodePods(msg.node))
+			}
+		}
+		if msg.err != nil {
+			m.statusMsg = "Error draining " + msg.node + ": " + msg.err.Error()
+			return m, tea.Batch(cmds...)
+		}
+
+		evicted := msg.evicted + len(msg.result.Evicted)
+		summary := drainSummary(msg.result, evicted)
+		switch {
+		case msg.result.Pending() && msg.round < drainMaxRounds:
+			m.statusMsg = "Draining " + msg.node + ": " + summary
+			node, round := msg.node, msg.round+1
+			cmds = append(cmds, tea.Tick(drainRetryInterval, func(time.Time) tea.Msg {
+				return drainRetryMsg{node: node, round: round, evicted: evicted}
+			}))
+		case msg.result.Pending():
+			m.statusMsg = "Stopped draining " + msg.node + ": " + summary
+		case len(msg.result.Failed) > 0:
+			m.statusMsg = "Drain of " + msg.node + " incomplete: " + summary
+		default:
+			m.statusMsg = "Drained " + msg.node + ": " + summary
+		}
+		return m, tea.Batch(cmds...)
+
+	case drainRetryMsg:
+		return m, m.drainNode(msg.node, msg.round, msg.evicted)
+
 	case workloadActionMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -269,10 +914,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		if m.view == ViewDashboard && m.pod != nil {
-			return m, tea.Batch(
+			cmds := []tea.Cmd{
 				m.loadDashboardData(m.pod),
 				m.tickCmd(),
-			)
+			}
+			if m.eventWatch == nil {
+				cmds = append(cmds, m.startEventWatch(m.pod))
+			}
+			if m.metricsProvider != nil && time.Since(m.lastMetricsRange) >= metricsRangeRefresh {
+				cmds = append(cmds, m.loadMetricsRange(m.pod))
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.view == ViewWorkload {
+			if w := m.workloadView.Workload(); w != nil {
+				return m, tea.Batch(m.loadWorkloadEvents(w), m.tickCmd())
+			}
+		}
+		if m.view == ViewTop {
+			return m, tea.Batch(m.loadTop(m.topView.ListNamespace()), m.tickCmd())
+		}
+		if m.view == ViewNodes {
+			cmds := []tea.Cmd{m.loadNodes(), m.tickCmd()}
+			if node := m.nodesView.DetailName(); node != "" {
+				cmds = append(cmds, m.loadNodePods(node))
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.view == ViewWarnings && m.warningsWatch == nil {
+			// Relist to cover anything missed while the watch was down
+			ns := m.warningsView.WatchNamespace()
+			return m, tea.Batch(m.loadWarnings(ns), m.startWarningsWatch(ns), m.tickCmd())
 		}
 		return m, m.tickCmd()
 
@@ -283,6 +955,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Then the edit diff and errors
+		if m.resultViewer.IsVisible() {
+			m.resultViewer, cmd = m.resultViewer.Update(msg)
+			return m, cmd
+		}
+
+		// And an open ConfigMap or Secret
+		if m.dataViewer.IsVisible() {
+			m.dataViewer, cmd = m.dataViewer.Update(msg)
+			return m, cmd
+		}
+
 		// Workload action menu takes priority
 		if m.workloadActionMenu.IsVisible() {
 			m.workloadActionMenu, cmd = m.workloadActionMenu.Update(msg)
@@ -323,6 +1007,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Panel search inputs get every key except ctrl+c
+		if msg.String() != "ctrl+c" {
+			if m.view == ViewDashboard && m.dashboard.IsSearching() {
+				m.dashboard, cmd = m.dashboard.Update(msg)
+				return m, cmd
+			}
+			if m.view == ViewWorkload && m.workloadView.IsSearching() {
+				m.workloadView, cmd = m.workloadView.Update(msg)
+				return m, cmd
+			}
+		}
+
 		// Normal key handling when not searching
 		switch {
 		case key.Matches(msg, m.keys.Quit):
@@ -333,6 +1029,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.help.Toggle()
 			return m, nil
 
+		case key.Matches(msg, m.keys.History):
+			m.showStatusHistory()
+			return m, nil
+
 		case key.Matches(msg, m.keys.Refresh):
 			return m, m.refresh()
 
@@ -344,15 +1044,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Back):
 			// Don't handle back if dashboard has active overlay or is searching - let dashboard handle esc
-			if m.view == ViewDashboard && (m.dashboard.IsLogsSearching() || m.dashboard.HasActiveOverlay()) {
+			if m.view == ViewDashboard && (m.dashboard.IsSearching() || m.dashboard.HasActiveOverlay()) {
 				break // Fall through to dashboard update
 			}
+			if m.view == ViewWorkload && m.workloadView.HasActiveOverlay() {
+				break
+			}
 			return m.handleBack()
 
 		case key.Matches(msg, m.keys.Enter):
-			// Don't handle enter if dashboard has active overlay - let dashboard handle it
-			if m.view == ViewDashboard && m.dashboard.HasActiveOverlay() {
-				break // Fall through to dashboard update
+			// Enter in the dashboard and workload view belongs to the focused panel
+			// (e.g. event details) or an active overlay
+			if m.view == ViewDashboard || m.view == ViewWorkload {
+				break
 			}
 			return m.handleEnter()
 		}
@@ -367,6 +1071,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.navigator.SetMode(components.ModeResourceType)
 					return m, nil
 				}
+				if key.Matches(msg, m.keys.Warnings) && (m.navigator.Mode() == components.ModeWorkloads || m.navigator.Mode() == components.ModePods) {
+					return m, m.openWarnings()
+				}
+				if key.Matches(msg, m.keys.Top) && (m.navigator.Mode() == components.ModeWorkloads || m.navigator.Mode() == components.ModePods) {
+					return m, m.openTop()
+				}
+				if key.Matches(msg, m.keys.Triage) && (m.navigator.Mode() == components.ModeWorkloads || m.navigator.Mode() == components.ModePods) {
+					return m, m.openTriage()
+				}
 				// Scale action (only for scalable resource types)
 				if key.Matches(msg, m.keys.Scale) && m.navigator.Mode() == components.ModeWorkloads {
 					workload := m.navigator.SelectedWorkload()
@@ -384,6 +1097,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 					}
 				}
+				// Events across the workload's owner chain
+				if key.Matches(msg, m.keys.WorkloadEvents) && m.navigator.Mode() == components.ModeWorkloads {
+					if workload := m.navigator.SelectedWorkload(); workload != nil {
+						m.view = ViewWorkload
+						m.workloadView.SetWorkload(workload)
+						m.loading = true
+						return m, m.loadWorkloadEvents(workload)
+					}
+				}
+				// Edit the selected workload or pod in $EDITOR
+				if key.Matches(msg, m.keys.Edit) {
+					if ref, ok := m.selectedObject(); ok {
+						return m, m.editResource(ref)
+					}
+				}
+				// Drift of the selected workload or pod from its manifest
+				if key.Matches(msg, m.keys.Drift) {
+					if ref, ok := m.selectedObject(); ok {
+						return m, m.loadDrift(ref)
+					}
+				}
 				// Restart action
 				if key.Matches(msg, m.keys.Restart) && m.navigator.Mode() == components.ModeWorkloads {
 					workload := m.navigator.SelectedWorkload()
@@ -405,19 +1139,88 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.navigator, cmd = m.navigator.Update(msg)
 		cmds = append(cmds, cmd)
 
+	case ViewWorkload:
+		m.workloadView, cmd = m.workloadView.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewTop:
+		m.topView, cmd = m.topView.Update(msg)
+		cmds = append(cmds, cmd)
+
+		if ns := m.topView.ListNamespace(); ns != m.topNamespace {
+			m.topNamespace = ns
+			m.loading = true
+			cmds = append(cmds, m.loadTop(ns))
+		}
+
+	case ViewTriage:
+		m.triageView, cmd = m.triageView.Update(msg)
+		cmds = append(cmds, cmd)
+
+		if ns := m.triageView.ScanNamespace(); ns != m.triageNamespace {
+			m.triageNamespace = ns
+			m.loading = true
+			cmds = append(cmds, m.loadTriage(ns))
+		}
+
+	case ViewNodes:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.PodActions) {
+			node := m.nodesView.DetailNode()
+			if node == nil {
+				node = m.nodesView.SelectedNode()
+			}
+			if node != nil {
+				m.workloadActionMenu.Show("Node "+node.Name, components.NodeActions(node))
+			}
+			return m, nil
+		}
+		m.nodesView, cmd = m.nodesView.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewWarnings:
+		m.warningsView, cmd = m.warningsView.Update(msg)
+		cmds = append(cmds, cmd)
+
+		// Switching between namespace and cluster scope needs a new list and watch
+		if ns := m.warningsView.WatchNamespace(); ns != m.warningsNamespace {
+			m.warningsNamespace = ns
+			m.stopWarningsWatch()
+			m.loading = true
+			cmds = append(cmds, m.loadWarnings(ns), m.startWarningsWatch(ns))
+		}
+
 	case ViewDashboard:
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.Bundle) && m.pod != nil && !m.dashboard.HasActiveOverlay() {
+			m.statusMsg = "Writing support bundle..."
+			return m, m.writeBundle(m.pod)
+		}
 		m.dashboard, cmd = m.dashboard.Update(msg)
 		cmds = append(cmds, cmd)
 
+		if m.pod != nil && m.metricsProvider != nil && m.dashboard.MetricsRangeWindow() != m.lastMetricsWindow {
+			cmds = append(cmds, m.loadMetricsRange(m.pod))
+		}
+
 		// Check if log state changed and needs refresh
 		if m.pod != nil {
 			currentShowPrevious := m.dashboard.LogsShowPrevious()
+			currentShowHistory := m.dashboard.LogsShowHistory()
+			currentHistoryWindow := m.dashboard.LogsHistoryWindow()
 			currentContainer := m.dashboard.LogsSelectedContainer()
 
-			if currentShowPrevious != m.lastShowPrevious || currentContainer != m.lastLogContainer {
+			historyChanged := currentShowHistory != m.lastShowHistory ||
+				(currentShowHistory && currentHistoryWindow != m.lastHistoryWindow)
+
+			if currentShowPrevious != m.lastShowPrevious || currentContainer != m.lastLogContainer || historyChanged {
 				m.lastShowPrevious = currentShowPrevious
+				m.lastShowHistory = currentShowHistory
+				m.lastHistoryWindow = currentHistoryWindow
 				m.lastLogContainer = currentContainer
-				cmds = append(cmds, m.loadLogsForState(m.pod, currentContainer, currentShowPrevious))
+				if currentShowHistory {
+					cmds = append(cmds, m.loadHistoricalLogs(m.pod, currentContainer, currentHistoryWindow))
+				} else {
+					cmds = append(cmds, m.loadLogsForState(m.pod, currentContainer, currentShowPrevious))
+				}
 			}
 		}
 	}
@@ -457,6 +1260,16 @@ func (m Model) View() string {
 		content = m.navigator.View()
 	case ViewDashboard:
 		content = m.dashboard.View()
+	case ViewWorkload:
+		content = m.workloadView.View()
+	case ViewWarnings:
+		content = m.warningsView.View()
+	case ViewTop:
+		content = m.topView.View()
+	case ViewTriage:
+		content = m.triageView.View()
+	case ViewNodes:
+		content = m.nodesView.View()
 	}
 
 	// Render confirm dialog as overlay (highest priority)
@@ -472,6 +1285,32 @@ func (m Model) View() string {
 		)
 	}
 
+	// Render the edit diff or error as overlay
+	if m.resultViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.resultViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(styles.Background),
+		)
+	}
+
+	// Render an open ConfigMap or Secret as overlay
+	if m.dataViewer.IsVisible() {
+		return lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			m.dataViewer.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(styles.Background),
+		)
+	}
+
 	// Render workload action menu as overlay
 	if m.workloadActionMenu.IsVisible() {
 		return lipgloss.Place(
@@ -508,9 +1347,34 @@ func (m Model) View() string {
 
 func (m *Model) handleBack() (tea.Model, tea.Cmd) {
 	switch m.view {
-	case ViewDashboard:
+	case ViewWorkload:
+		m.view = ViewNavigator
+		return m, nil
+
+	case ViewWarnings:
+		m.stopWarningsWatch()
+		m.view = ViewNavigator
+		return m, nil
+
+	case ViewTop, ViewTriage:
+		m.view = ViewNavigator
+		return m, nil
+
+	case ViewNodes:
+		if m.nodesView.DetailName() != "" {
+			m.nodesView.CloseDetail()
+			return m, nil
+		}
 		m.view = ViewNavigator
+		return m, nil
+
+	case ViewDashboard:
+		m.stopEventWatch()
 		m.pod = nil
+		if m.dashboardReturn != ViewNavigator {
+			return m, m.returnFromDashboard()
+		}
+		m.view = ViewNavigator
 		if m.workload != nil {
 			m.navigator.SetMode(components.ModePods)
 		} else {
@@ -523,6 +1387,7 @@ func (m *Model) handleBack() (tea.Model, tea.Cmd) {
 		case components.ModePods:
 			m.navigator.SetMode(components.ModeWorkloads)
 			m.workload = nil
+			m.startContainer = ""
 			return m, m.loadWorkloads()
 		case components.ModeNamespace:
 			m.navigator.SetMode(components.ModeWorkloads)
@@ -550,22 +1415,11 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 		case components.ModePods:
 			pod := m.navigator.SelectedPod()
 			if pod != nil {
-				m.pod = pod
-				m.view = ViewDashboard
-				m.dashboard.SetPod(pod)
-				m.dashboard.SetBreadcrumb(
-					m.k8sClient.Namespace(),
-					string(m.navigator.ResourceType()),
-					m.workload.Name,
-					pod.Name,
-				)
-				m.dashboard.SetContext(m.k8sClient.Context())
-				m.dashboard.SetNamespace(m.k8sClient.Namespace())
-				m.loading = true
-				return m, tea.Batch(
-					m.loadDashboardData(pod),
-					m.tickCmd(),
-				)
+				cmd := m.openPodDashboard(pod, ViewNavigator, string(m.navigator.ResourceType()), m.workload.Name)
+				if m.startContainer != "" {
+					m.dashboard.SelectLogsContainer(m.startContainer)
+				}
+				return m, cmd
 			}
 
 		case components.ModeNamespace:
@@ -580,16 +1434,137 @@ func (m *Model) handleEnter() (tea.Model, tea.Cmd) {
 
 		case components.ModeResourceType:
 			rt := m.navigator.SelectedResourceType()
+			if rt == k8s.ResourceNodes {
+				// Nodes have their own view; the navigator keeps its workload type
+				m.navigator.SetMode(components.ModeWorkloads)
+				return m, m.openNodes()
+			}
 			m.navigator.SetResourceType(rt)
 			m.config.SetLastResourceType(string(rt))
 			m.navigator.SetMode(components.ModeWorkloads)
 			m.loading = true
 			return m, m.loadWorkloads()
 		}
+
+	case ViewWarnings:
+		if group := m.warningsView.SelectedGroup(); group != nil {
+			m.loading = true
+			return m, m.resolveWarningTarget(group.Object)
+		}
+
+	case ViewTop:
+		if pod := m.topView.SelectedPod(); pod != nil {
+			m.workload = nil
+			return m, m.openPodDashboard(pod, ViewTop, "top")
+		}
+
+	case ViewTriage:
+		if pod := m.triageView.SelectedPod(); pod != nil {
+			m.workload = nil
+			return m, m.openPodDashboard(pod, ViewTriage, "triage")
+		}
+
+	case ViewNodes:
+		if node := m.nodesView.DetailName(); node != "" {
+			if pod := m.nodesView.SelectedPod(); pod != nil {
+				m.workload = nil
+				return m, m.openPodDashboard(pod, ViewNodes, "nodes", node)
+			}
+			return m, nil
+		}
+		if node := m.nodesView.SelectedNode(); node != nil {
+			m.nodesView.OpenNode(node.Name)
+			return m, m.loadNodePods(node.Name)
+		}
 	}
 	return m, nil
 }
 
+// openPodDashboard switches to the dashboard for pod; esc leads back to
+// returnTo. crumbs are shown between the namespace and the pod name.
+func (m *Model) openPodDashboard(pod *k8s.PodInfo, returnTo ViewState, crumbs ...string) tea.Cmd {
+	m.pod = pod
+	m.view = ViewDashboard
+	m.dashboardReturn = returnTo
+	m.dashboard.SetPod(pod)
+	items := append([]string{pod.Namespace}, crumbs...)
+	m.dashboard.SetBreadcrumb(append(items, pod.Name)...)
+	m.dashboard.SetContext(m.k8sClient.Context())
+	m.dashboard.SetNamespace(pod.Namespace)
+	m.loading = true
+	cmds := []tea.Cmd{
+		m.loadDashboardData(pod),
+		m.startEventWatch(pod),
+	}
+	if m.metricsProvider != nil {
+		cmds = append(cmds, m.loadMetricsRange(pod))
+	}
+	return tea.Batch(cmds...)
+}
+
+// openWarnings shows the warnings feed for the current namespace, or the
+// whole cluster if that scope was chosen before.
+func (m *Model) openWarnings() tea.Cmd {
+	m.view = ViewWarnings
+	m.warningsView.SetNamespace(m.k8sClient.Namespace())
+	ns := m.warningsView.WatchNamespace()
+	m.warningsNamespace = ns
+	m.loading = true
+	return tea.Batch(m.loadWarnings(ns), m.startWarningsWatch(ns))
+}
+
+// openTop shows pods of the current namespace, or of all namespaces if
+// that scope was chosen before, by resource usage.
+func (m *Model) openTop() tea.Cmd {
+	m.view = ViewTop
+	m.topView.SetNamespace(m.k8sClient.Namespace())
+	ns := m.topView.ListNamespace()
+	m.topNamespace = ns
+	m.loading = true
+	return m.loadTop(ns)
+}
+
+// openTriage scans the current namespace, or all namespaces if that scope
+// was chosen before, and ranks the pods that need attention. Scans run on
+// open and on refresh only, since each one reads the logs of every
+// unhealthy pod.
+func (m *Model) openTriage() tea.Cmd {
+	m.view = ViewTriage
+	m.triageView.SetNamespace(m.k8sClient.Namespace())
+	ns := m.triageView.ScanNamespace()
+	m.triageNamespace = ns
+	m.loading = true
+	return m.loadTriage(ns)
+}
+
+// openNodes shows the node list, or the node detail that was open before.
+func (m *Model) openNodes() tea.Cmd {
+	m.view = ViewNodes
+	m.loading = true
+	return m.loadNodes()
+}
+
+// returnFromDashboard reopens the view the dashboard was entered from.
+func (m *Model) returnFromDashboard() tea.Cmd {
+	switch m.dashboardReturn {
+	case ViewWarnings:
+		return m.openWarnings()
+	case ViewTop:
+		return m.openTop()
+	case ViewTriage:
+		// Back to the previous ranking; r rescans
+		m.view = ViewTriage
+		return nil
+	case ViewNodes:
+		if node := m.nodesView.DetailName(); node != "" {
+			return tea.Batch(m.openNodes(), m.loadNodePods(node))
+		}
+		return m.openNodes()
+	}
+	m.view = ViewNavigator
+	return nil
+}
+
 func (m *Model) refresh() tea.Cmd {
 	switch m.view {
 	case ViewNavigator:
@@ -598,8 +1573,34 @@ func (m *Model) refresh() tea.Cmd {
 	case ViewDashboard:
 		if m.pod != nil {
 			m.loading = true
+			if ref, ok := m.dashboard.ManifestObject(); ok {
+				return tea.Batch(m.loadDashboardData(m.pod), m.loadManifest(ref))
+			}
+			if m.dashboard.ManifestEnvShown() {
+				return tea.Batch(m.loadDashboardData(m.pod), m.loadEnv(m.pod.Namespace, m.pod.Name))
+			}
 			return m.loadDashboardData(m.pod)
 		}
+	case ViewWorkload:
+		if w := m.workloadView.Workload(); w != nil {
+			m.loading = true
+			return m.loadWorkloadEvents(w)
+		}
+	case ViewWarnings:
+		m.loading = true
+		return m.loadWarnings(m.warningsView.WatchNamespace())
+	case ViewTop:
+		m.loading = true
+		return m.loadTop(m.topView.ListNamespace())
+	case ViewTriage:
+		m.loading = true
+		return m.loadTriage(m.triageView.ScanNamespace())
+	case ViewNodes:
+		m.loading = true
+		if node := m.nodesView.DetailName(); node != "" {
+			return tea.Batch(m.loadNodes(), m.loadNodePods(node))
+		}
+		return m.loadNodes()
 	}
 	return nil
 }
@@ -659,6 +1660,167 @@ func (m *Model) loadPods(workload *k8s.WorkloadInfo) tea.Cmd {
 	}
 }
 
+func (m *Model) loadWorkloadEvents(workload *k8s.WorkloadInfo) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		objects, err := k8s.GetWorkloadObjects(ctx, m.k8sClient.Clientset(), *workload)
+		if err != nil {
+			return workloadEventsMsg{workload: workload.Name, err: err}
+		}
+		events, err := k8s.GetObjectsEvents(ctx, m.k8sClient.Clientset(), workload.Namespace, objects)
+		// Refetched so the health hints follow the rollout
+		info, _ := k8s.GetWorkload(ctx, m.k8sClient.Clientset(), workload.Namespace, workload.Type, workload.Name)
+		return workloadEventsMsg{
+			workload: workload.Name,
+			info:     info,
+			objects:  objects,
+			events:   events,
+			err:      err,
+		}
+	}
+}
+
+// warningsLookback bounds the initial list; the API server keeps events for
+// an hour by default, so this only matters for clusters with a longer TTL.
+const warningsLookback = 24 * time.Hour
+
+func (m *Model) loadWarnings(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		events, err := k8s.GetRecentWarnings(context.Background(), m.k8sClient.Clientset(), namespace, warningsLookback)
+		return warningsLoadedMsg{namespace: namespace, events: events, err: err}
+	}
+}
+
+func (m *Model) loadTop(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		usages, metrics, err := k8s.GetPodUsages(context.Background(), m.k8sClient.Clientset(), m.k8sClient.MetricsClient(), namespace)
+		return topLoadedMsg{namespace: namespace, usages: usages, metrics: metrics, err: err}
+	}
+}
+
+func (m *Model) loadTriage(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		results, err := k8s.TriagePods(context.Background(), m.k8sClient.Clientset(), namespace, m.rules)
+		return triageLoadedMsg{namespace: namespace, results: results, err: err}
+	}
+}
+
+func (m *Model) loadNodes() tea.Cmd {
+	return func() tea.Msg {
+		nodes, err := k8s.ListNodes(context.Background(), m.k8sClient.Clientset(), m.k8sClient.MetricsClient())
+		return nodesLoadedMsg{nodes: nodes, err: err}
+	}
+}
+
+// handleNodeAction asks for confirmation of an action picked from the
+// node menu, or copies its kubectl command.
+func (m *Model) handleNodeAction(item components.WorkloadActionItem) tea.Cmd {
+	node := m.nodesView.DetailNode()
+	if node == nil {
+		node = m.nodesView.SelectedNode()
+	}
+	if node == nil {
+		return nil
+	}
+
+	switch item.Action {
+	case "cordon":
+		m.confirmDialog.Show(
+			"Cordon node",
+			"Stop scheduling new pods on '"+node.Name+"'? Running pods are not affected.",
+			"cordon-node",
+			node.Name,
+		)
+	case "uncordon":
+		m.confirmDialog.Show(
+			"Uncordon node",
+			"Allow new pods to be scheduled on '"+node.Name+"' again?",
+			"uncordon-node",
+			node.Name,
+		)
+	case "drain":
+		m.confirmDialog.Show(
+			"Drain node",
+			fmt.Sprintf("Cordon '%s' and evict its %d pods? DaemonSet, static and unmanaged pods are skipped, PodDisruptionBudgets are honored and emptyDir data is lost.", node.Name, node.PodCount),
+			"drain-node",
+			node.Name,
+		)
+	case "copy":
+		if err := components.CopyToClipboard(item.Command); err == nil {
+			m.statusMsg = "Copied: " + item.Command
+		} else {
+			m.statusMsg = "Copy failed: " + err.Error()
+		}
+	}
+	return nil
+}
+
+func (m *Model) setNodeUnschedulable(node string, unschedulable bool) tea.Cmd {
+	return func() tea.Msg {
+		err := k8s.SetNodeUnschedulable(context.Background(), m.k8sClient.Clientset(), node, unschedulable)
+		action := "uncordon"
+		if unschedulable {
+			action = "cordon"
+		}
+		return nodeActionMsg{node: node, action: action, err: err}
+	}
+}
+
+func (m *Model) drainNode(node string, round, evicted int) tea.Cmd {
+	return func() tea.Msg {
+		result, err := k8s.DrainNode(context.Background(), m.k8sClient.Clientset(), node)
+		return nodeDrainMsg{node: node, round: round, evicted: evicted, result: result, err: err}
+	}
+}
+
+// drainSummary describes the state of a drain for the status bar, naming
+// the first pods whose eviction was blocked or failed.
+func drainSummary(r k8s.DrainResult, evicted int) string {
+	parts := []string{fmt.Sprintf("%d evicted", evicted)}
+	if n := r.Terminating + len(r.Evicted); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d terminating", n))
+	}
+	if len(r.Blocked) > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked by PodDisruptionBudget (%s)", len(r.Blocked), firstNames(r.Blocked, 3)))
+	}
+	if len(r.Failed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed (%s)", len(r.Failed), firstNames(r.Failed, 1)))
+	}
+	if len(r.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", len(r.Skipped)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func firstNames(names []string, n int) string {
+	if len(names) <= n {
+		return strings.Join(names, ", ")
+	}
+	return strings.Join(names[:n], ", ") + fmt.Sprintf(", +%d more", len(names)-n)
+}
+
+func (m *Model) loadNodePods(node string) tea.Cmd {
+	return func() tea.Msg {
+		pods, err := k8s.GetNodePods(context.Background(), m.k8sClient.Clientset(), node)
+		return nodePodsMsg{node: node, pods: pods, err: err}
+	}
+}
+
+func (m *Model) resolveWarningTarget(ref k8s.ObjectReference) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if ref.Kind == "Node" {
+			return warningTargetMsg{node: ref.Name}
+		}
+		if ref.Kind == "Pod" {
+			pod, err := k8s.GetPod(ctx, m.k8sClient.Clientset(), ref.Namespace, ref.Name)
+			return warningTargetMsg{pod: pod, err: err}
+		}
+		workload, err := k8s.ResolveWorkload(ctx, m.k8sClient.Clientset(), ref)
+		return warningTargetMsg{workload: workload, err: err}
+	}
+}
+
 func (m *Model) loadDashboardData(pod *k8s.PodInfo) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -668,7 +1830,7 @@ func (m *Model) loadDashboardData(pod *k8s.PodInfo) tea.Cmd {
 		metrics, _ := k8s.GetPodMetrics(ctx, m.k8sClient.MetricsClient(), pod.Namespace, pod.Name)
 		related, _ := k8s.GetRelatedResources(ctx, m.k8sClient.Clientset(), *pod)
 
-		helpers := k8s.AnalyzePodIssues(pod, events)
+		helpers := m.rules.Analyze(k8s.DiagnosticInput{Pod: pod, Events: events, Logs: logs})
 
 		return dashboardDataMsg{
 			logs:    logs,
@@ -680,6 +1842,76 @@ func (m *Model) loadDashboardData(pod *k8s.PodInfo) tea.Cmd {
 	}
 }
 
+// recordMetrics adds a sample to the session history and hands the pod's
+// recent samples to the metrics panel.
+func (m *Model) recordMetrics(metrics *k8s.PodMetrics) {
+	now := time.Now()
+	m.metricsHistory.Record(metrics, now)
+	if m.pod == nil {
+		return
+	}
+	window := time.Duration(m.config.MetricsHistoryMinutes) * time.Minute
+	m.dashboard.SetMetricsHistory(m.metricsHistory.PodSamples(m.pod, now.Add(-window)), window)
+}
+
+// writeBundle saves a support bundle of pod to the working directory,
+// with the metrics samples recorded for it this session.
+func (m *Model) writeBundle(pod *k8s.PodInfo) tea.Cmd {
+	opts := bundle.DefaultOptions()
+	window := time.Duration(m.config.MetricsHistoryMinutes) * time.Minute
+	// The history is not safe for concurrent use, so it is read here
+	opts.Samples = map[string]map[string][]k8s.MetricSample{
+		pod.Name: m.metricsHistory.PodSamples(pod, time.Now().Add(-window)),
+	}
+
+	return func() tea.Msg {
+		b, err := bundle.Collect(context.Background(), m.k8sClient, m.rules, k8s.ResourcePods, pod.Namespace, pod.Name, opts)
+		if err != nil {
+			return bundleWrittenMsg{err: err}
+		}
+		path := b.FileName()
+		if err := bundle.Save(path, b); err != nil {
+			return bundleWrittenMsg{err: err}
+		}
+		return bundleWrittenMsg{path: path, missing: len(b.Index.Errors)}
+	}
+}
+
+// loadEnv resolves the container environment for the manifest env view.
+func (m *Model) loadEnv(namespace, pod string) tea.Cmd {
+	return func() tea.Msg {
+		envs, err := k8s.GetPodEnv(context.Background(), m.k8sClient.Clientset(), namespace, pod)
+		return envLoadedMsg{namespace: namespace, pod: pod, envs: envs, err: err}
+	}
+}
+
+// loadManifest fetches an object for the raw manifest view.
+func (m *Model) loadManifest(ref k8s.ObjectReference) tea.Cmd {
+	return func() tea.Msg {
+		obj, err := k8s.GetObject(context.Background(), m.k8sClient.Clientset(), ref)
+		if err != nil {
+			return manifestLoadedMsg{ref: ref, err: err}
+		}
+		fields, err := k8s.ManifestFields(obj)
+		return manifestLoadedMsg{ref: ref, fields: fields, err: err}
+	}
+}
+
+// loadMetricsRange queries the metrics provider for the pod over the window
+// selected in the metrics panel.
+func (m *Model) loadMetricsRange(pod *k8s.PodInfo) tea.Cmd {
+	provider := m.metricsProvider
+	window := m.dashboard.MetricsRangeWindow()
+	m.lastMetricsWindow = window
+	m.lastMetricsRange = time.Now()
+
+	return func() tea.Msg {
+		end := time.Now()
+		data, err := provider.PodRangeMetrics(context.Background(), pod.Namespace, pod.Name, end.Add(-window), end)
+		return metricsRangeMsg{namespace: pod.Namespace, podName: pod.Name, window: window, data: data, err: err}
+	}
+}
+
 func (m *Model) loadLogsForState(pod *k8s.PodInfo, container string, previous bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -716,6 +1948,83 @@ func (m *Model) loadLogsForState(pod *k8s.PodInfo, container string, previous bo
 	}
 }
 
+// loadHistoricalLogs queries the configured log provider by workload labels
+// rather than pod name, so lines from pods that were already replaced show up too.
+func (m *Model) loadHistoricalLogs(pod *k8s.PodInfo, container string, window time.Duration) tea.Cmd {
+	provider := m.logProvider
+	labels := k8s.StableLabels(pod.Labels)
+	if m.workload != nil && m.workload.Type != k8s.ResourcePods && len(m.workload.Labels) > 0 {
+		labels = m.workload.Labels
+	}
+	limit := m.config.LogLineLimit
+
+	return func() tea.Msg {
+		if provider == nil {
+			return logsUpdatedMsg{logs: []k8s.LogLine{{Content: "No historical log provider configured", IsError: true}}}
+		}
+
+		end := time.Now()
+		logs, err := provider.QueryLogs(context.Background(), k8s.HistoricalLogQuery{
+			Namespace: pod.Namespace,
+			Labels:    labels,
+			Container: container,
+			Start:     end.Add(-window),
+			End:       end,
+			Limit:     limit,
+		})
+		if err != nil {
+			return logsUpdatedMsg{logs: []k8s.LogLine{{Content: "Error fetching logs from " + provider.Name() + ": " + err.Error(), IsError: true}}}
+		}
+		if len(logs) == 0 {
+			return logsUpdatedMsg{logs: []k8s.LogLine{{Content: "No historical logs found in " + provider.Name()}}}
+		}
+		return logsUpdatedMsg{logs: logs}
+	}
+}
+
+func (m *Model) startEventWatch(pod *k8s.PodInfo) tea.Cmd {
+	return func() tea.Msg {
+		w, err := k8s.WatchEvents(context.Background(), m.k8sClient.Clientset(), pod.Namespace, k8s.EventSelector{
+			Kind: "Pod",
+			Name: pod.Name,
+		})
+		return eventWatchStartedMsg{watch: w, podName: pod.Name, err: err}
+	}
+}
+
+func (m *Model) startWarningsWatch(namespace string) tea.Cmd {
+	return func() tea.Msg {
+		w, err := k8s.WatchEvents(context.Background(), m.k8sClient.Clientset(), namespace, k8s.EventSelector{
+			Type: "Warning",
+		})
+		return eventWatchStartedMsg{watch: w, warnings: true, namespace: namespace, err: err}
+	}
+}
+
+func waitForEvent(w *k8s.EventWatch) tea.Cmd {
+	return func() tea.Msg {
+		event, ok := w.Next()
+		if !ok {
+			return eventWatchClosedMsg{watch: w}
+		}
+		return eventReceivedMsg{watch: w, event: event}
+	}
+}
+
+func (m *Model) stopEventWatch() {
+	if m.eventWatch != nil {
+		m.eventWatch.Stop()
+		m.eventWatch = nil
+	}
+}
+
+func (m *Model) stopWarningsWatch() {
+	if m.warningsWatch != nil {
+		m.warningsWatch.Stop()
+		m.warningsWatch = nil
+	}
+}
+
 func (m *Model) tickCmd() tea.Cmd {
 	return tea.Tick(time.Duration(m.config.RefreshInterval)*time.Second, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -738,6 +2047,29 @@ func (m *Model) deletePod(namespace, podName string) tea.Cmd {
 	}
 }
 
+// checkRightSizing drops recommendations for containers that are not in
+// the workload's pod template, which a patch cannot change.
+func (m *Model) checkRightSizing(req views.ApplyResourcesRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		containers, err := k8s.WorkloadContainers(ctx, m.k8sClient.Clientset(), req.Namespace, req.ResourceType, req.Name)
+		if err != nil {
+			return views.RightSizingResult{Request: req, Err: err}
+		}
+		var skipped []string
+		req.Recommendations, skipped = k8s.TemplateRecommendations(req.Recommendations, containers)
+		return views.RightSizingResult{Request: req, Skipped: skipped}
+	}
+}
+
+func (m *Model) applyResources(req views.ApplyResourcesRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		err := k8s.ApplyResources(ctx, m.k8sClient.Clientset(), req.Namespace, req.ResourceType, req.Name, req.Recommendations)
+		return views.ApplyResourcesResult{Workload: req.Name, Err: err}
+	}
+}
+
 func (m *Model) scaleWorkload(workload *k8s.WorkloadInfo, replicas int32) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/bundle"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/components"
+	"github.com/doganarif/k9sight/internal/ui/keys"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+	"github.com/doganarif/k9sight/internal/ui/views"
+	"sigs.k8s.io/yaml"
+)
+
+// BundleViewer browses a support bundle without a cluster: the bundle's
+// pods and a read-only dashboard for each, fed from the bundle instead of
+// the API. Actions that need the cluster are not offered.
+type BundleViewer struct {
+	bundle    *bundle.Bundle
+	navigator components.Navigator
+	dashboard views.Dashboard
+	statusBar components.StatusBar
+	help      components.HelpPanel
+	keys      keys.KeyMap
+	view      ViewState
+	pod       *bundle.Pod
+	width     int
+	height    int
+	statusMsg string
+
+	// Log state the shown logs were picked for
+	lastShowPrevious bool
+	lastLogContainer string
+}
+
+func NewBundleViewer(b *bundle.Bundle) *BundleViewer {
+	v := &BundleViewer{
+		bundle:    b,
+		navigator: components.NewNavigator(),
+		dashboard: views.NewDashboard(),
+		statusBar: components.NewStatusBar(),
+		help:      components.NewHelpPanel(),
+		keys:      keys.DefaultKeyMap(),
+		view:      ViewNavigator,
+		statusMsg: fmt.Sprintf("Bundle of %s/%s taken %s", strings.ToLower(b.Index.Kind), b.Index.Name, b.Index.Created.Local().Format(time.RFC1123)),
+	}
+
+	pods := make([]k8s.PodInfo, 0, len(b.Pods))
+	for _, p := range b.Pods {
+		pods = append(pods, p.Info)
+	}
+	v.navigator.SetPods(pods)
+	v.navigator.SetMode(components.ModePods)
+
+	// A pod bundle has nothing to choose from
+	if len(b.Pods) == 1 {
+		v.openPod(&b.Pods[0])
+	}
+	return v
+}
+
+func (v BundleViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (v *BundleViewer) openPod(p *bundle.Pod) {
+	v.pod = p
+	v.view = ViewDashboard
+	v.dashboard.SetPod(&p.Info)
+	v.dashboard.SetBreadcrumb(p.Info.Namespace, "bundle", p.Info.Name)
+	v.dashboard.SetContext(v.bundle.Index.Context)
+	v.dashboard.SetNamespace(p.Info.Namespace)
+	v.dashboard.SetLogs(p.Logs)
+	v.dashboard.SetEvents(p.Events)
+	v.dashboard.SetMetrics(p.Metrics)
+	if oldest := oldestSample(p.Samples); !oldest.IsZero() {
+		v.dashboard.SetMetricsHistory(p.Samples, v.bundle.Index.Created.Sub(oldest))
+	}
+	v.dashboard.SetRelated(p.Related)
+	v.dashboard.SetHelpers(p.Helpers)
+	v.lastShowPrevious = false
+	v.lastLogContainer = ""
+}
+
+func oldestSample(samples map[string][]k8s.MetricSample) time.Time {
+	var oldest time.Time
+	for _, s := range samples {
+		if len(s) > 0 && (oldest.IsZero() || s[0].Time.Before(oldest)) {
+			oldest = s[0].Time
+		}
+	}
+	return oldest
+}
+
+func (v BundleViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width = msg.Width
+		v.height = msg.Height
+		v.navigator.SetSize(msg.Width, msg.Height-2)
+		v.dashboard.SetSize(msg.Width, msg.Height-2)
+		v.statusBar.SetWidth(msg.Width)
+		v.help.SetSize(msg.Width, msg.Height)
+		return v, nil
+
+	// Requests that would change the cluster are dropped
+	case views.DeletePodRequest, views.ApplyResourcesRequest, views.EditResourceRequest, views.ViewConfigDataRequest, views.RightSizingRequest, components.ShowRightSizingMsg:
+		v.statusMsg = "Not available in a bundle"
+		return v, nil
+
+	case components.LoadManifestMsg:
+		fields, err := v.manifest(msg.Ref)
+		v.dashboard.SetManifestObject(msg.Ref, fields, err)
+		return v, nil
+
+	case components.LoadEnvMsg:
+		// ConfigMaps and Secrets are not collected
+		v.dashboard.SetManifestEnv(msg.Namespace, msg.Pod, nil, fmt.Errorf("the environment is not in the bundle"))
+		return v, nil
+
+	case tea.KeyMsg:
+		if v.help.IsVisible() {
+			if msg.String() == "?" || msg.String() == "esc" {
+				v.help.Hide()
+			}
+			return v, nil
+		}
+
+		searching := (v.view == ViewNavigator && v.navigator.IsSearching()) ||
+			(v.view == ViewDashboard && v.dashboard.IsSearching())
+		if searching && msg.String() != "ctrl+c" {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, v.keys.Quit):
+			return v, tea.Quit
+
+		case key.Matches(msg, v.keys.Help) && v.view == ViewNavigator:
+			v.help.Toggle()
+			return v, nil
+
+		case key.Matches(msg, v.keys.PodActions), key.Matches(msg, v.keys.Bundle), key.Matches(msg, v.keys.Refresh):
+			if v.view == ViewDashboard && !v.dashboard.HasActiveOverlay() {
+				v.statusMsg = "Not available in a bundle"
+				return v, nil
+			}
+
+		case key.Matches(msg, v.keys.Back):
+			if v.view == ViewDashboard && !v.dashboard.HasActiveOverlay() && len(v.bundle.Pods) > 1 {
+				v.view = ViewNavigator
+				v.pod = nil
+				return v, nil
+			}
+
+		case key.Matches(msg, v.keys.Enter):
+			if v.view == ViewNavigator {
+				if pod := v.navigator.SelectedPod(); pod != nil {
+					for i := range v.bundle.Pods {
+						if v.bundle.Pods[i].Info.Name == pod.Name {
+							v.openPod(&v.bundle.Pods[i])
+						}
+					}
+				}
+				return v, nil
+			}
+		}
+	}
+
+	switch v.view {
+	case ViewNavigator:
+		v.navigator, cmd = v.navigator.Update(msg)
+	case ViewDashboard:
+		v.dashboard, cmd = v.dashboard.Update(msg)
+		v.syncLogs()
+	}
+	return v, cmd
+}
+
+// manifest reads an object's YAML from the bundle, which holds the pods and
+// their owners but not related Services or ConfigMaps.
+func (v BundleViewer) manifest(ref k8s.ObjectReference) (map[string]interface{}, error) {
+	var data []byte
+	for _, p := range v.bundle.Pods {
+		if ref.Kind == "Pod" && p.Info.Name == ref.Name {
+			data = p.YAML
+		}
+	}
+	for _, o := range v.bundle.Owners {
+		if o.Kind == ref.Kind && o.Name == ref.Name {
+			data = o.YAML
+		}
+	}
+	if data == nil {
+		return nil, fmt.Errorf("not in the bundle")
+	}
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// syncLogs swaps in the previous run's logs when the logs panel asks for
+// them, as the app would fetch them.
+func (v *BundleViewer) syncLogs() {
+	if v.pod == nil {
+		return
+	}
+	showPrevious := v.dashboard.LogsShowPrevious()
+	container := v.dashboard.LogsSelectedContainer()
+	if showPrevious == v.lastShowPrevious && container == v.lastLogContainer {
+		return
+	}
+	v.lastShowPrevious = showPrevious
+	v.lastLogContainer = container
+
+	if !showPrevious {
+		v.dashboard.SetLogs(v.pod.Logs)
+		return
+	}
+	var logs []k8s.LogLine
+	for _, c := range v.pod.Info.Containers {
+		if container == "" || c.Name == container {
+			logs = append(logs, v.pod.PreviousLogs[c.Name]...)
+		}
+	}
+	v.dashboard.SetLogs(logs)
+}
+
+func (v BundleViewer) View() string {
+	v.statusBar.SetContext(v.bundle.Index.Context)
+	v.statusBar.SetNamespace(v.bundle.Index.Namespace)
+	v.statusBar.SetResource("bundle")
+	footerLine := v.statusBar.View()
+	if v.statusMsg != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+		footerLine += "  " + statusStyle.Render(v.statusMsg)
+	}
+	footer := footerLine + "\n" + v.help.ShortHelp() + "  " + styles.Credit()
+
+	if v.help.IsVisible() {
+		return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, v.help.View(),
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(styles.Background))
+	}
+
+	var content string
+	switch v.view {
+	case ViewNavigator:
+		content = v.navigator.View()
+	case ViewDashboard:
+		content = v.dashboard.View()
+	}
+	return lipgloss.NewStyle().Height(v.height-3).Render(content) + "\n" + footer
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/components"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// maxStatusHistory bounds the status history kept for the session.
+const maxStatusHistory = 200
+
+type statusEntry struct {
+	time time.Time
+	text string
+}
+
+type configDataMsg struct {
+	ref  k8s.ObjectReference
+	data *k8s.ConfigData
+	err  error
+}
+
+// loadConfigData reads a ConfigMap or Secret for the data viewer.
+func (m *Model) loadConfigData(ref k8s.ObjectReference) tea.Cmd {
+	m.statusMsg = "Opening " + strings.ToLower(ref.String()) + "..."
+	return func() tea.Msg {
+		ctx := context.Background()
+		var data *k8s.ConfigData
+		var err error
+		if ref.Kind == "Secret" {
+			data, err = k8s.GetSecretData(ctx, m.k8sClient.Clientset(), ref.Namespace, ref.Name)
+		} else {
+			data, err = k8s.GetConfigMapData(ctx, m.k8sClient.Clientset(), ref.Namespace, ref.Name)
+		}
+		return configDataMsg{ref: ref, data: data, err: err}
+	}
+}
+
+func (m *Model) configDataLoaded(msg configDataMsg) {
+	if msg.err != nil {
+		m.statusMsg = "Could not open " + strings.ToLower(msg.ref.String()) + ": " + msg.err.Error()
+		return
+	}
+	m.statusMsg = ""
+	m.dataViewer.Show(msg.data, m.width-4, m.height-4)
+}
+
+// recordStatus shows a status message and keeps it in the status history,
+// for things worth looking back on such as revealed secrets.
+func (m *Model) recordStatus(text string) {
+	m.statusMsg = text
+	m.statusHistory = append(m.statusHistory, statusEntry{time: time.Now(), text: text})
+	if len(m.statusHistory) > maxStatusHistory {
+		m.statusHistory = m.statusHistory[len(m.statusHistory)-maxStatusHistory:]
+	}
+}
+
+func (m *Model) secretAccessed(msg components.SecretAccessMsg) {
+	m.recordStatus(strings.ToUpper(msg.Action[:1]) + msg.Action[1:] + " " + msg.What)
+}
+
+// showStatusHistory lists the recorded status messages, newest first.
+func (m *Model) showStatusHistory() {
+	var b strings.Builder
+	if len(m.statusHistory) == 0 {
+		b.WriteString(styles.StatusMuted.Render("Nothing recorded yet. Revealed and copied secret values are listed here."))
+	}
+	for i := len(m.statusHistory) - 1; i >= 0; i-- {
+		e := m.statusHistory[i]
+		b.WriteString(fmt.Sprintf("%s  %s\n", styles.LogTimestamp.Render(e.time.Format("15:04:05")), e.text))
+	}
+	m.resultViewer.Show("Status history", b.String(), m.width-4, m.height-4)
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/components"
+)
+
+type driftScannedMsg struct {
+	namespace    string
+	resourceType k8s.ResourceType
+	drift        map[string]int
+	err          error
+}
+
+type driftMsg struct {
+	ref    k8s.ObjectReference
+	report *k8s.DriftReport
+	err    error
+}
+
+// localManifests reads the --manifests path, if any, so files edited
+// while k9sight runs are picked up on the next check.
+func (m *Model) localManifests() ([]k8s.Manifest, error) {
+	if m.manifestsPath == "" {
+		return nil, nil
+	}
+	manifests, err := k8s.LoadManifests(m.manifestsPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifests: %w", err)
+	}
+	return manifests, nil
+}
+
+// scanDrift counts the drifted fields of the listed workloads for the
+// navigator marker.
+func (m *Model) scanDrift() tea.Cmd {
+	ns, rt := m.k8sClient.Namespace(), m.navigator.ResourceType()
+	return func() tea.Msg {
+		manifests, err := m.localManifests()
+		if err != nil {
+			return driftScannedMsg{namespace: ns, resourceType: rt, err: err}
+		}
+		reports, err := k8s.ScanDrift(context.Background(), m.k8sClient.Clientset(), ns, rt, manifests)
+		if err != nil {
+			return driftScannedMsg{namespace: ns, resourceType: rt, err: err}
+		}
+		drift := make(map[string]int)
+		for name, r := range reports {
+			if len(r.Fields) > 0 {
+				drift[name] = len(r.Fields)
+			}
+		}
+		return driftScannedMsg{namespace: ns, resourceType: rt, drift: drift}
+	}
+}
+
+func (m *Model) driftScanned(msg driftScannedMsg) {
+	// The list moved on to another namespace or type meanwhile
+	if msg.namespace != m.k8sClient.Namespace() || msg.resourceType != m.navigator.ResourceType() {
+		return
+	}
+	if msg.err != nil {
+		m.statusMsg = "Drift check failed: " + msg.err.Error()
+	}
+	m.navigator.SetDrift(msg.drift)
+}
+
+// loadDrift compares the live object ref names with its desired state.
+func (m *Model) loadDrift(ref k8s.ObjectReference) tea.Cmd {
+	m.statusMsg = "Checking " + strings.ToLower(ref.String()) + " for drift..."
+	return func() tea.Msg {
+		obj, err := k8s.GetObject(context.Background(), m.k8sClient.Clientset(), ref)
+		if err != nil {
+			return driftMsg{ref: ref, err: err}
+		}
+		live, err := k8s.ManifestFields(obj)
+		if err != nil {
+			return driftMsg{ref: ref, err: err}
+		}
+		manifests, err := m.localManifests()
+		if err != nil {
+			return driftMsg{ref: ref, err: err}
+		}
+		report, ok, err := k8s.ObjectDrift(ref, live, manifests)
+		if err == nil && !ok {
+			err = fmt.Errorf("no local manifest and no %s annotation to compare with", k8s.LastAppliedAnnotation)
+		}
+		return driftMsg{ref: ref, report: report, err: err}
+	}
+}
+
+func (m *Model) driftLoaded(msg driftMsg) {
+	target := strings.ToLower(msg.ref.String())
+	switch {
+	case msg.err != nil:
+		m.statusMsg = "Drift of " + target + ": " + msg.err.Error()
+	case len(msg.report.Fields) == 0:
+		m.statusMsg = target + " matches " + msg.report.Source
+	default:
+		m.statusMsg = ""
+		title := fmt.Sprintf("Drift of %s (%d fields)", target, len(msg.report.Fields))
+		m.resultViewer.Show(title, components.FormatDrift(msg.report), m.width-4, m.height-4)
+	}
+}
@@ -0,0 +1,241 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/components"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// editSession follows one edit from the editor to the apply. The edited
+// file is kept until the change is applied or dropped unchanged, so
+// nothing typed is lost when the server rejects it.
+type editSession struct {
+	ref      k8s.ObjectReference
+	path     string
+	original []byte
+	live     string // manifest the edit started from, for the diff
+	edited   []byte
+
+	// Strategic merge patch from live to edited, as kubectl edit sends
+	patch []byte
+}
+
+func (s *editSession) target() string {
+	return strings.ToLower(s.ref.String())
+}
+
+type editOpenedMsg struct {
+	session *editSession
+	err     error
+}
+
+type editClosedMsg struct {
+	session *editSession
+	err     error
+}
+
+type editDryRunMsg struct {
+	session *editSession
+	diff    []k8s.DiffLine
+	err     error
+}
+
+type editAppliedMsg struct {
+	session *editSession
+	err     error
+}
+
+// editResource writes the live manifest of ref, without status and
+// managedFields, to a temporary file for the editor.
+func (m *Model) editResource(ref k8s.ObjectReference) tea.Cmd {
+	m.statusMsg = "Opening " + strings.ToLower(ref.String()) + "..."
+	return func() tea.Msg {
+		obj, err := k8s.GetObject(context.Background(), m.k8sClient.Clientset(), ref)
+		if err != nil {
+			return editOpenedMsg{err: err}
+		}
+		fields, err := k8s.ManifestFields(obj)
+		if err != nil {
+			return editOpenedMsg{err: err}
+		}
+		live, err := k8s.FormatManifest(fields, k8s.ManifestYAML, true)
+		if err != nil {
+			return editOpenedMsg{err: err}
+		}
+
+		s := &editSession{ref: ref, live: live}
+		s.original = []byte(fmt.Sprintf("# Editing %s in %s. Save and quit to see a server-side dry-run\n"+
+			"# diff before anything is applied; quit without changes to cancel.\n", s.target(), ref.Namespace) + live)
+
+		f, err := os.CreateTemp("", fmt.Sprintf("k9sight-%s-%s-*.yaml", strings.ToLower(ref.Kind), ref.Name))
+		if err != nil {
+			return editOpenedMsg{err: err}
+		}
+		s.path = f.Name()
+		if _, err := f.Write(s.original); err != nil {
+			f.Close()
+			return editOpenedMsg{err: err}
+		}
+		return editOpenedMsg{session: s, err: f.Close()}
+	}
+}
+
+// selectedObject returns the workload or pod selected in the navigator.
+func (m *Model) selectedObject() (k8s.ObjectReference, bool) {
+	switch m.navigator.Mode() {
+	case components.ModeWorkloads:
+		if w := m.navigator.SelectedWorkload(); w != nil {
+			return k8s.ObjectReference{Kind: w.Type.Kind(), Namespace: w.Namespace, Name: w.Name}, true
+		}
+	case components.ModePods:
+		if p := m.navigator.SelectedPod(); p != nil {
+			return k8s.ObjectReference{Kind: "Pod", Namespace: p.Namespace, Name: p.Name}, true
+		}
+	}
+	return k8s.ObjectReference{}, false
+}
+
+// editorCommand opens path in $KUBE_EDITOR or $EDITOR, like kubectl edit,
+// falling back to vi.
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// openEditor suspends the TUI while the editor runs, as exec does.
+func (m *Model) openEditor(s *editSession) tea.Cmd {
+	return tea.ExecProcess(editorCommand(s.path), func(err error) tea.Msg {
+		return editClosedMsg{session: s, err: err}
+	})
+}
+
+// dryRunEdit patches the object with the difference between the manifest
+// the edit started from and the edited one, as kubectl edit does, so
+// removed fields are deleted, and diffs what the server would store
+// against the starting manifest.
+func (m *Model) dryRunEdit(s *editSession) tea.Cmd {
+	return func() tea.Msg {
+		patch, err := k8s.EditPatch(s.ref.Kind, []byte(s.live), s.edited)
+		if err != nil {
+			return editDryRunMsg{session: s, err: err}
+		}
+		s.patch = patch
+		obj, err := k8s.PatchObject(context.Background(), m.k8sClient.Clientset(), s.ref, patch, true)
+		if err != nil {
+			return editDryRunMsg{session: s, err: err}
+		}
+
+		fields, err := k8s.ManifestFields(obj)
+		if err != nil {
+			return editDryRunMsg{session: s, err: err}
+		}
+		result, err := k8s.FormatManifest(fields, k8s.ManifestYAML, true)
+		if err != nil {
+			return editDryRunMsg{session: s, err: err}
+		}
+		return editDryRunMsg{session: s, diff: k8s.UnifiedDiff(s.live, result, diffContext)}
+	}
+}
+
+func (m *Model) applyEdit(s *editSession) tea.Cmd {
+	return func() tea.Msg {
+		_, err := k8s.PatchObject(context.Background(), m.k8sClient.Clientset(), s.ref, s.patch, false)
+		return editAppliedMsg{session: s, err: err}
+	}
+}
+
+func (m *Model) editOpened(msg editOpenedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.statusMsg = "Edit failed: " + msg.err.Error()
+		return nil
+	}
+	m.statusMsg = ""
+	return m.openEditor(msg.session)
+}
+
+func (m *Model) editClosed(msg editClosedMsg) tea.Cmd {
+	s := msg.session
+	if msg.err != nil {
+		m.statusMsg = fmt.Sprintf("Editor failed: %v (file kept in %s)", msg.err, s.path)
+		return nil
+	}
+	edited, err := os.ReadFile(s.path)
+	if err != nil {
+		m.statusMsg = "Edit failed: " + err.Error()
+		return nil
+	}
+	if bytes.Equal(edited, s.original) {
+		os.Remove(s.path)
+		m.statusMsg = "Edit cancelled, nothing changed"
+		return nil
+	}
+	s.edited = edited
+	s.patch = nil
+	m.statusMsg = "Dry-running the change on the server..."
+	return m.dryRunEdit(s)
+}
+
+// editDryRunDone shows the diff to confirm, or why the server refused it.
+func (m *Model) editDryRunDone(msg editDryRunMsg) {
+	s := msg.session
+	m.statusMsg = ""
+	if msg.err != nil {
+		m.resultViewer.Show("Edit of "+s.target()+" rejected", editErrorReport(s, msg.err), m.width-4, m.height-4)
+		return
+	}
+	if len(msg.diff) == 0 {
+		// The text differs from what was opened, so the edit is kept
+		m.statusMsg = "No changes: the server would store " + s.target() + " as it is (edit kept in " + s.path + ")"
+		return
+	}
+
+	m.resultViewer.ShowConfirm("Diff of "+s.target()+" (server-side dry run)", components.FormatDiff(msg.diff),
+		"Apply to "+s.target()+"?", m.width-4, m.height-4, "apply-edit", s)
+}
+
+func (m *Model) editApplied(msg editAppliedMsg) tea.Cmd {
+	s := msg.session
+	if msg.err != nil {
+		m.resultViewer.Show("Apply of "+s.target()+" failed", editErrorReport(s, msg.err), m.width-4, m.height-4)
+		return nil
+	}
+	os.Remove(s.path)
+	m.statusMsg = "Applied " + s.target()
+	return m.refresh()
+}
+
+// editErrorReport explains why the server refused an edit and where the
+// edited file is.
+func editErrorReport(s *editSession, err error) string {
+	var b strings.Builder
+	switch {
+	case apierrors.IsConflict(err):
+		b.WriteString(s.target() + " changed on the server since the edit started.\n")
+		b.WriteString("Edit it again to start from the current version.\n")
+	case apierrors.IsInvalid(err):
+		b.WriteString("The server rejected the change as invalid:\n")
+		b.WriteString(err.Error() + "\n")
+	default:
+		b.WriteString(err.Error() + "\n")
+	}
+	b.WriteString("\nYour edit is kept in " + s.path + "\n")
+	return b.String()
+}
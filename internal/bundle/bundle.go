@@ -0,0 +1,385 @@
+// Package bundle writes and reads incident support bundles: a tar.gz
+// snapshot of a pod or workload with everything the dashboard shows, for
+// attaching to a ticket and browsing later without cluster access.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/doganarif/k9sight/internal/k8s"
+)
+
+// Version is the layout version recorded in the index.
+const Version = 1
+
+// IndexFile sits at the top level of every bundle and lists its contents.
+const IndexFile = "index.json"
+
+// logTimeLayout matches the kubelet's timestamps so logs read back with
+// k8s.ParseLogs.
+const logTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Bundle is a snapshot of a pod, or of a workload and its pods.
+type Bundle struct {
+	Index Index
+
+	// Workload is set when the bundle was taken for a workload
+	Workload *Workload
+
+	// Owners holds the redacted YAML of the target workload and of the
+	// pods' owner chains
+	Owners []Object
+
+	Pods []Pod
+}
+
+// Index is the manifest of a bundle.
+type Index struct {
+	Version   int       `json:"version"`
+	Created   time.Time `json:"created"`
+	Context   string    `json:"context,omitempty"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Pods      []string  `json:"pods"`
+	Owners    []Owner   `json:"owners,omitempty"`
+	Files     []File    `json:"files"`
+
+	// Errors lists what could not be collected, e.g. missing metrics-server
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Owner locates the YAML of an object of the owner chain, keeping its kind
+// as the API spells it.
+type Owner struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type File struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+type Workload struct {
+	Info     k8s.WorkloadInfo  `json:"info"`
+	Findings []k8s.DebugHelper `json:"findings,omitempty"`
+}
+
+// Object is the YAML of an API object.
+type Object struct {
+	Kind string
+	Name string
+	YAML []byte
+}
+
+// Pod is everything collected for one pod. Logs hold the current run of
+// every container; PreviousLogs the run before a restart, by container.
+type Pod struct {
+	Info         k8s.PodInfo
+	YAML         []byte
+	Logs         []k8s.LogLine
+	PreviousLogs map[string][]k8s.LogLine
+	Events       []k8s.EventInfo
+	Describe     string
+	Metrics      *k8s.PodMetrics
+	Samples      map[string][]k8s.MetricSample
+	Related      *k8s.RelatedResources
+	Helpers      []k8s.DebugHelper
+}
+
+type podMetrics struct {
+	Current *k8s.PodMetrics               `json:"current,omitempty"`
+	Samples map[string][]k8s.MetricSample `json:"samples,omitempty"`
+}
+
+// FileName is a default name for the bundle's archive.
+func (b *Bundle) FileName() string {
+	return fmt.Sprintf("k9sight-%s-%s-%s-%s.tar.gz", b.Index.Namespace, strings.ToLower(b.Index.Kind), b.Index.Name,
+		b.Index.Created.UTC().Format("20060102-150405"))
+}
+
+// Write writes b as a gzipped tar with the index first.
+func Write(w io.Writer, b *Bundle) error {
+	var files []archiveFile
+	add := func(name, description string, data []byte) {
+		files = append(files, archiveFile{name: name, description: description, data: data})
+	}
+	addJSON := func(name, description string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", name, err)
+		}
+		add(name, description, data)
+		return nil
+	}
+
+	if b.Workload != nil {
+		if err := addJSON("workload.json", "workload status and rollout findings", b.Workload); err != nil {
+			return err
+		}
+	}
+	for _, o := range b.Owners {
+		add(ownerPath(o), o.Kind+" "+o.Name+" (secrets redacted)", o.YAML)
+	}
+
+	for i := range b.Pods {
+		p := &b.Pods[i]
+		dir := "pods/" + p.Info.Name + "/"
+		if err := addJSON(dir+"pod.json", "pod status as shown in the dashboard", p.Info); err != nil {
+			return err
+		}
+		if len(p.YAML) > 0 {
+			add(dir+"pod.yaml", "pod manifest (secrets redacted)", p.YAML)
+		}
+		if p.Describe != "" {
+			add(dir+"describe.txt", "kubectl describe output (secrets redacted)", []byte(p.Describe))
+		}
+		for _, c := range p.Info.Containers {
+			add(dir+"logs/"+c.Name+".log", "logs of container "+c.Name, formatLogs(p.Logs, c.Name))
+			if prev, ok := p.PreviousLogs[c.Name]; ok {
+				add(dir+"logs/"+c.Name+".previous.log", "logs of the previous run of container "+c.Name, formatLogs(prev, c.Name))
+			}
+		}
+		if err := addJSON(dir+"events.json", "events of the pod", p.Events); err != nil {
+			return err
+		}
+		if err := addJSON(dir+"metrics.json", "current usage and recorded samples", podMetrics{Current: p.Metrics, Samples: p.Samples}); err != nil {
+			return err
+		}
+		if err := addJSON(dir+"related.json", "owner, services, ingresses and referenced ConfigMap and Secret names", p.Related); err != nil {
+			return err
+		}
+		if err := addJSON(dir+"findings.json", "diagnostic findings", p.Helpers); err != nil {
+			return err
+		}
+	}
+
+	index := b.Index
+	index.Version = Version
+	index.Pods = nil
+	for _, p := range b.Pods {
+		index.Pods = append(index.Pods, p.Info.Name)
+	}
+	index.Owners = nil
+	for _, o := range b.Owners {
+		index.Owners = append(index.Owners, Owner{Kind: o.Kind, Name: o.Name, Path: ownerPath(o)})
+	}
+	index.Files = nil
+	for _, f := range files {
+		index.Files = append(index.Files, File{Path: f.name, Description: f.description})
+	}
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, f := range append([]archiveFile{{name: IndexFile, data: indexData}}, files...) {
+		hdr := &tar.Header{
+			Name:    f.name,
+			Mode:    0644,
+			Size:    int64(len(f.data)),
+			ModTime: b.Index.Created,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type archiveFile struct {
+	name        string
+	description string
+	data        []byte
+}
+
+// ownerKind restores the API spelling of a lowercased owner kind.
+func ownerKind(lower string) string {
+	for _, kind := range []string{"ReplicaSet", "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob"} {
+		if strings.EqualFold(kind, lower) {
+			return kind
+		}
+	}
+	return lower
+}
+
+func ownerPath(o Object) string {
+	return "owners/" + strings.ToLower(o.Kind) + "-" + o.Name + ".yaml"
+}
+
+func formatLogs(logs []k8s.LogLine, container string) []byte {
+	var b bytes.Buffer
+	for _, l := range logs {
+		if l.Container != container {
+			continue
+		}
+		if !l.Timestamp.IsZero() {
+			b.WriteString(l.Timestamp.UTC().Format(logTimeLayout) + " ")
+		}
+		b.WriteString(l.Content + "\n")
+	}
+	return b.Bytes()
+}
+
+// Read loads a bundle written by Write.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer gz.Close()
+
+	contents := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+		contents[path.Clean(hdr.Name)] = data
+	}
+
+	b := &Bundle{}
+	data, ok := contents[IndexFile]
+	if !ok {
+		return nil, fmt.Errorf("not a bundle: %s is missing", IndexFile)
+	}
+	if err := json.Unmarshal(data, &b.Index); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", IndexFile, err)
+	}
+	if b.Index.Version > Version {
+		return nil, fmt.Errorf("bundle version %d is newer than this k9sight supports (%d)", b.Index.Version, Version)
+	}
+
+	decode := func(name string, v interface{}) error {
+		data, ok := contents[name]
+		if !ok {
+			return nil
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("reading %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if _, ok := contents["workload.json"]; ok {
+		b.Workload = &Workload{}
+		if err := decode("workload.json", b.Workload); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range b.Index.Owners {
+		b.Owners = append(b.Owners, Object{Kind: o.Kind, Name: o.Name, YAML: contents[o.Path]})
+	}
+	if len(b.Index.Owners) == 0 {
+		// Bundles without owners in the index only have the lowercased
+		// kind in the file name
+		var owners []string
+		for name := range contents {
+			if strings.HasPrefix(name, "owners/") {
+				owners = append(owners, name)
+			}
+		}
+		sort.Strings(owners)
+		for _, name := range owners {
+			kind, objName, _ := strings.Cut(strings.TrimSuffix(path.Base(name), ".yaml"), "-")
+			b.Owners = append(b.Owners, Object{Kind: ownerKind(kind), Name: objName, YAML: contents[name]})
+		}
+	}
+
+	for _, name := range b.Index.Pods {
+		dir := "pods/" + name + "/"
+		p := Pod{
+			YAML:     contents[dir+"pod.yaml"],
+			Describe: string(contents[dir+"describe.txt"]),
+		}
+		var metrics podMetrics
+		for file, v := range map[string]interface{}{
+			"pod.json":      &p.Info,
+			"events.json":   &p.Events,
+			"metrics.json":  &metrics,
+			"related.json":  &p.Related,
+			"findings.json": &p.Helpers,
+		} {
+			if err := decode(dir+file, v); err != nil {
+				return nil, err
+			}
+		}
+		p.Metrics, p.Samples = metrics.Current, metrics.Samples
+		if p.Info.Name == "" {
+			return nil, fmt.Errorf("bundle lists pod %s but has no %spod.json", name, dir)
+		}
+
+		for _, c := range p.Info.Containers {
+			logs, err := k8s.ParseLogs(bytes.NewReader(contents[dir+"logs/"+c.Name+".log"]), c.Name)
+			if err != nil {
+				return nil, err
+			}
+			p.Logs = append(p.Logs, logs...)
+			if data, ok := contents[dir+"logs/"+c.Name+".previous.log"]; ok {
+				prev, err := k8s.ParseLogs(bytes.NewReader(data), c.Name)
+				if err != nil {
+					return nil, err
+				}
+				if p.PreviousLogs == nil {
+					p.PreviousLogs = make(map[string][]k8s.LogLine)
+				}
+				p.PreviousLogs[c.Name] = prev
+			}
+		}
+		sort.SliceStable(p.Logs, func(i, j int) bool { return p.Logs[i].Timestamp.Before(p.Logs[j].Timestamp) })
+		b.Pods = append(b.Pods, p)
+	}
+	return b, nil
+}
+
+// Load reads the bundle at path.
+func Load(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Save writes b to a file at path.
+func Save(path string, b *Bundle) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,196 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/doganarif/k9sight/internal/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// maxOwnerDepth bounds the owner chain walk, e.g. Pod, Job, CronJob.
+const maxOwnerDepth = 5
+
+// Options controls what is collected per pod.
+type Options struct {
+	// LogLines is the tail read per container, for the current and the
+	// previous run
+	LogLines int64
+
+	// Samples are metrics samples recorded before the bundle was taken, by
+	// pod name and then container. Optional.
+	Samples map[string]map[string][]k8s.MetricSample
+}
+
+func DefaultOptions() Options {
+	return Options{LogLines: 1000}
+}
+
+// Collect snapshots a pod, or a workload and all its pods. Every source
+// except the target itself is optional; what fails is listed in
+// Index.Errors so an incomplete bundle is still useful.
+func Collect(ctx context.Context, client *k8s.Client, engine *k8s.RuleEngine, kind k8s.ResourceType, namespace, name string, opts Options) (*Bundle, error) {
+	cs := client.Clientset()
+	b := &Bundle{Index: Index{
+		Created:   time.Now(),
+		Context:   client.Context(),
+		Kind:      kind.Kind(),
+		Namespace: namespace,
+		Name:      name,
+	}}
+	c := &collector{ctx: ctx, client: client, bundle: b, seen: make(map[string]bool)}
+
+	var pods []k8s.PodInfo
+	if kind == k8s.ResourcePods {
+		pod, err := k8s.GetPod(ctx, cs, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		pods = []k8s.PodInfo{*pod}
+	} else {
+		workload, err := k8s.GetWorkload(ctx, cs, namespace, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		b.Workload = &Workload{Info: *workload, Findings: k8s.AnalyzeWorkload(workload)}
+		c.addObject(namespace, kind.Kind(), name)
+
+		pods, err = k8s.GetWorkloadPods(ctx, cs, *workload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range pods {
+		b.Pods = append(b.Pods, c.collectPod(&pods[i], engine, opts))
+	}
+	return b, nil
+}
+
+type collector struct {
+	ctx    context.Context
+	client *k8s.Client
+	bundle *Bundle
+	// Owner objects already added, by kind/name
+	seen map[string]bool
+}
+
+func (c *collector) fail(format string, args ...interface{}) {
+	c.bundle.Index.Errors = append(c.bundle.Index.Errors, fmt.Sprintf(format, args...))
+}
+
+func (c *collector) collectPod(info *k8s.PodInfo, engine *k8s.RuleEngine, opts Options) Pod {
+	cs := c.client.Clientset()
+	p := Pod{Info: *info}
+
+	if pod, err := k8s.GetObject(c.ctx, cs, k8s.ObjectReference{Kind: "Pod", Namespace: info.Namespace, Name: info.Name}); err != nil {
+		c.fail("pod %s manifest: %v", info.Name, err)
+	} else {
+		p.YAML = c.marshal(pod)
+		c.addOwners(info.Namespace, pod.GetOwnerReferences(), maxOwnerDepth)
+	}
+
+	for _, container := range info.Containers {
+		logs, err := k8s.GetPodLogs(c.ctx, cs, info.Namespace, info.Name, k8s.LogOptions{
+			Container: container.Name, TailLines: opts.LogLines, Timestamps: true,
+		})
+		if err != nil {
+			c.fail("pod %s container %s logs: %v", info.Name, container.Name, err)
+		}
+		p.Logs = append(p.Logs, logs...)
+
+		if container.RestartCount == 0 && container.LastTerminated == nil {
+			continue
+		}
+		prev, err := k8s.GetPreviousLogs(c.ctx, cs, info.Namespace, info.Name, container.Name, opts.LogLines)
+		if err != nil {
+			c.fail("pod %s container %s previous logs: %v", info.Name, container.Name, err)
+			continue
+		}
+		if p.PreviousLogs == nil {
+			p.PreviousLogs = make(map[string][]k8s.LogLine)
+		}
+		p.PreviousLogs[container.Name] = prev
+	}
+
+	var err error
+	if p.Events, err = k8s.GetPodEvents(c.ctx, cs, info.Namespace, info.Name); err != nil {
+		c.fail("pod %s events: %v", info.Name, err)
+	}
+	if p.Metrics, err = k8s.GetPodMetrics(c.ctx, c.client.MetricsClient(), info.Namespace, info.Name); err != nil {
+		c.fail("pod %s metrics: %v", info.Name, err)
+	}
+	p.Samples = opts.Samples[info.Name]
+	if p.Related, err = k8s.GetRelatedResources(c.ctx, cs, *info); err != nil {
+		c.fail("pod %s related resources: %v", info.Name, err)
+	}
+	if p.Describe, err = c.describe(info); err != nil {
+		c.fail("pod %s describe: %v", info.Name, err)
+	}
+
+	p.Helpers = engine.Analyze(k8s.DiagnosticInput{Pod: info, Events: p.Events, Logs: p.Logs})
+	return p
+}
+
+// describe runs kubectl describe, which has no client-go equivalent.
+func (c *collector) describe(pod *k8s.PodInfo) (string, error) {
+	args := []string{"describe", "pod", "-n", pod.Namespace, pod.Name}
+	if ctx := c.client.Context(); ctx != "" {
+		args = append(args, "--context", ctx)
+	}
+	out, err := exec.CommandContext(c.ctx, "kubectl", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, out)
+	}
+	return RedactDescribe(string(out)), nil
+}
+
+// addOwners walks the controller owner references up to the top-level
+// workload, e.g. ReplicaSet then Deployment.
+func (c *collector) addOwners(namespace string, refs []metav1.OwnerReference, depth int) {
+	if depth == 0 {
+		return
+	}
+	for _, ref := range refs {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if owner := c.addObject(namespace, ref.Kind, ref.Name); owner != nil {
+			c.addOwners(namespace, owner.GetOwnerReferences(), depth-1)
+		}
+	}
+}
+
+// addObject adds the YAML of a workload object once and returns it.
+func (c *collector) addObject(namespace, kind, name string) k8s.Object {
+	key := kind + "/" + name
+	if c.seen[key] {
+		return nil
+	}
+	c.seen[key] = true
+
+	obj, err := k8s.GetObject(c.ctx, c.client.Clientset(), k8s.ObjectReference{Kind: kind, Namespace: namespace, Name: name})
+	if err != nil {
+		c.fail("%s %s: %v", kind, name, err)
+		return nil
+	}
+	if data := c.marshal(obj); data != nil {
+		c.bundle.Owners = append(c.bundle.Owners, Object{Kind: kind, Name: name, YAML: data})
+	}
+	return obj
+}
+
+func (c *collector) marshal(obj k8s.Object) []byte {
+	obj.SetManagedFields(nil)
+	Redact(obj)
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		c.fail("%s %s: %v", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,106 @@
+package bundle
+
+import (
+	"regexp"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Redacted replaces values that may be secret.
+const Redacted = "REDACTED"
+
+// lastAppliedAnnotation holds the whole applied manifest, including any
+// literal env values.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// sensitiveName matches env var names whose literal values are likely
+// credentials. Values from Secret references are never in a manifest.
+var sensitiveName = regexp.MustCompile(`(?i)(pass|secret|token|key|credential|auth|private|cert|dsn)`)
+
+// credentialURL matches connection strings with a password, such as
+// postgres://user:pass@db, whatever the variable is called.
+var credentialURL = regexp.MustCompile(`://[^/\s]+:[^@\s]+@`)
+
+// sensitive reports whether an env var's literal value should be redacted.
+func sensitive(name, value string) bool {
+	return sensitiveName.MatchString(name) || credentialURL.MatchString(value)
+}
+
+// describeEnvLine is an env var line of kubectl describe: "NAME:  value".
+var describeEnvLine = regexp.MustCompile(`^(\s+)([A-Za-z_][A-Za-z0-9_.-]*):(\s+)(\S.*)$`)
+
+// Redact removes likely secrets from a workload or pod in place: literal
+// values of sensitive env vars, connection strings with credentials and the
+// last-applied-configuration annotation.
+func Redact(obj metav1.Object) {
+	if annotations := obj.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		annotations[lastAppliedAnnotation] = Redacted
+		obj.SetAnnotations(annotations)
+	}
+
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		redactPodSpec(&o.Spec)
+	case *appsv1.ReplicaSet:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *appsv1.Deployment:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *appsv1.StatefulSet:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *appsv1.DaemonSet:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *batchv1.Job:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *batchv1.CronJob:
+		redactPodSpec(&o.Spec.JobTemplate.Spec.Template.Spec)
+	}
+}
+
+func redactPodSpec(spec *corev1.PodSpec) {
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			redactEnv(containers[i].Env)
+		}
+	}
+	for i := range spec.EphemeralContainers {
+		redactEnv(spec.EphemeralContainers[i].Env)
+	}
+}
+
+func redactEnv(env []corev1.EnvVar) {
+	for i := range env {
+		if env[i].Value != "" && sensitive(env[i].Name, env[i].Value) {
+			env[i].Value = Redacted
+		}
+	}
+}
+
+// RedactDescribe blanks literal values of sensitive env vars in kubectl
+// describe output. References such as "<set to the key ...>" are kept.
+func RedactDescribe(out string) string {
+	lines := strings.Split(out, "\n")
+	inEnv := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Environment:") {
+			inEnv = true
+			continue
+		}
+		if !inEnv {
+			continue
+		}
+		m := describeEnvLine.FindStringSubmatch(line)
+		if m == nil {
+			inEnv = false
+			continue
+		}
+		if sensitive(m[2], m[4]) && !strings.HasPrefix(m[4], "<") {
+			lines[i] = m[1] + m[2] + ":" + m[3] + Redacted
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -14,6 +14,17 @@ type Config struct {
 	LogLineLimit     int      `json:"log_line_limit"`
 	RefreshInterval  int      `json:"refresh_interval_seconds"`
 	Theme            string   `json:"theme"`
+	LokiURL          string   `json:"loki_url,omitempty"`
+	LokiTenant       string   `json:"loki_tenant,omitempty"`
+	PrometheusURL    string   `json:"prometheus_url,omitempty"`
+
+	// RulesDir holds custom diagnostic rule files, *.json. Defaults to
+	// ~/.config/k9sight/rules.
+	RulesDir string `json:"rules_dir,omitempty"`
+
+	// MetricsHistoryMinutes is how much in-memory metrics history the
+	// metrics panel keeps and charts per container.
+	MetricsHistoryMinutes int `json:"metrics_history_minutes"`
 }
 
 func DefaultConfig() *Config {
@@ -23,6 +34,8 @@ func DefaultConfig() *Config {
 		LogLineLimit:     500,
 		RefreshInterval:  5,
 		Theme:            "default",
+
+		MetricsHistoryMinutes: 15,
 	}
 }
 
@@ -34,6 +47,18 @@ func configPath() (string, error) {
 	return filepath.Join(home, ".config", "k9sight", "config.json"), nil
 }
 
+// RulesPath returns the directory custom diagnostic rules are read from.
+func (c *Config) RulesPath() (string, error) {
+	if c.RulesDir != "" {
+		return c.RulesDir, nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "rules"), nil
+}
+
 func Load() (*Config, error) {
 	path, err := configPath()
 	if err != nil {
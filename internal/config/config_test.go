@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -24,6 +25,10 @@ func TestDefaultConfig(t *testing.T) {
 		t.Errorf("DefaultConfig().RefreshInterval = %d, should be positive", cfg.RefreshInterval)
 	}
 
+	if cfg.MetricsHistoryMinutes <= 0 {
+		t.Errorf("DefaultConfig().MetricsHistoryMinutes = %d, should be positive", cfg.MetricsHistoryMinutes)
+	}
+
 	if cfg.FavoriteItems == nil {
 		// nil is acceptable, but if not nil should be empty
 	} else if len(cfg.FavoriteItems) != 0 {
@@ -133,3 +138,20 @@ func TestSetters(t *testing.T) {
 		t.Errorf("After SetLastResourceType, LastResourceType = %q, want %q", cfg.LastResourceType, "statefulsets")
 	}
 }
+
+func TestRulesPath(t *testing.T) {
+	cfg := DefaultConfig()
+
+	path, err := cfg.RulesPath()
+	if err != nil {
+		t.Fatalf("RulesPath() error = %v", err)
+	}
+	if filepath.Base(path) != "rules" || filepath.Base(filepath.Dir(path)) != "k9sight" {
+		t.Errorf("RulesPath() = %q, want the rules dir next to config.json", path)
+	}
+
+	cfg.RulesDir = "/etc/k9sight/rules"
+	if path, _ := cfg.RulesPath(); path != "/etc/k9sight/rules" {
+		t.Errorf("RulesPath() = %q, want the configured rules_dir", path)
+	}
+}
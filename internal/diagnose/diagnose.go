@@ -0,0 +1,423 @@
+// Package diagnose collects what the pod dashboard shows about a pod or
+// workload into a report that can be printed without the TUI.
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/doganarif/k9sight/internal/k8s"
+)
+
+// Target is the object to diagnose.
+type Target struct {
+	Type      k8s.ResourceType
+	Namespace string
+	Name      string
+}
+
+// ParseTarget reads a kubectl-style reference such as deploy/api,
+// pod/api-7d9f-x2k or node/worker-1; a bare name is a pod.
+func ParseTarget(ref, namespace string) (Target, error) {
+	t := Target{Type: k8s.ResourcePods, Namespace: namespace, Name: ref}
+	if kind, name, ok := strings.Cut(ref, "/"); ok {
+		rt, known := k8s.ParseResourceType(kind)
+		if !known {
+			return Target{}, fmt.Errorf("unknown resource type %q", kind)
+		}
+		t.Type, t.Name = rt, name
+	}
+	if t.Name == "" {
+		return Target{}, fmt.Errorf("missing name in %q", ref)
+	}
+	return t, nil
+}
+
+func (t Target) String() string {
+	return strings.ToLower(t.Type.Kind()) + "/" + t.Name
+}
+
+// Options controls how much is gathered per pod.
+type Options struct {
+	// LogLines is the log tail read per pod, shared between its containers
+	LogLines int64
+	// ErrorLines is how many of the most recent error lines are reported
+	ErrorLines int
+	// FailingOnly leaves out pods that are ready or completed and have no
+	// Warning events
+	FailingOnly bool
+}
+
+func DefaultOptions() Options {
+	return Options{LogLines: 200, ErrorLines: 20}
+}
+
+// Report is everything known about a target. Severity is the highest
+// severity of any finding.
+type Report struct {
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Context   string          `json:"context,omitempty"`
+	Generated time.Time       `json:"generated"`
+	Severity  string          `json:"severity,omitempty"`
+	Workload  *WorkloadReport `json:"workload,omitempty"`
+	Pods      []PodReport     `json:"pods"`
+}
+
+type Finding struct {
+	Rule        string   `json:"rule,omitempty"`
+	Issue       string   `json:"issue"`
+	Severity    string   `json:"severity"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	Docs        []string `json:"docs,omitempty"`
+}
+
+type WorkloadReport struct {
+	Ready    string    `json:"ready"`
+	Status   string    `json:"status"`
+	Age      string    `json:"age"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+type PodReport struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Node       string            `json:"node,omitempty"`
+	Status     string            `json:"status"`
+	Ready      string            `json:"ready"`
+	Restarts   int32             `json:"restarts"`
+	Age        string            `json:"age"`
+	IP         string            `json:"ip,omitempty"`
+	Containers []ContainerReport `json:"containers"`
+	Findings   []Finding         `json:"findings,omitempty"`
+	Events     []EventReport     `json:"events,omitempty"`
+	Related    *RelatedReport    `json:"related,omitempty"`
+	Metrics    *MetricsReport    `json:"metrics,omitempty"`
+	ErrorLogs  []LogReport       `json:"error_logs,omitempty"`
+}
+
+type ContainerReport struct {
+	Name            string             `json:"name"`
+	Image           string             `json:"image"`
+	Ready           bool               `json:"ready"`
+	State           string             `json:"state"`
+	Reason          string             `json:"reason,omitempty"`
+	Restarts        int32              `json:"restarts"`
+	CPURequest      string             `json:"cpu_request,omitempty"`
+	CPULimit        string             `json:"cpu_limit,omitempty"`
+	MemoryRequest   string             `json:"memory_request,omitempty"`
+	MemoryLimit     string             `json:"memory_limit,omitempty"`
+	LastTermination *TerminationReport `json:"last_termination,omitempty"`
+}
+
+type TerminationReport struct {
+	ExitCode   int32     `json:"exit_code"`
+	Reason     string    `json:"reason,omitempty"`
+	Meaning    string    `json:"meaning"`
+	Message    string    `json:"message,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+type EventReport struct {
+	Type     string    `json:"type"`
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	Source   string    `json:"source,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type RelatedReport struct {
+	Owner      string          `json:"owner,omitempty"`
+	Services   []ServiceReport `json:"services,omitempty"`
+	Ingresses  []IngressReport `json:"ingresses,omitempty"`
+	ConfigMaps []string        `json:"configmaps,omitempty"`
+	Secrets    []string        `json:"secrets,omitempty"`
+}
+
+type ServiceReport struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	ClusterIP string `json:"cluster_ip"`
+	Ports     string `json:"ports"`
+	Endpoints int    `json:"endpoints"`
+}
+
+type IngressReport struct {
+	Name  string `json:"name"`
+	Hosts string `json:"hosts"`
+	Paths string `json:"paths"`
+}
+
+// MetricsReport is the pod's current usage from metrics-server. Percentages
+// are of the limit, falling back to the request when no limit is set.
+type MetricsReport struct {
+	CPU           string                 `json:"cpu"`
+	CPUPercent    float64                `json:"cpu_percent"`
+	Memory        string                 `json:"memory"`
+	MemoryPercent float64                `json:"memory_percent"`
+	Containers    []ContainerUsageReport `json:"containers,omitempty"`
+	Issues        []string               `json:"issues,omitempty"`
+}
+
+type ContainerUsageReport struct {
+	Name                 string  `json:"name"`
+	CPU                  string  `json:"cpu"`
+	Memory               string  `json:"memory"`
+	CPULimitPercent      float64 `json:"cpu_limit_percent,omitempty"`
+	MemoryLimitPercent   float64 `json:"memory_limit_percent,omitempty"`
+	CPURequestPercent    float64 `json:"cpu_request_percent,omitempty"`
+	MemoryRequestPercent float64 `json:"memory_request_percent,omitempty"`
+}
+
+type LogReport struct {
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Line      string    `json:"line"`
+}
+
+// Gather builds the report for target: the pod itself, or the workload's
+// health and each of its pods.
+func Gather(ctx context.Context, client *k8s.Client, engine *k8s.RuleEngine, target Target, opts Options) (*Report, error) {
+	if target.Type == k8s.ResourceNodes {
+		return nil, fmt.Errorf("nodes cannot be diagnosed, use a pod or workload")
+	}
+
+	report := &Report{
+		Kind:      target.Type.Kind(),
+		Namespace: target.Namespace,
+		Name:      target.Name,
+		Context:   client.Context(),
+		Generated: time.Now(),
+	}
+
+	var pods []k8s.PodInfo
+	if target.Type == k8s.ResourcePods {
+		pod, err := k8s.GetPod(ctx, client.Clientset(), target.Namespace, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		pods = []k8s.PodInfo{*pod}
+	} else {
+		workload, err := k8s.GetWorkload(ctx, client.Clientset(), target.Namespace, target.Type, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		report.Workload = workloadReport(workload)
+		pods, err = k8s.GetWorkloadPods(ctx, client.Clientset(), *workload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range pods {
+		p := gatherPod(ctx, client, engine, &pods[i], opts)
+		if opts.FailingOnly && !failing(&pods[i], p) {
+			continue
+		}
+		report.Pods = append(report.Pods, p)
+	}
+	report.Severity = report.highestSeverity()
+	return report, nil
+}
+
+// gatherPod loads the same data as the dashboard. Each source is optional,
+// so a pod whose logs or metrics are unavailable is still reported.
+func gatherPod(ctx context.Context, client *k8s.Client, engine *k8s.RuleEngine, pod *k8s.PodInfo, opts Options) PodReport {
+	cs := client.Clientset()
+	logs, _ := k8s.GetAllContainerLogs(ctx, cs, pod.Namespace, pod.Name, opts.LogLines)
+	events, _ := k8s.GetPodEvents(ctx, cs, pod.Namespace, pod.Name)
+	metrics, _ := k8s.GetPodMetrics(ctx, client.MetricsClient(), pod.Namespace, pod.Name)
+	related, _ := k8s.GetRelatedResources(ctx, cs, *pod)
+
+	helpers := engine.Analyze(k8s.DiagnosticInput{Pod: pod, Events: events, Logs: logs})
+	return podReport(pod, helpers, events, related, k8s.CalculateResourceUsage(metrics, pod), logs, opts.ErrorLines)
+}
+
+func failing(pod *k8s.PodInfo, p PodReport) bool {
+	if state, _ := k8s.PodRolloutStatus(pod); state != k8s.RolloutComplete {
+		return true
+	}
+	for _, e := range p.Events {
+		if e.Type == "Warning" {
+			return true
+		}
+	}
+	return false
+}
+
+func workloadReport(w *k8s.WorkloadInfo) *WorkloadReport {
+	return &WorkloadReport{
+		Ready:    w.Ready,
+		Status:   w.Status,
+		Age:      w.Age,
+		Findings: findings(k8s.AnalyzeWorkload(w)),
+	}
+}
+
+func podReport(pod *k8s.PodInfo, helpers []k8s.DebugHelper, events []k8s.EventInfo, related *k8s.RelatedResources, usage *k8s.ResourceUsageSummary, logs []k8s.LogLine, errorLines int) PodReport {
+	r := PodReport{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Node,
+		Status:    pod.Status,
+		Ready:     pod.Ready,
+		Restarts:  pod.Restarts,
+		Age:       pod.Age,
+		IP:        pod.IP,
+		Findings:  findings(helpers),
+	}
+
+	for _, c := range pod.Containers {
+		cr := ContainerReport{
+			Name:          c.Name,
+			Image:         c.Image,
+			Ready:         c.Ready,
+			State:         c.State,
+			Reason:        c.Reason,
+			Restarts:      c.RestartCount,
+			CPURequest:    c.Resources.CPURequest,
+			CPULimit:      c.Resources.CPULimit,
+			MemoryRequest: c.Resources.MemoryRequest,
+			MemoryLimit:   c.Resources.MemoryLimit,
+		}
+		if t := c.LastTermination(); t != nil {
+			cr.LastTermination = &TerminationReport{
+				ExitCode:   t.ExitCode,
+				Reason:     t.Reason,
+				Meaning:    t.Meaning(),
+				Message:    t.Message,
+				FinishedAt: t.FinishedAt,
+			}
+		}
+		r.Containers = append(r.Containers, cr)
+	}
+
+	for _, e := range events {
+		r.Events = append(r.Events, EventReport{
+			Type:     e.Type,
+			Reason:   e.Reason,
+			Message:  e.Message,
+			Count:    e.Count,
+			Source:   e.Source,
+			LastSeen: e.LastSeen,
+		})
+	}
+
+	if related != nil {
+		r.Related = relatedReport(related)
+	}
+
+	if usage != nil {
+		m := &MetricsReport{
+			CPU:           usage.CPUUsed,
+			CPUPercent:    usage.CPUPercent,
+			Memory:        usage.MemUsed,
+			MemoryPercent: usage.MemPercent,
+			Issues:        k8s.UsageIssues(usage),
+		}
+		for _, u := range usage.Containers {
+			m.Containers = append(m.Containers, ContainerUsageReport{
+				Name:                 u.Name,
+				CPU:                  k8s.FormatCPU(u.CPUMilli),
+				Memory:               k8s.FormatMemory(u.MemoryBytes),
+				CPULimitPercent:      u.CPULimitPercent,
+				MemoryLimitPercent:   u.MemLimitPercent,
+				CPURequestPercent:    u.CPURequestPercent,
+				MemoryRequestPercent: u.MemRequestPercent,
+			})
+		}
+		r.Metrics = m
+	}
+
+	errors := k8s.FilterErrorLogs(logs)
+	if errorLines >= 0 && len(errors) > errorLines {
+		errors = errors[len(errors)-errorLines:]
+	}
+	for _, l := range errors {
+		r.ErrorLogs = append(r.ErrorLogs, LogReport{Timestamp: l.Timestamp, Container: l.Container, Line: l.Content})
+	}
+	return r
+}
+
+func relatedReport(related *k8s.RelatedResources) *RelatedReport {
+	r := &RelatedReport{
+		ConfigMaps: related.ConfigMaps,
+		Secrets:    related.Secrets,
+	}
+	if related.Owner != nil {
+		r.Owner = related.Owner.Kind + "/" + related.Owner.Name
+	}
+	for _, s := range related.Services {
+		r.Services = append(r.Services, ServiceReport{
+			Name:      s.Name,
+			Type:      s.Type,
+			ClusterIP: s.ClusterIP,
+			Ports:     s.Ports,
+			Endpoints: s.Endpoints,
+		})
+	}
+	for _, i := range related.Ingresses {
+		r.Ingresses = append(r.Ingresses, IngressReport{Name: i.Name, Hosts: i.Hosts, Paths: i.Paths})
+	}
+	return r
+}
+
+func findings(helpers []k8s.DebugHelper) []Finding {
+	var out []Finding
+	for _, h := range helpers {
+		out = append(out, Finding{
+			Rule:        h.Rule,
+			Issue:       h.Issue,
+			Severity:    h.Severity,
+			Suggestions: h.Suggestions,
+			Docs:        h.Docs,
+		})
+	}
+	return out
+}
+
+func (r *Report) highestSeverity() string {
+	highest := ""
+	consider := func(fs []Finding) {
+		for _, f := range fs {
+			if highest == "" || k8s.SeverityRank(f.Severity) < k8s.SeverityRank(highest) {
+				highest = f.Severity
+			}
+		}
+	}
+	if r.Workload != nil {
+		consider(r.Workload.Findings)
+	}
+	for _, p := range r.Pods {
+		consider(p.Findings)
+	}
+	return highest
+}
+
+// Exit codes of the diagnose command. ExitError means the report could not
+// be produced; the others reflect the report's highest severity.
+const (
+	ExitOK      = 0
+	ExitError   = 1
+	ExitWarning = 2
+	ExitMedium  = 3
+	ExitHigh    = 4
+)
+
+// ExitCode maps the report's severity to the process exit code. Info
+// findings, like a missing CPU limit, don't fail the command.
+func (r *Report) ExitCode() int {
+	switch k8s.SeverityRank(r.Severity) {
+	case k8s.SeverityRank("High"):
+		return ExitHigh
+	case k8s.SeverityRank("Medium"):
+		return ExitMedium
+	case k8s.SeverityRank("Warning"):
+		return ExitWarning
+	}
+	return ExitOK
+}
@@ -0,0 +1,217 @@
+package diagnose
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doganarif/k9sight/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want Target
+	}{
+		{"api-7d9f-x2k", Target{Type: k8s.ResourcePods, Namespace: "shop", Name: "api-7d9f-x2k"}},
+		{"deploy/api", Target{Type: k8s.ResourceDeployments, Namespace: "shop", Name: "api"}},
+		{"statefulset/db", Target{Type: k8s.ResourceStatefulSets, Namespace: "shop", Name: "db"}},
+		{"Job/migrate", Target{Type: k8s.ResourceJobs, Namespace: "shop", Name: "migrate"}},
+		{"no/worker-1", Target{Type: k8s.ResourceNodes, Namespace: "shop", Name: "worker-1"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.ref, "shop")
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTarget(%q) = %+v, %v, want %+v", tt.ref, got, err, tt.want)
+		}
+	}
+
+	for _, ref := range []string{"", "deploy/", "replicaset/api"} {
+		if _, err := ParseTarget(ref, "shop"); err == nil {
+			t.Errorf("ParseTarget(%q) should fail", ref)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "JSON": FormatJSON, "md": FormatMarkdown, "markdown": FormatMarkdown} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("ParseFormat(yaml) should fail")
+	}
+}
+
+func samplePodReport(now time.Time) PodReport {
+	pod := &k8s.PodInfo{
+		Name:      "api-7d9f-x2k",
+		Namespace: "shop",
+		Node:      "worker-1",
+		Status:    "CrashLoopBackOff",
+		Ready:     "0/1",
+		Restarts:  7,
+		Containers: []k8s.ContainerInfo{{
+			Name:         "app",
+			Image:        "registry.example.com/api:1.2",
+			State:        "Waiting",
+			Reason:       "CrashLoopBackOff",
+			RestartCount: 7,
+			LastTerminated: &k8s.TerminationInfo{
+				ExitCode:   137,
+				Reason:     "OOMKilled",
+				Message:    "heap exhausted",
+				FinishedAt: now.Add(-time.Minute),
+			},
+		}},
+	}
+	helpers := []k8s.DebugHelper{
+		{Rule: "crash-loop", Issue: "CrashLoopBackOff", Severity: "High", Suggestions: []string{"Check container logs | grep panic"}},
+		{Rule: "no-cpu-limit", Issue: "No CPU limit on container app", Severity: "Info"},
+	}
+	events := []k8s.EventInfo{{Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 12, LastSeen: now.Add(-2 * time.Minute)}}
+	related := &k8s.RelatedResources{
+		Owner:    &k8s.OwnerInfo{Kind: "ReplicaSet", Name: "api-7d9f"},
+		Services: []k8s.ServiceInfo{{Name: "api", Type: "ClusterIP", ClusterIP: "10.0.0.10", Ports: "80/TCP", Endpoints: 0}},
+	}
+	logs := []k8s.LogLine{
+		{Container: "app", Content: "error: first", IsError: true},
+		{Container: "app", Content: "listening on :8080"},
+		{Container: "app", Content: "error: second", IsError: true},
+		{Container: "app", Content: "fatal: third", IsError: true},
+	}
+	return podReport(pod, helpers, events, related, nil, logs, 2)
+}
+
+func TestPodReport(t *testing.T) {
+	p := samplePodReport(time.Now())
+
+	if len(p.ErrorLogs) != 2 || p.ErrorLogs[0].Line != "error: second" || p.ErrorLogs[1].Line != "fatal: third" {
+		t.Errorf("ErrorLogs = %+v, want the last two error lines", p.ErrorLogs)
+	}
+	last := p.Containers[0].LastTermination
+	if last == nil || last.ExitCode != 137 || last.Meaning != "OOM killed, SIGKILL" {
+		t.Errorf("LastTermination = %+v", last)
+	}
+	if p.Related == nil || p.Related.Owner != "ReplicaSet/api-7d9f" || len(p.Related.Services) != 1 {
+		t.Errorf("Related = %+v", p.Related)
+	}
+	if p.Metrics != nil {
+		t.Errorf("Metrics = %+v, want nil without metrics-server", p.Metrics)
+	}
+}
+
+func TestReportSeverityAndExitCode(t *testing.T) {
+	r := &Report{Pods: []PodReport{{Findings: []Finding{{Severity: "Info"}}}}}
+	if r.Severity = r.highestSeverity(); r.Severity != "Info" || r.ExitCode() != ExitOK {
+		t.Errorf("Info only: severity %q, exit %d", r.Severity, r.ExitCode())
+	}
+
+	r.Workload = &WorkloadReport{Findings: []Finding{{Severity: "Medium"}}}
+	if r.Severity = r.highestSeverity(); r.Severity != "Medium" || r.ExitCode() != ExitMedium {
+		t.Errorf("workload finding: severity %q, exit %d", r.Severity, r.ExitCode())
+	}
+
+	r.Pods = append(r.Pods, samplePodReport(time.Now()))
+	if r.Severity = r.highestSeverity(); r.Severity != "High" || r.ExitCode() != ExitHigh {
+		t.Errorf("pod finding: severity %q, exit %d", r.Severity, r.ExitCode())
+	}
+
+	if (&Report{}).ExitCode() != ExitOK {
+		t.Error("a report without findings should exit 0")
+	}
+}
+
+func sampleReport() *Report {
+	now := time.Now()
+	r := &Report{
+		Kind:      "Deployment",
+		Namespace: "shop",
+		Name:      "api",
+		Context:   "prod",
+		Generated: now,
+		Workload:  &WorkloadReport{Ready: "0/1", Status: "Progressing", Age: "3d"},
+		Pods:      []PodReport{samplePodReport(now)},
+	}
+	r.Severity = r.highestSeverity()
+	return r
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, sampleReport(), FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if decoded["severity"] != "High" || decoded["kind"] != "Deployment" {
+		t.Errorf("decoded = %v", decoded)
+	}
+	for _, key := range []string{`"error_logs"`, `"last_termination"`, `"exit_code": 137`, `"cluster_ip"`} {
+		if !strings.Contains(buf.String(), key) {
+			t.Errorf("JSON output is missing %s", key)
+		}
+	}
+}
+
+func TestWriteMarkdownAndText(t *testing.T) {
+	r := sampleReport()
+
+	md := Markdown(r)
+	for _, want := range []string{
+		"# Diagnosis: `deployment/api`",
+		"- **Severity:** High",
+		"## Pod `api-7d9f-x2k`",
+		"| app | registry.example.com/api:1.2 | Waiting (CrashLoopBackOff) | 7 | exit 137 (OOMKilled): OOM killed, SIGKILL |",
+		"- **High** CrashLoopBackOff",
+		"| Warning | BackOff | 12 | 2m ago | Back-off restarting failed container |",
+		"- service api (ClusterIP 10.0.0.10, 80/TCP, 0 endpoints)",
+		"[app] fatal: third",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() is missing %q\n%s", want, md)
+		}
+	}
+
+	text := Text(r)
+	for _, want := range []string{
+		"deployment/api in shop (context prod)",
+		"Severity: High",
+		"Pod api-7d9f-x2k: CrashLoopBackOff, 0/1 ready, 7 restarts",
+		"[High] CrashLoopBackOff",
+		"- Check container logs | grep panic",
+		"| heap exhausted",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() is missing %q\n%s", want, text)
+		}
+	}
+}
+
+func TestMarkdownCellEscaping(t *testing.T) {
+	if got := cell("a | b\nc"); got != `a \| b c` {
+		t.Errorf("cell() = %q", got)
+	}
+}
+
+func TestFailing(t *testing.T) {
+	pod := &k8s.PodInfo{Phase: corev1.PodRunning, Status: "Running", Ready: "1/1",
+		Containers: []k8s.ContainerInfo{{Name: "app", Ready: true}}}
+	if failing(pod, PodReport{}) {
+		t.Error("a ready pod without warnings is not failing")
+	}
+	if !failing(pod, PodReport{Events: []EventReport{{Type: "Warning", Reason: "Unhealthy"}}}) {
+		t.Error("a ready pod with warning events is failing")
+	}
+	pod.Containers[0].Ready = false
+	if !failing(pod, PodReport{}) {
+		t.Error("a pod with an unready container is failing")
+	}
+}
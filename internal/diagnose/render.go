@@ -0,0 +1,340 @@
+package diagnose
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat accepts the -o values, with md as a short form of markdown.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "text":
+		return FormatText, nil
+	case "json":
+		return FormatJSON, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (use text, json or markdown)", s)
+}
+
+// Write renders the report in format to w.
+func Write(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatMarkdown:
+		_, err := io.WriteString(w, Markdown(r))
+		return err
+	default:
+		_, err := io.WriteString(w, Text(r))
+		return err
+	}
+}
+
+func (r *Report) title() string {
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
+
+// Text renders the report for a terminal.
+func Text(r *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s in %s", r.title(), r.Namespace)
+	if r.Context != "" {
+		fmt.Fprintf(&b, " (context %s)", r.Context)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Severity: %s\n", severityOrNone(r.Severity))
+	fmt.Fprintf(&b, "Generated: %s\n", r.Generated.Format(time.RFC3339))
+
+	if w := r.Workload; w != nil {
+		fmt.Fprintf(&b, "\nWorkload: %s ready, %s, age %s\n", w.Ready, w.Status, w.Age)
+		writeTextFindings(&b, w.Findings, "  ")
+	}
+
+	if len(r.Pods) == 0 {
+		b.WriteString("\nNo pods found\n")
+	}
+	for _, p := range r.Pods {
+		fmt.Fprintf(&b, "\nPod %s: %s, %s ready, %d restarts, age %s", p.Name, p.Status, p.Ready, p.Restarts, p.Age)
+		if p.Node != "" {
+			fmt.Fprintf(&b, ", node %s", p.Node)
+		}
+		if p.IP != "" {
+			fmt.Fprintf(&b, ", ip %s", p.IP)
+		}
+		b.WriteString("\n")
+
+		b.WriteString("  Containers:\n")
+		for _, c := range p.Containers {
+			fmt.Fprintf(&b, "    %s (%s): %s", c.Name, c.Image, containerState(c))
+			if c.Restarts > 0 {
+				fmt.Fprintf(&b, ", %d restarts", c.Restarts)
+			}
+			if t := c.LastTermination; t != nil {
+				fmt.Fprintf(&b, ", last %s", terminationSummary(t))
+			}
+			b.WriteString("\n")
+			if t := c.LastTermination; t != nil && t.Message != "" {
+				writeIndented(&b, t.Message, "      | ")
+			}
+		}
+
+		if len(p.Findings) > 0 {
+			b.WriteString("  Findings:\n")
+			writeTextFindings(&b, p.Findings, "    ")
+		}
+
+		if len(p.Events) > 0 {
+			b.WriteString("  Events:\n")
+			for _, e := range p.Events {
+				fmt.Fprintf(&b, "    %s %s x%d (%s ago): %s\n", e.Type, e.Reason, max32(e.Count, 1), ago(e.LastSeen, r.Generated), e.Message)
+			}
+		}
+
+		if m := p.Metrics; m != nil {
+			fmt.Fprintf(&b, "  Metrics: cpu %s (%.0f%%), memory %s (%.0f%%)\n", m.CPU, m.CPUPercent, m.Memory, m.MemoryPercent)
+			for _, issue := range m.Issues {
+				fmt.Fprintf(&b, "    ! %s\n", issue)
+			}
+		}
+
+		if rel := relatedLines(p.Related); len(rel) > 0 {
+			b.WriteString("  Related:\n")
+			for _, l := range rel {
+				fmt.Fprintf(&b, "    %s\n", l)
+			}
+		}
+
+		if len(p.ErrorLogs) > 0 {
+			b.WriteString("  Error logs:\n")
+			for _, l := range p.ErrorLogs {
+				fmt.Fprintf(&b, "    %s\n", logLine(l))
+			}
+		}
+	}
+	return b.String()
+}
+
+func writeTextFindings(b *strings.Builder, findings []Finding, indent string) {
+	for _, f := range findings {
+		fmt.Fprintf(b, "%s[%s] %s\n", indent, f.Severity, f.Issue)
+		for _, s := range f.Suggestions {
+			fmt.Fprintf(b, "%s    - %s\n", indent, s)
+		}
+		for _, d := range f.Docs {
+			fmt.Fprintf(b, "%s    ↗ %s\n", indent, d)
+		}
+	}
+}
+
+func writeIndented(b *strings.Builder, text, prefix string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+}
+
+// Markdown renders the report for pasting into a ticket or chat.
+func Markdown(r *Report) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Diagnosis: `%s`\n\n", r.title())
+	fmt.Fprintf(&b, "- **Namespace:** %s\n", r.Namespace)
+	if r.Context != "" {
+		fmt.Fprintf(&b, "- **Context:** %s\n", r.Context)
+	}
+	fmt.Fprintf(&b, "- **Severity:** %s\n", severityOrNone(r.Severity))
+	fmt.Fprintf(&b, "- **Generated:** %s\n", r.Generated.Format(time.RFC3339))
+
+	if w := r.Workload; w != nil {
+		b.WriteString("\n## Workload\n\n")
+		fmt.Fprintf(&b, "%s ready, %s, age %s\n", w.Ready, w.Status, w.Age)
+		if len(w.Findings) > 0 {
+			b.WriteString("\n")
+			writeMarkdownFindings(&b, w.Findings)
+		}
+	}
+
+	if len(r.Pods) == 0 {
+		b.WriteString("\nNo pods found.\n")
+	}
+	for _, p := range r.Pods {
+		fmt.Fprintf(&b, "\n## Pod `%s`\n\n", p.Name)
+		b.WriteString("| Status | Ready | Restarts | Age | Node | IP |\n|---|---|---|---|---|---|\n")
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %s | %s |\n", cell(p.Status), p.Ready, p.Restarts, p.Age, cell(p.Node), p.IP)
+
+		b.WriteString("\n### Containers\n\n")
+		b.WriteString("| Name | Image | State | Restarts | Last termination |\n|---|---|---|---|---|\n")
+		for _, c := range p.Containers {
+			last := ""
+			if c.LastTermination != nil {
+				last = terminationSummary(c.LastTermination)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n", cell(c.Name), cell(c.Image), cell(containerState(c)), c.Restarts, cell(last))
+		}
+		for _, c := range p.Containers {
+			if t := c.LastTermination; t != nil && t.Message != "" {
+				fmt.Fprintf(&b, "\nTermination message of `%s`:\n\n```text\n%s\n```\n", c.Name, strings.TrimRight(t.Message, "\n"))
+			}
+		}
+
+		if len(p.Findings) > 0 {
+			b.WriteString("\n### Findings\n\n")
+			writeMarkdownFindings(&b, p.Findings)
+		}
+
+		if len(p.Events) > 0 {
+			b.WriteString("\n### Events\n\n")
+			b.WriteString("| Type | Reason | Count | Last seen | Message |\n|---|---|---|---|---|\n")
+			for _, e := range p.Events {
+				fmt.Fprintf(&b, "| %s | %s | %d | %s ago | %s |\n", e.Type, cell(e.Reason), max32(e.Count, 1), ago(e.LastSeen, r.Generated), cell(e.Message))
+			}
+		}
+
+		if m := p.Metrics; m != nil {
+			b.WriteString("\n### Metrics\n\n")
+			fmt.Fprintf(&b, "CPU %s (%.0f%%), memory %s (%.0f%%)\n", m.CPU, m.CPUPercent, m.Memory, m.MemoryPercent)
+			if len(m.Issues) > 0 {
+				b.WriteString("\n")
+				for _, issue := range m.Issues {
+					fmt.Fprintf(&b, "- %s\n", issue)
+				}
+			}
+		}
+
+		if rel := relatedLines(p.Related); len(rel) > 0 {
+			b.WriteString("\n### Related resources\n\n")
+			for _, l := range rel {
+				fmt.Fprintf(&b, "- %s\n", l)
+			}
+		}
+
+		if len(p.ErrorLogs) > 0 {
+			b.WriteString("\n### Error logs\n\n```text\n")
+			for _, l := range p.ErrorLogs {
+				b.WriteString(logLine(l) + "\n")
+			}
+			b.WriteString("```\n")
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownFindings(b *strings.Builder, findings []Finding) {
+	for _, f := range findings {
+		fmt.Fprintf(b, "- **%s** %s\n", f.Severity, f.Issue)
+		for _, s := range f.Suggestions {
+			fmt.Fprintf(b, "  - %s\n", s)
+		}
+		for _, d := range f.Docs {
+			fmt.Fprintf(b, "  - <%s>\n", d)
+		}
+	}
+}
+
+// cell makes a value safe inside a markdown table row.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func severityOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func containerState(c ContainerReport) string {
+	state := c.State
+	if c.Reason != "" {
+		state += " (" + c.Reason + ")"
+	}
+	if c.Ready {
+		state += ", ready"
+	}
+	return state
+}
+
+func terminationSummary(t *TerminationReport) string {
+	s := fmt.Sprintf("exit %d", t.ExitCode)
+	if t.Reason != "" {
+		s += " (" + t.Reason + ")"
+	}
+	return s + ": " + t.Meaning
+}
+
+func relatedLines(r *RelatedReport) []string {
+	if r == nil {
+		return nil
+	}
+	var lines []string
+	if r.Owner != "" {
+		lines = append(lines, "owner "+r.Owner)
+	}
+	for _, s := range r.Services {
+		lines = append(lines, fmt.Sprintf("service %s (%s %s, %s, %d endpoints)", s.Name, s.Type, s.ClusterIP, s.Ports, s.Endpoints))
+	}
+	for _, i := range r.Ingresses {
+		lines = append(lines, fmt.Sprintf("ingress %s (%s %s)", i.Name, i.Hosts, i.Paths))
+	}
+	if len(r.ConfigMaps) > 0 {
+		lines = append(lines, "configmaps "+strings.Join(r.ConfigMaps, ", "))
+	}
+	if len(r.Secrets) > 0 {
+		lines = append(lines, "secrets "+strings.Join(r.Secrets, ", "))
+	}
+	return lines
+}
+
+func logLine(l LogReport) string {
+	var prefix []string
+	if !l.Timestamp.IsZero() {
+		prefix = append(prefix, l.Timestamp.UTC().Format(time.RFC3339))
+	}
+	if l.Container != "" {
+		prefix = append(prefix, "["+l.Container+"]")
+	}
+	if len(prefix) == 0 {
+		return l.Line
+	}
+	return strings.Join(prefix, " ") + " " + l.Line
+}
+
+// ago formats the time between t and now like pod ages are shown.
+func ago(t, now time.Time) string {
+	if t.IsZero() {
+		return "?"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+func max32(v, floor int32) int32 {
+	if v < floor {
+		return floor
+	}
+	return v
+}
@@ -0,0 +1,227 @@
+package k8s
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// DataFormat is the syntax detected in a ConfigMap or Secret value.
+type DataFormat string
+
+const (
+	DataText       DataFormat = "text"
+	DataJSON       DataFormat = "json"
+	DataYAML       DataFormat = "yaml"
+	DataProperties DataFormat = "properties"
+	DataPEM        DataFormat = "pem"
+	DataBinary     DataFormat = "binary"
+)
+
+// DataEntry is one key of a ConfigMap or Secret. Secret values are
+// already base64-decoded.
+type DataEntry struct {
+	Key    string
+	Value  string
+	Size   int
+	Format DataFormat
+}
+
+// ConfigData is the content of a ConfigMap or Secret.
+type ConfigData struct {
+	Ref ObjectReference
+	// Type is the Secret type, e.g. kubernetes.io/tls
+	Type    string
+	Entries []DataEntry
+	// Certificates parsed from PEM values, such as tls.crt and ca.crt
+	Certificates []CertificateInfo
+}
+
+// CertificateInfo describes an X.509 certificate found in a value.
+type CertificateInfo struct {
+	Key       string
+	Subject   string
+	Issuer    string
+	DNSNames  []string
+	IPs       []string
+	NotBefore time.Time
+	NotAfter  time.Time
+	IsCA      bool
+}
+
+// Expiry says when the certificate expires relative to now, e.g.
+// "expires in 42d" or "expired 3d ago".
+func (c CertificateInfo) Expiry(now time.Time) string {
+	if now.After(c.NotAfter) {
+		return "expired " + formatDuration(now.Sub(c.NotAfter)) + " ago"
+	}
+	if now.Before(c.NotBefore) {
+		return "not valid for " + formatDuration(c.NotBefore.Sub(now))
+	}
+	return "expires in " + formatDuration(c.NotAfter.Sub(now))
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// GetConfigMapData reads a ConfigMap's data and binaryData.
+func GetConfigMapData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (*ConfigData, error) {
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return configMapData(cm), nil
+}
+
+// GetSecretData reads a Secret's decoded data. Values are returned in the
+// clear; hiding them is up to the caller.
+func GetSecretData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (*ConfigData, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return secretData(secret), nil
+}
+
+func configMapData(cm *corev1.ConfigMap) *ConfigData {
+	data := &ConfigData{Ref: ObjectReference{Kind: "ConfigMap", Namespace: cm.Namespace, Name: cm.Name}}
+	for key, value := range cm.Data {
+		data.Entries = append(data.Entries, newDataEntry(key, []byte(value)))
+	}
+	for key, value := range cm.BinaryData {
+		data.Entries = append(data.Entries, newDataEntry(key, value))
+	}
+	data.sort()
+	return data
+}
+
+func secretData(secret *corev1.Secret) *ConfigData {
+	data := &ConfigData{
+		Ref:  ObjectReference{Kind: "Secret", Namespace: secret.Namespace, Name: secret.Name},
+		Type: string(secret.Type),
+	}
+	for key, value := range secret.Data {
+		data.Entries = append(data.Entries, newDataEntry(key, value))
+		data.Certificates = append(data.Certificates, ParseCertificates(key, value)...)
+	}
+	data.sort()
+	return data
+}
+
+func (d *ConfigData) sort() {
+	sort.Slice(d.Entries, func(i, j int) bool { return d.Entries[i].Key < d.Entries[j].Key })
+	sort.SliceStable(d.Certificates, func(i, j int) bool { return d.Certificates[i].Key < d.Certificates[j].Key })
+}
+
+func newDataEntry(key string, value []byte) DataEntry {
+	entry := DataEntry{Key: key, Size: len(value)}
+	if !utf8.Valid(value) {
+		entry.Format = DataBinary
+		return entry
+	}
+	entry.Value = string(value)
+	entry.Format = DetectFormat(key, entry.Value)
+	return entry
+}
+
+// DetectFormat guesses the syntax of a value from the key's extension,
+// then from the content.
+func DetectFormat(key, value string) DataFormat {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".json":
+		return DataJSON
+	case ".yaml", ".yml":
+		return DataYAML
+	case ".properties", ".env":
+		return DataProperties
+	case ".pem", ".crt", ".key":
+		return DataPEM
+	}
+
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(trimmed, "-----BEGIN "):
+		return DataPEM
+	case (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)):
+		return DataJSON
+	case !strings.Contains(trimmed, "\n"):
+		// A single line is a plain value, even if it parses as YAML
+		return DataText
+	case isProperties(trimmed):
+		return DataProperties
+	}
+	var doc map[string]interface{}
+	if yaml.Unmarshal([]byte(trimmed), &doc) == nil && len(doc) > 0 {
+		return DataYAML
+	}
+	return DataText
+}
+
+// isProperties reports whether every line is a key=value pair or a
+// comment.
+func isProperties(value string) bool {
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		key, _, ok := strings.Cut(line, "=")
+		if !ok || strings.ContainsAny(strings.TrimSpace(key), " :") {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseCertificates returns the certificates in the PEM blocks of a
+// value. Other blocks, such as private keys, are skipped.
+func ParseCertificates(key string, value []byte) []CertificateInfo {
+	var certs []CertificateInfo
+	for {
+		var block *pem.Block
+		block, value = pem.Decode(value)
+		if block == nil {
+			return certs
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		info := CertificateInfo{
+			Key:       key,
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			IsCA:      cert.IsCA,
+		}
+		for _, ip := range cert.IPAddresses {
+			info.IPs = append(info.IPs, ip.String())
+		}
+		certs = append(certs, info)
+	}
+}
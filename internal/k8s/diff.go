@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOp is the kind of a diff line.
+type DiffOp int
+
+const (
+	DiffContext DiffOp = iota
+	DiffAdded
+	DiffRemoved
+	// DiffHunk starts a hunk, with a "@@ -l,n +l,n @@" header as text
+	DiffHunk
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// UnifiedDiff compares two texts line by line and returns the changes
+// with context lines around them, grouped in hunks like diff -u. It is
+// empty when the texts are equal.
+func UnifiedDiff(a, b string, context int) []DiffLine {
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	// Keep the changes and the context around them
+	keep := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Op == DiffContext {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	var out []DiffLine
+	aLine, bLine := 1, 1
+	for i := 0; i < len(lines); {
+		if !keep[i] {
+			aLine, bLine = advance(lines[i], aLine, bLine)
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && keep[end] {
+			end++
+		}
+		var aCount, bCount int
+		for _, l := range lines[i:end] {
+			if l.Op != DiffAdded {
+				aCount++
+			}
+			if l.Op != DiffRemoved {
+				bCount++
+			}
+		}
+		out = append(out, DiffLine{Op: DiffHunk, Text: fmt.Sprintf("@@ -%d,%d +%d,%d @@", aLine, aCount, bLine, bCount)})
+		out = append(out, lines[i:end]...)
+		aLine += aCount
+		bLine += bCount
+		i = end
+	}
+	return out
+}
+
+func advance(l DiffLine, aLine, bLine int) (int, int) {
+	if l.Op != DiffAdded {
+		aLine++
+	}
+	if l.Op != DiffRemoved {
+		bLine++
+	}
+	return aLine, bLine
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns every line of a and b marked as kept, removed or
+// added, from their longest common subsequence. Manifests are a few
+// hundred lines, so the quadratic table is fine.
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the common length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, DiffLine{Op: DiffContext, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{Op: DiffRemoved, Text: a[i]})
+			i++
+		default:
+			out = append(out, DiffLine{Op: DiffAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, DiffLine{Op: DiffRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, DiffLine{Op: DiffAdded, Text: b[j]})
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	b := "a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\n"
+
+	var got []string
+	for _, l := range UnifiedDiff(a, b, 1) {
+		prefix := map[DiffOp]string{DiffContext: " ", DiffAdded: "+", DiffRemoved: "-", DiffHunk: ""}[l.Op]
+		got = append(got, prefix+l.Text)
+	}
+	want := []string{
+		"@@ -3,3 +3,3 @@", " c", "-d", "+D", " e",
+		"@@ -10,1 +10,2 @@", " j", "+k",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if diff := UnifiedDiff(a, a, 3); len(diff) != 0 {
+		t.Errorf("UnifiedDiff() of equal texts = %v", diff)
+	}
+	if diff := UnifiedDiff("", "x\n", 3); len(diff) != 2 || diff[1].Op != DiffAdded {
+		t.Errorf("UnifiedDiff() from empty = %v", diff)
+	}
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// mirrorPodAnnotation marks static pods, which the API cannot evict.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// SetNodeUnschedulable cordons or uncordons a node.
+func SetNodeUnschedulable(ctx context.Context, clientset *kubernetes.Clientset, name string, unschedulable bool) error {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := clientset.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// DrainResult is the outcome of one eviction pass over a node. Blocked
+// evictions were refused by a PodDisruptionBudget and can be retried;
+// Failed ones were refused for any other reason.
+type DrainResult struct {
+	Node        string
+	Evicted     []string
+	Blocked     []string
+	Failed      []string
+	Skipped     []string
+	Terminating int
+}
+
+// Pending reports whether pods are still leaving the node, either because
+// they are shutting down or because their eviction has to be retried.
+func (r DrainResult) Pending() bool {
+	return len(r.Evicted) > 0 || len(r.Blocked) > 0 || r.Terminating > 0
+}
+
+// DrainNode cordons the node and asks the Eviction API to remove every pod
+// on it that a drain should move. It makes a single pass, so callers retry
+// until nothing is pending.
+func DrainNode(ctx context.Context, clientset *kubernetes.Clientset, node string) (DrainResult, error) {
+	result := DrainResult{Node: node}
+
+	if err := SetNodeUnschedulable(ctx, clientset, node, true); err != nil {
+		return result, err
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	evict, skipped, terminating := drainablePods(pods.Items)
+	result.Skipped = skipped
+	result.Terminating = terminating
+
+	for _, pod := range evict {
+		name := pod.Namespace + "/" + pod.Name
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		switch {
+		case err == nil:
+			result.Evicted = append(result.Evicted, name)
+		case apierrors.IsNotFound(err):
+			// Already gone
+		case apierrors.IsTooManyRequests(err):
+			// The API answers 429 when a disruption budget does not allow it
+			result.Blocked = append(result.Blocked, name)
+		default:
+			result.Failed = append(result.Failed, name+": "+err.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// drainablePods splits a node's pods into those to evict and those a drain
+// leaves alone: DaemonSet pods would be recreated on the same node, static
+// pods cannot be evicted and pods without a controller would be lost for
+// good. Pods already shutting down are only counted.
+func drainablePods(pods []corev1.Pod) (evict []corev1.Pod, skipped []string, terminating int) {
+	for _, pod := range pods {
+		name := pod.Namespace + "/" + pod.Name
+		owner := metav1.GetControllerOf(&pod)
+
+		switch {
+		case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed:
+			continue
+		case pod.DeletionTimestamp != nil:
+			terminating++
+		case owner != nil && owner.Kind == "DaemonSet":
+			skipped = append(skipped, name+" (DaemonSet)")
+		case pod.Annotations[mirrorPodAnnotation] != "":
+			skipped = append(skipped, name+" (static)")
+		case owner == nil:
+			skipped = append(skipped, name+" (no controller)")
+		default:
+			evict = append(evict, pod)
+		}
+	}
+	return evict, skipped, terminating
+}
+
+// CordonCommand and DrainCommand return the kubectl equivalents.
+func CordonCommand(node string, unschedulable bool) string {
+	if unschedulable {
+		return "kubectl cordon " + node
+	}
+	return "kubectl uncordon " + node
+}
+
+func DrainCommand(node string) string {
+	return "kubectl drain " + node + " --ignore-daemonsets --delete-emptydir-data"
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDrainablePods(t *testing.T) {
+	controlled := func(name, kind string) corev1.Pod {
+		isController := true
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &isController}},
+		}}
+	}
+
+	web := controlled("web-1", "ReplicaSet")
+	agent := controlled("agent-1", "DaemonSet")
+	static := controlled("etcd-1", "Node")
+	static.Annotations = map[string]string{mirrorPodAnnotation: "abc"}
+	bare := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default"}}
+	leaving := controlled("web-2", "ReplicaSet")
+	now := metav1.Now()
+	leaving.DeletionTimestamp = &now
+	done := controlled("job-1", "Job")
+	done.Status.Phase = corev1.PodSucceeded
+
+	evict, skipped, terminating := drainablePods([]corev1.Pod{web, agent, static, bare, leaving, done})
+
+	if len(evict) != 1 || evict[0].Name != "web-1" {
+		t.Errorf("evict = %v, want only web-1", evict)
+	}
+	wantSkipped := []string{"default/agent-1 (DaemonSet)", "default/etcd-1 (static)", "default/debug (no controller)"}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %v, want %v", skipped, wantSkipped)
+	}
+	if terminating != 1 {
+		t.Errorf("terminating = %d, want 1", terminating)
+	}
+}
+
+func TestDrainResultPending(t *testing.T) {
+	if (DrainResult{Skipped: []string{"a"}, Failed: []string{"b"}}).Pending() {
+		t.Error("skipped and failed pods should not keep a drain pending")
+	}
+	if !(DrainResult{Blocked: []string{"a"}}).Pending() {
+		t.Error("blocked evictions should keep a drain pending")
+	}
+	if !(DrainResult{Terminating: 1}).Pending() {
+		t.Error("terminating pods should keep a drain pending")
+	}
+}
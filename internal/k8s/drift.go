@@ -0,0 +1,466 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// LastAppliedAnnotation holds the manifest kubectl apply last sent.
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+type DriftChange int
+
+const (
+	// DriftChanged is a field whose live value differs from the desired one
+	DriftChanged DriftChange = iota
+	// DriftAdded is a label, annotation or list item only the live object has
+	DriftAdded
+	// DriftRemoved is a desired field the live object lacks
+	DriftRemoved
+)
+
+// FieldDrift is one difference between the desired and the live object.
+type FieldDrift struct {
+	Path    string
+	Change  DriftChange
+	Desired interface{}
+	Live    interface{}
+}
+
+// DriftReport is the drift of one object.
+type DriftReport struct {
+	Object ObjectReference
+	// Source is what the live object was compared against: the
+	// last-applied annotation or a local manifest file
+	Source string
+	Fields []FieldDrift
+}
+
+// Manifest is an object read from a local file.
+type Manifest struct {
+	File   string
+	Fields map[string]interface{}
+}
+
+// driftIgnored are fields the server or controllers set, never drift.
+var driftIgnored = map[string]bool{
+	"status":                     true,
+	"metadata.managedFields":     true,
+	"metadata.resourceVersion":   true,
+	"metadata.uid":               true,
+	"metadata.generation":        true,
+	"metadata.creationTimestamp": true,
+	"metadata.selfLink":          true,
+	"metadata.namespace":         true,
+	`metadata.annotations["` + LastAppliedAnnotation + `"]`:     true,
+	`metadata.annotations["deployment.kubernetes.io/revision"]`: true,
+}
+
+// Drift compares a live object with its desired state. Only fields set in
+// desired are compared, so values the server defaulted are not drift.
+// Labels, annotations and list items are compared both ways since the
+// server does not add those: an env var or annotation set by hand on the
+// live object shows up as added.
+func Drift(desired, live map[string]interface{}) []FieldDrift {
+	var out []FieldDrift
+	diffValue("", desired, live, &out)
+	return out
+}
+
+func diffValue(path string, desired, live interface{}, out *[]FieldDrift) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			*out = append(*out, FieldDrift{Path: path, Change: DriftChanged, Desired: desired, Live: live})
+			return
+		}
+		for _, k := range sortedKeys(d) {
+			p := joinPath(path, k)
+			if driftIgnored[p] {
+				continue
+			}
+			lv, ok := l[k]
+			if !ok {
+				if !isEmpty(d[k]) {
+					*out = append(*out, FieldDrift{Path: p, Change: DriftRemoved, Desired: d[k]})
+				}
+				continue
+			}
+			diffValue(p, d[k], lv, out)
+		}
+		if path == "metadata" || strings.HasSuffix(path, ".metadata") {
+			for _, k := range []string{"labels", "annotations"} {
+				if _, ok := d[k]; !ok && l[k] != nil {
+					diffValue(joinPath(path, k), map[string]interface{}{}, l[k], out)
+				}
+			}
+		}
+		if strings.HasSuffix(path, "labels") || strings.HasSuffix(path, "annotations") {
+			for _, k := range sortedKeys(l) {
+				p := joinPath(path, k)
+				if _, ok := d[k]; !ok && !driftIgnored[p] {
+					*out = append(*out, FieldDrift{Path: p, Change: DriftAdded, Live: l[k]})
+				}
+			}
+		}
+
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			*out = append(*out, FieldDrift{Path: path, Change: DriftChanged, Desired: desired, Live: live})
+			return
+		}
+		diffList(path, d, l, out)
+
+	default:
+		if !equalScalar(path, desired, live) {
+			*out = append(*out, FieldDrift{Path: path, Change: DriftChanged, Desired: desired, Live: live})
+		}
+	}
+}
+
+// diffList matches items by name when every item has a unique one, like
+// containers and env vars, and by position otherwise.
+func diffList(path string, desired, live []interface{}, out *[]FieldDrift) {
+	dNames, dOK := itemNames(desired)
+	lNames, lOK := itemNames(live)
+	if dOK && lOK {
+		for i, name := range dNames {
+			p := fmt.Sprintf("%s[%s]", path, name)
+			if j := indexOf(lNames, name); j >= 0 {
+				diffValue(p, desired[i], live[j], out)
+			} else {
+				*out = append(*out, FieldDrift{Path: p, Change: DriftRemoved, Desired: desired[i]})
+			}
+		}
+		for j, name := range lNames {
+			if indexOf(dNames, name) < 0 {
+				*out = append(*out, FieldDrift{Path: fmt.Sprintf("%s[%s]", path, name), Change: DriftAdded, Live: live[j]})
+			}
+		}
+		return
+	}
+
+	for i := range desired {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if i < len(live) {
+			diffValue(p, desired[i], live[i], out)
+		} else {
+			*out = append(*out, FieldDrift{Path: p, Change: DriftRemoved, Desired: desired[i]})
+		}
+	}
+	for i := len(desired); i < len(live); i++ {
+		*out = append(*out, FieldDrift{Path: fmt.Sprintf("%s[%d]", path, i), Change: DriftAdded, Live: live[i]})
+	}
+}
+
+func itemNames(items []interface{}) ([]string, bool) {
+	names := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := m["name"].(string)
+		if !ok || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// equalScalar compares numbers by value, since desired ones are parsed as
+// float64 and live ones as int64, quantities under resources by value, so
+// 1000m equals 1, and anything else as printed.
+func equalScalar(path string, desired, live interface{}) bool {
+	if d, ok := toFloat(desired); ok {
+		if l, ok := toFloat(live); ok {
+			return d == l
+		}
+	}
+	if fmt.Sprint(desired) == fmt.Sprint(live) {
+		return true
+	}
+	if !strings.Contains(path, "resources") {
+		return false
+	}
+	d, err := resource.ParseQuantity(fmt.Sprint(desired))
+	if err != nil {
+		return false
+	}
+	l, err := resource.ParseQuantity(fmt.Sprint(live))
+	return err == nil && d.Cmp(l) == 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// isEmpty reports whether a desired value the live object lacks is one the
+// server drops as omitempty, such as paused: false or minReadySeconds: 0.
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	}
+	if f, ok := toFloat(v); ok {
+		return f == 0
+	}
+	return false
+}
+
+// joinPath appends a key, quoting keys with dots or slashes such as
+// annotation names.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LastApplied returns the manifest kubectl apply last sent for live, if
+// it was applied with kubectl.
+func LastApplied(live map[string]interface{}) (map[string]interface{}, bool, error) {
+	meta, _ := live["metadata"].(map[string]interface{})
+	annotations, _ := meta["annotations"].(map[string]interface{})
+	data, _ := annotations[LastAppliedAnnotation].(string)
+	if data == "" {
+		return nil, false, nil
+	}
+	var applied map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &applied); err != nil {
+		return nil, false, fmt.Errorf("reading %s: %w", LastAppliedAnnotation, err)
+	}
+	return applied, true, nil
+}
+
+var documentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// LoadManifests reads the objects in a YAML or JSON file, or in every such
+// file under a directory. Multi-document files and List kinds are split
+// into their objects.
+func LoadManifests(path string) ([]Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var manifests []Manifest
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range documentSeparator.Split(string(data), -1) {
+			var fields map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &fields); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			if fields == nil {
+				continue
+			}
+			items, isList := fields["items"].([]interface{})
+			if !isList || !strings.HasSuffix(fmt.Sprint(fields["kind"]), "List") {
+				manifests = append(manifests, Manifest{File: file, Fields: fields})
+				continue
+			}
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					manifests = append(manifests, Manifest{File: file, Fields: m})
+				}
+			}
+		}
+	}
+	return manifests, nil
+}
+
+// FindManifest returns the local manifest of an object. A manifest
+// without a namespace matches any, as kubectl apply -n would.
+func FindManifest(manifests []Manifest, ref ObjectReference) (*Manifest, bool) {
+	for i, m := range manifests {
+		meta, _ := m.Fields["metadata"].(map[string]interface{})
+		ns, _ := meta["namespace"].(string)
+		if m.Fields["kind"] == ref.Kind && meta["name"] == ref.Name && (ns == "" || ns == ref.Namespace) {
+			return &manifests[i], true
+		}
+	}
+	return nil, false
+}
+
+// ObjectDrift compares a live object with its local manifest when there is
+// one, or else with its last-applied annotation. ok is false when there is
+// nothing to compare with.
+func ObjectDrift(ref ObjectReference, live map[string]interface{}, manifests []Manifest) (report *DriftReport, ok bool, err error) {
+	desired, source := map[string]interface{}(nil), ""
+	if m, found := FindManifest(manifests, ref); found {
+		desired, source = m.Fields, m.File
+	} else {
+		applied, found, err := LastApplied(live)
+		if err != nil || !found {
+			return nil, false, err
+		}
+		desired, source = applied, "last-applied-configuration"
+	}
+	return &DriftReport{Object: ref, Source: source, Fields: Drift(desired, live)}, true, nil
+}
+
+// ScanDrift reports the drift of every object of a workload type in
+// namespace that has a desired state to compare with, by name.
+func ScanDrift(ctx context.Context, clientset *kubernetes.Clientset, namespace string, resourceType ResourceType, manifests []Manifest) (map[string]*DriftReport, error) {
+	objects, err := listObjects(ctx, clientset, namespace, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	reports := make(map[string]*DriftReport)
+	for _, obj := range objects {
+		live, err := ManifestFields(obj)
+		if err != nil {
+			return nil, err
+		}
+		ref := ObjectReference{Kind: resourceType.Kind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		report, ok, err := ObjectDrift(ref, live, manifests)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+		if ok {
+			reports[obj.GetName()] = report
+		}
+	}
+	return reports, nil
+}
+
+func listObjects(ctx context.Context, clientset *kubernetes.Clientset, namespace string, resourceType ResourceType) ([]Object, error) {
+	var objects []Object
+	list := metav1.ListOptions{}
+	switch resourceType {
+	case ResourceDeployments:
+		l, err := clientset.AppsV1().Deployments(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	case ResourceStatefulSets:
+		l, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	case ResourceDaemonSets:
+		l, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	case ResourceJobs:
+		l, err := clientset.BatchV1().Jobs(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	case ResourceCronJobs:
+		l, err := clientset.BatchV1().CronJobs(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	case ResourcePods:
+		l, err := clientset.CoreV1().Pods(namespace).List(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+		for i := range l.Items {
+			objects = append(objects, &l.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("drift is not tracked for %s", resourceType)
+	}
+	return objects, nil
+}
@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+func mustFields(t *testing.T, doc string) map[string]interface{} {
+	t.Helper()
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal([]byte(doc), &fields); err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestDrift(t *testing.T) {
+	desired := mustFields(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  labels: {app: api}
+spec:
+  replicas: 3
+  template:
+    metadata:
+      creationTimestamp: null
+      labels: {app: api}
+    spec:
+      containers:
+      - name: app
+        image: api:1.2
+        ports: [{containerPort: 8080}]
+        resources: {requests: {cpu: "1"}}
+        env: [{name: MODE, value: prod}]
+`)
+	live := mustFields(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: shop
+  uid: 1234
+  resourceVersion: "99"
+  generation: 7
+  labels: {app: api}
+  annotations:
+    deployment.kubernetes.io/revision: "4"
+spec:
+  replicas: 3
+  progressDeadlineSeconds: 600
+  template:
+    metadata:
+      labels: {app: api}
+      annotations:
+        kubectl.kubernetes.io/restartedAt: "2024-05-01T10:00:00Z"
+    spec:
+      containers:
+      - name: app
+        image: api:1.3-hotfix
+        imagePullPolicy: IfNotPresent
+        ports: [{containerPort: 8080, protocol: TCP}]
+        resources: {requests: {cpu: 1000m}}
+        env: [{name: MODE, value: prod}, {name: DEBUG, value: "1"}]
+status:
+  replicas: 3
+`)
+
+	got := map[string]DriftChange{}
+	for _, f := range Drift(desired, live) {
+		got[f.Path] = f.Change
+	}
+	want := map[string]DriftChange{
+		"spec.template.spec.containers[app].image":                                DriftChanged,
+		"spec.template.spec.containers[app].env[DEBUG]":                           DriftAdded,
+		`spec.template.metadata.annotations["kubectl.kubernetes.io/restartedAt"]`: DriftAdded,
+	}
+	if len(got) != len(want) {
+		t.Errorf("Drift() = %v, want %v", got, want)
+	}
+	for path, change := range want {
+		if c, ok := got[path]; !ok || c != change {
+			t.Errorf("Drift() of %s = %v, %v, want %v", path, c, ok, change)
+		}
+	}
+
+	if d := Drift(desired, desired); len(d) != 0 {
+		t.Errorf("Drift() of an object with itself = %v", d)
+	}
+
+	delete(live["spec"].(map[string]interface{}), "replicas")
+	for _, f := range Drift(desired, live) {
+		if f.Path == "spec.replicas" && f.Change != DriftRemoved {
+			t.Errorf("missing replicas reported as %v", f.Change)
+		}
+	}
+}
+
+func TestLastApplied(t *testing.T) {
+	live := mustFields(t, `
+kind: Deployment
+metadata:
+  name: api
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: '{"kind":"Deployment","spec":{"replicas":2}}'
+spec:
+  replicas: 5
+`)
+	applied, ok, err := LastApplied(live)
+	if err != nil || !ok {
+		t.Fatalf("LastApplied() = %v, %v", ok, err)
+	}
+	d := Drift(applied, live)
+	if len(d) != 1 || d[0].Path != "spec.replicas" || d[0].Desired != float64(2) {
+		t.Errorf("Drift() from last-applied = %+v", d)
+	}
+
+	if _, ok, _ := LastApplied(mustFields(t, "metadata: {name: api}")); ok {
+		t.Error("LastApplied() found an annotation that is not there")
+	}
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+---
+apiVersion: v1
+kind: Service
+metadata: {name: api, namespace: shop}
+---
+`,
+		"sub/list.yml": `kind: List
+items:
+- {kind: StatefulSet, metadata: {name: db, namespace: other}}
+`,
+		"README.md": "not a manifest",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifests, err := LoadManifests(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 3 {
+		t.Fatalf("LoadManifests() = %d objects, want 3", len(manifests))
+	}
+
+	if m, ok := FindManifest(manifests, ObjectReference{Kind: "Deployment", Namespace: "shop", Name: "api"}); !ok || m.File != filepath.Join(dir, "app.yaml") {
+		t.Errorf("FindManifest() of an unnamespaced manifest = %v, %v", m, ok)
+	}
+	if _, ok := FindManifest(manifests, ObjectReference{Kind: "StatefulSet", Namespace: "shop", Name: "db"}); ok {
+		t.Error("FindManifest() matched a manifest of another namespace")
+	}
+	if _, ok := FindManifest(manifests, ObjectReference{Kind: "StatefulSet", Namespace: "other", Name: "db"}); !ok {
+		t.Error("FindManifest() did not find a List item")
+	}
+}
+
+func TestDriftNumbersAndOmitted(t *testing.T) {
+	desired := mustFields(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  paused: false
+  minReadySeconds: 0
+  revisionHistoryLimit: 2000000
+  template:
+    spec:
+      hostNetwork: false
+      securityContext: {runAsUser: 1000680000, fsGroup: 1000680000}
+      containers:
+      - name: app
+        image: api:1.2
+`)
+
+	// Live objects are converted from typed ones, so numbers are int64 and
+	// omitempty fields set to false or 0 are dropped
+	uid := int64(1000680000)
+	history := int32(2000000)
+	deploy := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec: appsv1.DeploymentSpec{
+			RevisionHistoryLimit: &history,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					SecurityContext: &corev1.PodSecurityContext{RunAsUser: &uid, FSGroup: &uid},
+					Containers:      []corev1.Container{{Name: "app", Image: "api:1.2"}},
+				},
+			},
+		},
+	}
+	live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(deploy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := Drift(desired, live); len(d) != 0 {
+		t.Errorf("Drift() = %+v, want none", d)
+	}
+
+	desired["spec"].(map[string]interface{})["paused"] = true
+	d := Drift(desired, live)
+	if len(d) != 1 || d[0].Path != "spec.paused" || d[0].Change != DriftRemoved {
+		t.Errorf("Drift() with paused: true = %+v, want spec.paused removed", d)
+	}
+}
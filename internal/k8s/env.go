@@ -0,0 +1,386 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EnvVar is one variable a container receives, resolved to its value.
+type EnvVar struct {
+	Name  string
+	Value string
+	// Source is where the value comes from, e.g. "configmap/app:LOG_LEVEL",
+	// "envFrom secret/db" or "field metadata.name"; empty for a literal
+	Source string
+	// Secret values are masked until revealed
+	Secret bool
+	// Problem keeps the container from starting
+	// (CreateContainerConfigError), such as a missing key
+	Problem string
+	// Note explains a value that could not be resolved without being an
+	// error, such as an optional key that is absent and so not set
+	Note string
+}
+
+// ContainerEnv is the environment of one container.
+type ContainerEnv struct {
+	Container string
+	Init      bool
+	Vars      []EnvVar
+	// Problems are envFrom sources that keep the container from starting
+	Problems []string
+	// Notes are envFrom sources that could not be read
+	Notes []string
+}
+
+// ProblemCount is the number of problems keeping the container from
+// starting.
+func (c ContainerEnv) ProblemCount() int {
+	n := len(c.Problems)
+	for _, v := range c.Vars {
+		if v.Problem != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// EnvSources holds the ConfigMaps and Secrets a pod's environment reads,
+// by name. A name in neither its map nor Errors was not found.
+type EnvSources struct {
+	ConfigMaps map[string]map[string]string
+	Secrets    map[string]map[string]string
+	// Errors other than not found, by "configmap/<name>" or "secret/<name>"
+	Errors map[string]error
+}
+
+// GetPodEnv resolves the environment of every init and app container of a
+// pod, reading the ConfigMaps and Secrets it references.
+func GetPodEnv(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) ([]ContainerEnv, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return ResolveEnv(pod, FetchEnvSources(ctx, clientset, pod)), nil
+}
+
+// FetchEnvSources reads the ConfigMaps and Secrets pod's containers take
+// environment variables from.
+func FetchEnvSources(ctx context.Context, clientset *kubernetes.Clientset, pod *corev1.Pod) EnvSources {
+	sources := EnvSources{
+		ConfigMaps: map[string]map[string]string{},
+		Secrets:    map[string]map[string]string{},
+		Errors:     map[string]error{},
+	}
+	configMaps, secrets := envReferences(pod)
+	for name := range configMaps {
+		cm, err := clientset.CoreV1().ConfigMaps(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			sources.ConfigMaps[name] = cm.Data
+		case !apierrors.IsNotFound(err):
+			sources.Errors["configmap/"+name] = err
+		}
+	}
+	for name := range secrets {
+		secret, err := clientset.CoreV1().Secrets(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			data := make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				data[k] = string(v)
+			}
+			sources.Secrets[name] = data
+		case !apierrors.IsNotFound(err):
+			sources.Errors["secret/"+name] = err
+		}
+	}
+	return sources
+}
+
+func envReferences(pod *corev1.Pod) (configMaps, secrets map[string]bool) {
+	configMaps, secrets = map[string]bool{}, map[string]bool{}
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				configMaps[from.ConfigMapRef.Name] = true
+			}
+			if from.SecretRef != nil {
+				secrets[from.SecretRef.Name] = true
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+				configMaps[ref.Name] = true
+			}
+			if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+				secrets[ref.Name] = true
+			}
+		}
+	}
+	return configMaps, secrets
+}
+
+// ResolveEnv computes what each container of pod receives as the kubelet
+// does: envFrom sources in order, then env, later definitions winning, and
+// $(VAR) references in values expanded from the variables before them.
+func ResolveEnv(pod *corev1.Pod, sources EnvSources) []ContainerEnv {
+	var envs []ContainerEnv
+	for _, c := range pod.Spec.InitContainers {
+		env := resolveContainerEnv(pod, &c, sources)
+		env.Init = true
+		envs = append(envs, env)
+	}
+	for _, c := range pod.Spec.Containers {
+		envs = append(envs, resolveContainerEnv(pod, &c, sources))
+	}
+	return envs
+}
+
+func resolveContainerEnv(pod *corev1.Pod, c *corev1.Container, sources EnvSources) ContainerEnv {
+	env := ContainerEnv{Container: c.Name}
+	index := map[string]int{}
+	set := func(v EnvVar) {
+		if i, ok := index[v.Name]; ok {
+			env.Vars[i] = v
+			return
+		}
+		index[v.Name] = len(env.Vars)
+		env.Vars = append(env.Vars, v)
+	}
+
+	for _, from := range c.EnvFrom {
+		kind, name, optional := "configmap", "", false
+		data := sources.ConfigMaps
+		switch {
+		case from.ConfigMapRef != nil:
+			name, optional = from.ConfigMapRef.Name, isOptional(from.ConfigMapRef.Optional)
+		case from.SecretRef != nil:
+			kind, name, optional = "secret", from.SecretRef.Name, isOptional(from.SecretRef.Optional)
+			data = sources.Secrets
+		default:
+			continue
+		}
+		source := "envFrom " + kind + "/" + name
+		if err := sources.Errors[kind+"/"+name]; err != nil {
+			env.Notes = append(env.Notes, fmt.Sprintf("%s: cannot read: %v", source, err))
+			continue
+		}
+		values, ok := data[name]
+		if !ok {
+			if !optional {
+				env.Problems = append(env.Problems, fmt.Sprintf("%s: %s %s not found", source, kind, name))
+			}
+			continue
+		}
+		for _, key := range sortedStringKeys(values) {
+			set(EnvVar{Name: from.Prefix + key, Value: values[key], Source: source, Secret: kind == "secret"})
+		}
+	}
+
+	for _, e := range c.Env {
+		v := EnvVar{Name: e.Name}
+		if e.ValueFrom == nil {
+			v.Value, v.Secret = expandEnv(e.Value, env.Vars)
+		} else {
+			resolveValueFrom(pod, c, e.ValueFrom, sources, &v)
+		}
+		set(v)
+	}
+	return env
+}
+
+func resolveValueFrom(pod *corev1.Pod, c *corev1.Container, from *corev1.EnvVarSource, sources EnvSources, v *EnvVar) {
+	switch {
+	case from.ConfigMapKeyRef != nil:
+		ref := from.ConfigMapKeyRef
+		resolveKeyRef(v, "configmap", ref.Name, ref.Key, isOptional(ref.Optional), sources.ConfigMaps, sources.Errors)
+	case from.SecretKeyRef != nil:
+		ref := from.SecretKeyRef
+		v.Secret = true
+		resolveKeyRef(v, "secret", ref.Name, ref.Key, isOptional(ref.Optional), sources.Secrets, sources.Errors)
+	case from.FieldRef != nil:
+		v.Source = "field " + from.FieldRef.FieldPath
+		value, ok := podField(pod, from.FieldRef.FieldPath)
+		if !ok {
+			v.Note = "unknown field"
+		}
+		v.Value = value
+	case from.ResourceFieldRef != nil:
+		v.Source = "resource " + from.ResourceFieldRef.Resource
+		v.Value, v.Note = resourceField(pod, c, from.ResourceFieldRef)
+	}
+}
+
+func resolveKeyRef(v *EnvVar, kind, name, key string, optional bool, data map[string]map[string]string, errs map[string]error) {
+	v.Source = kind + "/" + name + ":" + key
+	if err := errs[kind+"/"+name]; err != nil {
+		v.Note = fmt.Sprintf("cannot read %s %s: %v", kind, name, err)
+		return
+	}
+	values, found := data[name]
+	if !found {
+		if optional {
+			v.Note = fmt.Sprintf("not set: optional %s %s not found", kind, name)
+		} else {
+			v.Problem = fmt.Sprintf("%s %s not found", kind, name)
+		}
+		return
+	}
+	value, found := values[key]
+	if !found {
+		if optional {
+			v.Note = fmt.Sprintf("not set: optional key %s not in %s %s", key, kind, name)
+		} else {
+			v.Problem = fmt.Sprintf("key %s not in %s %s", key, kind, name)
+		}
+		return
+	}
+	v.Value = value
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+var fieldSubscript = regexp.MustCompile(`^metadata\.(labels|annotations)\['(.+)'\]$`)
+
+// podField returns the downward API field path of pod.
+func podField(pod *corev1.Pod, path string) (string, bool) {
+	if m := fieldSubscript.FindStringSubmatch(path); m != nil {
+		if m[1] == "labels" {
+			return pod.Labels[m[2]], true
+		}
+		return pod.Annotations[m[2]], true
+	}
+	switch path {
+	case "metadata.name":
+		return pod.Name, true
+	case "metadata.namespace":
+		return pod.Namespace, true
+	case "metadata.uid":
+		return string(pod.UID), true
+	case "spec.nodeName":
+		return pod.Spec.NodeName, true
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, true
+	case "status.hostIP":
+		return pod.Status.HostIP, true
+	case "status.podIP":
+		return pod.Status.PodIP, true
+	case "status.podIPs":
+		var ips []string
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), true
+	}
+	return "", false
+}
+
+// resourceField returns a container's request or limit in units of the
+// divisor, rounded up as the kubelet does. Without a limit the kubelet
+// passes the node's allocatable capacity, which is not known here.
+func resourceField(pod *corev1.Pod, c *corev1.Container, sel *corev1.ResourceFieldSelector) (value, note string) {
+	if sel.ContainerName != "" && sel.ContainerName != c.Name {
+		c = nil
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == sel.ContainerName {
+				c = &pod.Spec.Containers[i]
+			}
+		}
+		if c == nil {
+			return "", "no container " + sel.ContainerName
+		}
+	}
+
+	list, name, _ := strings.Cut(sel.Resource, ".")
+	resources := c.Resources.Requests
+	if list == "limits" {
+		resources = c.Resources.Limits
+	}
+	q, ok := resources[corev1.ResourceName(name)]
+	if !ok {
+		if list == "limits" {
+			return "", "no limit set: the node's allocatable " + name
+		}
+		return "0", ""
+	}
+
+	divisor := sel.Divisor
+	if divisor.IsZero() {
+		return fmt.Sprint(q.Value()), ""
+	}
+	if name == "cpu" {
+		return fmt.Sprint(int64(math.Ceil(float64(q.MilliValue()) / float64(divisor.MilliValue())))), ""
+	}
+	return fmt.Sprint(int64(math.Ceil(float64(q.Value()) / float64(divisor.Value())))), ""
+}
+
+// expandEnv replaces $(NAME) with the value of a variable defined before;
+// unknown references are left as they are and $$ escapes a $. secret is set
+// when a Secret value was substituted, so the result is masked as well.
+func expandEnv(value string, defined []EnvVar) (expanded string, secret bool) {
+	if !strings.Contains(value, "$") {
+		return value, false
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case '(':
+			end := strings.IndexByte(value[i+2:], ')')
+			if end < 0 {
+				break
+			}
+			name := value[i+2 : i+2+end]
+			if v, ok := lookupEnv(defined, name); ok {
+				b.WriteString(v.Value)
+				secret = secret || v.Secret
+			} else {
+				b.WriteString(value[i : i+3+end])
+			}
+			i += 2 + end
+			continue
+		}
+		b.WriteByte('$')
+	}
+	return b.String(), secret
+}
+
+func lookupEnv(vars []EnvVar, name string) (EnvVar, bool) {
+	for _, v := range vars {
+		if v.Name == name && v.Problem == "" && v.Note == "" {
+			return v, true
+		}
+	}
+	return EnvVar{}, false
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
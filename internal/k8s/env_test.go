@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveEnv(t *testing.T) {
+	optional := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+					{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}},
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "gone"}}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "URL", Value: "http://$(HOST):8080/$$(HOST)/$(NOPE)"},
+					{Name: "DATABASE_URL", Value: "postgres://app:$(DB_password)@db"},
+					{Name: "POD", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+					{Name: "APP", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
+					{Name: "MEM_MB", ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.memory", Divisor: resource.MustParse("1Mi")}}},
+					{Name: "CPUS", ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.cpu"}}},
+					{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "token"}}},
+					{Name: "MODE", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}, Key: "MODE", Optional: &optional}}},
+					{Name: "API_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "vault"}, Key: "key"}}},
+				},
+			}},
+		},
+	}
+	sources := EnvSources{
+		ConfigMaps: map[string]map[string]string{"app-config": {"LOG_LEVEL": "info", "HOST": "localhost"}},
+		Secrets:    map[string]map[string]string{"db": {"password": "s3cret"}},
+		Errors:     map[string]error{"secret/vault": errors.New("forbidden")},
+	}
+
+	envs := ResolveEnv(pod, sources)
+	if len(envs) != 1 || envs[0].Container != "app" {
+		t.Fatalf("ResolveEnv() = %+v", envs)
+	}
+	vars := map[string]EnvVar{}
+	var names []string
+	for _, v := range envs[0].Vars {
+		vars[v.Name] = v
+		names = append(names, v.Name)
+	}
+
+	// envFrom first, env overriding it in place
+	want := []string{"HOST", "LOG_LEVEL", "DB_password", "URL", "DATABASE_URL", "POD", "APP", "MEM_MB", "CPUS", "TOKEN", "MODE", "API_KEY"}
+	if len(names) != len(want) {
+		t.Fatalf("variables = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("variables = %v, want %v", names, want)
+		}
+	}
+
+	values := map[string]string{
+		"LOG_LEVEL":    "debug",
+		"DB_password":  "s3cret",
+		"URL":          "http://localhost:8080/$(HOST)/$(NOPE)",
+		"DATABASE_URL": "postgres://app:s3cret@db",
+		"POD":          "api-1",
+		"APP":          "api",
+		"MEM_MB":       "512",
+		"CPUS":         "",
+	}
+	for name, value := range values {
+		if vars[name].Value != value {
+			t.Errorf("%s = %q, want %q", name, vars[name].Value, value)
+		}
+	}
+	if !vars["DB_password"].Secret || vars["LOG_LEVEL"].Secret || vars["DB_password"].Source != "envFrom secret/db" {
+		t.Errorf("DB_password = %+v", vars["DB_password"])
+	}
+	// A literal that expands a Secret value is masked like the Secret
+	if !vars["DATABASE_URL"].Secret || vars["URL"].Secret {
+		t.Errorf("DATABASE_URL = %+v, URL = %+v", vars["DATABASE_URL"], vars["URL"])
+	}
+	if vars["CPUS"].Note == "" || vars["CPUS"].Problem != "" {
+		t.Errorf("CPUS without a limit = %+v", vars["CPUS"])
+	}
+	if vars["TOKEN"].Problem != "key token not in secret db" {
+		t.Errorf("TOKEN = %+v", vars["TOKEN"])
+	}
+	if vars["MODE"].Problem != "" || vars["MODE"].Note == "" {
+		t.Errorf("optional MODE = %+v", vars["MODE"])
+	}
+	if vars["API_KEY"].Problem != "" || vars["API_KEY"].Note == "" {
+		t.Errorf("unreadable API_KEY = %+v", vars["API_KEY"])
+	}
+	if len(envs[0].Problems) != 1 || envs[0].Problems[0] != "envFrom configmap/gone: configmap gone not found" {
+		t.Errorf("Problems = %v", envs[0].Problems)
+	}
+	if n := envs[0].ProblemCount(); n != 2 {
+		t.Errorf("ProblemCount() = %d, want 2", n)
+	}
+}
+
+func TestResolveEnvCPUDivisor(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Name: "app",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")},
+		},
+		Env: []corev1.EnvVar{
+			{Name: "CPU_MILLIS", ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "requests.cpu", Divisor: resource.MustParse("1m")}}},
+			{Name: "CPUS", ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.cpu", Divisor: resource.MustParse("1")}}},
+		},
+	}}}}
+
+	vars := ResolveEnv(pod, EnvSources{})[0].Vars
+	if vars[0].Value != "250" || vars[1].Value != "2" {
+		t.Errorf("CPU_MILLIS = %q, CPUS = %q, want 250 and 2 (rounded up)", vars[0].Value, vars[1].Value)
+	}
+}
@@ -3,75 +3,125 @@ package k8s
 import (
 	"context"
 	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
 type EventInfo struct {
+	UID       string
 	Type      string
 	Reason    string
-	Message   string
+	Message   string // the event note for events.k8s.io/v1, message for core/v1
 	Source    string
 	Age       string
 	Count     int32
 	FirstSeen time.Time
 	LastSeen  time.Time
 	Object    string
+
+	Action              string
+	ReportingController string
+	ReportingInstance   string
+	Regarding           ObjectReference
+	Related             *ObjectReference
 }
 
-func GetPodEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string) ([]EventInfo, error) {
-	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
-		FieldSelector: "involvedObject.name=" + podName,
+type ObjectReference struct {
+	Kind       string
+	Namespace  string
+	Name       string
+	UID        string
+	APIVersion string
+	FieldPath  string
+}
+
+func (r ObjectReference) String() string {
+	if r.Kind == "" {
+		return r.Name
+	}
+	return r.Kind + "/" + r.Name
+}
+
+// EventSelector narrows an event query down to an involved object and/or
+// event type. It is translated to the field selector syntax of whichever
+// events API ends up serving the request.
+type EventSelector struct {
+	Kind string
+	Name string
+	UID  string
+	Type string
+}
+
+func (s EventSelector) fieldSelector(objectPrefix string) string {
+	var fields []string
+	if s.Kind != "" {
+		fields = append(fields, objectPrefix+".kind="+s.Kind)
+	}
+	if s.Name != "" {
+		fields = append(fields, objectPrefix+".name="+s.Name)
+	}
+	if s.UID != "" {
+		fields = append(fields, objectPrefix+".uid="+s.UID)
+	}
+	if s.Type != "" {
+		fields = append(fields, "type="+s.Type)
+	}
+	return strings.Join(fields, ",")
+}
+
+// ListEvents reads events from events.k8s.io/v1 and falls back to the
+// legacy core/v1 API on clusters (or RBAC setups) that don't serve it.
+func ListEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string, sel EventSelector) ([]EventInfo, error) {
+	events, err := clientset.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: sel.fieldSelector("regarding"),
+	})
+	if err == nil {
+		return eventsV1ToEventInfo(events.Items), nil
+	}
+	if !useCoreEventsFallback(err) {
+		return nil, err
+	}
+
+	coreEvents, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: sel.fieldSelector("involvedObject"),
 	})
 	if err != nil {
 		return nil, err
 	}
+	return eventsToEventInfo(coreEvents.Items), nil
+}
 
-	return eventsToEventInfo(events.Items), nil
+func useCoreEventsFallback(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsForbidden(err)
 }
 
+func GetPodEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string) ([]EventInfo, error) {
+	return ListEvents(ctx, clientset, namespace, EventSelector{Kind: "Pod", Name: podName})
+}
+
+// GetWorkloadEvents returns events for the workload and its whole owner
+// chain, see GetWorkloadObjects.
 func GetWorkloadEvents(ctx context.Context, clientset *kubernetes.Clientset, workload WorkloadInfo) ([]EventInfo, error) {
-	events, err := clientset.CoreV1().Events(workload.Namespace).List(ctx, metav1.ListOptions{})
+	refs, err := GetWorkloadObjects(ctx, clientset, workload)
 	if err != nil {
 		return nil, err
 	}
-
-	var filtered []corev1.Event
-	for _, e := range events.Items {
-		if e.InvolvedObject.Name == workload.Name {
-			filtered = append(filtered, e)
-			continue
-		}
-
-		if workload.Labels != nil {
-			pods, _ := GetWorkloadPods(ctx, clientset, workload)
-			for _, pod := range pods {
-				if e.InvolvedObject.Name == pod.Name {
-					filtered = append(filtered, e)
-					break
-				}
-			}
-		}
-	}
-
-	return eventsToEventInfo(filtered), nil
+	return GetObjectsEvents(ctx, clientset, workload.Namespace, refs)
 }
 
 func GetNamespaceEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string, limit int) ([]EventInfo, error) {
-	events, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	result, err := ListEvents(ctx, clientset, namespace, EventSelector{})
 	if err != nil {
 		return nil, err
 	}
 
-	result := eventsToEventInfo(events.Items)
-
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].LastSeen.After(result[j].LastSeen)
-	})
-
 	if limit > 0 && len(result) > limit {
 		result = result[:limit]
 	}
@@ -79,37 +129,214 @@ func GetNamespaceEvents(ctx context.Context, clientset *kubernetes.Clientset, na
 	return result, nil
 }
 
+func eventsV1ToEventInfo(events []eventsv1.Event) []EventInfo {
+	var result []EventInfo
+	for _, e := range events {
+		result = append(result, eventV1ToEventInfo(&e))
+	}
+	sortEventsByLastSeen(result)
+	return result
+}
+
+func eventV1ToEventInfo(e *eventsv1.Event) EventInfo {
+	firstSeen := e.EventTime.Time
+	if firstSeen.IsZero() {
+		firstSeen = e.DeprecatedFirstTimestamp.Time
+	}
+	if firstSeen.IsZero() {
+		firstSeen = e.CreationTimestamp.Time
+	}
+
+	lastSeen := firstSeen
+	count := e.DeprecatedCount
+	if e.Series != nil {
+		// A series means the event was seen again after it was first
+		// recorded; its own count and timestamp supersede the deprecated ones.
+		count = e.Series.Count
+		if !e.Series.LastObservedTime.IsZero() {
+			lastSeen = e.Series.LastObservedTime.Time
+		}
+	} else if !e.DeprecatedLastTimestamp.IsZero() && e.DeprecatedLastTimestamp.After(lastSeen) {
+		lastSeen = e.DeprecatedLastTimestamp.Time
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	source := e.ReportingController
+	if source == "" {
+		source = e.DeprecatedSource.Component
+	}
+
+	regarding := objectReferenceFrom(e.Regarding)
+	info := EventInfo{
+		UID:                 string(e.UID),
+		Type:                e.Type,
+		Reason:              e.Reason,
+		Message:             e.Note,
+		Source:              source,
+		Age:                 formatAge(lastSeen),
+		Count:               count,
+		FirstSeen:           firstSeen,
+		LastSeen:            lastSeen,
+		Object:              regarding.String(),
+		Action:              e.Action,
+		ReportingController: e.ReportingController,
+		ReportingInstance:   e.ReportingInstance,
+		Regarding:           regarding,
+	}
+	if e.Related != nil {
+		related := objectReferenceFrom(*e.Related)
+		info.Related = &related
+	}
+	return info
+}
+
 func eventsToEventInfo(events []corev1.Event) []EventInfo {
 	var result []EventInfo
 	for _, e := range events {
-		firstSeen := e.FirstTimestamp.Time
-		lastSeen := e.LastTimestamp.Time
+		result = append(result, coreEventToEventInfo(&e))
+	}
+	sortEventsByLastSeen(result)
+	return result
+}
 
-		if firstSeen.IsZero() && e.EventTime.Time.IsZero() == false {
-			firstSeen = e.EventTime.Time
+func coreEventToEventInfo(e *corev1.Event) EventInfo {
+	firstSeen := e.FirstTimestamp.Time
+	if firstSeen.IsZero() {
+		firstSeen = e.EventTime.Time
+	}
+	if firstSeen.IsZero() {
+		firstSeen = e.CreationTimestamp.Time
+	}
+
+	lastSeen := e.LastTimestamp.Time
+	count := e.Count
+	if e.Series != nil {
+		count = e.Series.Count
+		if !e.Series.LastObservedTime.IsZero() {
+			lastSeen = e.Series.LastObservedTime.Time
 		}
-		if lastSeen.IsZero() {
-			lastSeen = firstSeen
+	}
+	if lastSeen.IsZero() {
+		lastSeen = firstSeen
+	}
+	if count < 1 {
+		count = 1
+	}
+
+	source := e.Source.Component
+	if source == "" {
+		source = e.ReportingController
+	}
+
+	regarding := objectReferenceFrom(e.InvolvedObject)
+	info := EventInfo{
+		UID:                 string(e.UID),
+		Type:                e.Type,
+		Reason:              e.Reason,
+		Message:             e.Message,
+		Source:              source,
+		Age:                 formatAge(lastSeen),
+		Count:               count,
+		FirstSeen:           firstSeen,
+		LastSeen:            lastSeen,
+		Object:              regarding.String(),
+		Action:              e.Action,
+		ReportingController: e.ReportingController,
+		ReportingInstance:   e.ReportingInstance,
+		Regarding:           regarding,
+	}
+	if e.Related != nil {
+		related := objectReferenceFrom(*e.Related)
+		info.Related = &related
+	}
+	return info
+}
+
+func objectReferenceFrom(ref corev1.ObjectReference) ObjectReference {
+	return ObjectReference{
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+		UID:        string(ref.UID),
+		APIVersion: ref.APIVersion,
+		FieldPath:  ref.FieldPath,
+	}
+}
+
+func sortEventsByLastSeen(events []EventInfo) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+}
+
+// MergeEvent inserts or replaces e in events, keyed by UID, keeping the
+// newest-first order used everywhere else.
+func MergeEvent(events []EventInfo, e EventInfo) []EventInfo {
+	merged := make([]EventInfo, 0, len(events)+1)
+	replaced := false
+	for _, existing := range events {
+		if e.UID != "" && existing.UID == e.UID {
+			merged = append(merged, e)
+			replaced = true
+			continue
 		}
+		merged = append(merged, existing)
+	}
+	if !replaced {
+		merged = append(merged, e)
+	}
+	sortEventsByLastSeen(merged)
+	return merged
+}
+
+// EventWatch streams added and updated events for a selector.
+type EventWatch struct {
+	watcher watch.Interface
+}
 
-		result = append(result, EventInfo{
-			Type:      e.Type,
-			Reason:    e.Reason,
-			Message:   e.Message,
-			Source:    e.Source.Component,
-			Age:       formatAge(lastSeen),
-			Count:     e.Count,
-			FirstSeen: firstSeen,
-			LastSeen:  lastSeen,
-			Object:    e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
-		})
-	}
-
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].LastSeen.After(result[j].LastSeen)
+// WatchEvents starts a watch on events.k8s.io/v1, falling back to core/v1
+// the same way ListEvents does.
+func WatchEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string, sel EventSelector) (*EventWatch, error) {
+	w, err := clientset.EventsV1().Events(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: sel.fieldSelector("regarding"),
 	})
+	if err == nil {
+		return &EventWatch{watcher: w}, nil
+	}
+	if !useCoreEventsFallback(err) {
+		return nil, err
+	}
 
-	return result
+	w, err = clientset.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: sel.fieldSelector("involvedObject"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EventWatch{watcher: w}, nil
+}
+
+// Next blocks until the next added or modified event arrives. It returns
+// false once the watch has been stopped or closed by the server.
+func (w *EventWatch) Next() (EventInfo, bool) {
+	for change := range w.watcher.ResultChan() {
+		if change.Type != watch.Added && change.Type != watch.Modified {
+			continue
+		}
+		switch obj := change.Object.(type) {
+		case *eventsv1.Event:
+			return eventV1ToEventInfo(obj), true
+		case *corev1.Event:
+			return coreEventToEventInfo(obj), true
+		}
+	}
+	return EventInfo{}, false
+}
+
+func (w *EventWatch) Stop() {
+	w.watcher.Stop()
 }
 
 func IsWarningEvent(e EventInfo) bool {
@@ -117,7 +344,7 @@ func IsWarningEvent(e EventInfo) bool {
 }
 
 func GetRecentWarnings(ctx context.Context, clientset *kubernetes.Clientset, namespace string, since time.Duration) ([]EventInfo, error) {
-	events, err := GetNamespaceEvents(ctx, clientset, namespace, 0)
+	events, err := ListEvents(ctx, clientset, namespace, EventSelector{Type: "Warning"})
 	if err != nil {
 		return nil, err
 	}
@@ -125,9 +352,113 @@ func GetRecentWarnings(ctx context.Context, clientset *kubernetes.Clientset, nam
 	cutoff := time.Now().Add(-since)
 	var warnings []EventInfo
 	for _, e := range events {
-		if e.Type == "Warning" && e.LastSeen.After(cutoff) {
+		if e.LastSeen.After(cutoff) {
 			warnings = append(warnings, e)
 		}
 	}
 	return warnings, nil
 }
+
+// EventFilter narrows a list of events. Query matches reason and message
+// case-insensitively; empty fields match everything.
+type EventFilter struct {
+	Query  string
+	Type   string
+	Reason string
+	Source string
+}
+
+func (f EventFilter) IsEmpty() bool {
+	return f == EventFilter{}
+}
+
+func FilterEvents(events []EventInfo, f EventFilter) []EventInfo {
+	if f.IsEmpty() {
+		return events
+	}
+
+	query := strings.ToLower(f.Query)
+	var filtered []EventInfo
+	for _, e := range events {
+		if f.Type != "" && e.Type != f.Type {
+			continue
+		}
+		if f.Reason != "" && e.Reason != f.Reason {
+			continue
+		}
+		if f.Source != "" && e.Source != f.Source {
+			continue
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(e.Reason), query) &&
+			!strings.Contains(strings.ToLower(e.Message), query) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// GroupEvents collapses events with the same type, reason and message into
+// one entry: the newest event of the group with counts summed and FirstSeen
+// set to the earliest occurrence. Order follows the newest event of each group.
+func GroupEvents(events []EventInfo) []EventInfo {
+	index := map[string]int{}
+	var grouped []EventInfo
+
+	for _, e := range events {
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+
+		k := e.Type + "|" + e.Reason + "|" + e.Message
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(grouped)
+			e.Count = count
+			grouped = append(grouped, e)
+			continue
+		}
+
+		g := &grouped[i]
+		total := g.Count + count
+		firstSeen := g.FirstSeen
+		if !e.FirstSeen.IsZero() && (firstSeen.IsZero() || e.FirstSeen.Before(firstSeen)) {
+			firstSeen = e.FirstSeen
+		}
+		if e.LastSeen.After(g.LastSeen) {
+			*g = e
+		}
+		g.Count = total
+		g.FirstSeen = firstSeen
+	}
+
+	sortEventsByLastSeen(grouped)
+	return grouped
+}
+
+// EventReasons and EventSources list the distinct non-empty values present
+// in events, sorted, for building filter choices.
+func EventReasons(events []EventInfo) []string {
+	return distinctEventValues(events, func(e EventInfo) string { return e.Reason })
+}
+
+func EventSources(events []EventInfo) []string {
+	return distinctEventValues(events, func(e EventInfo) string { return e.Source })
+}
+
+func distinctEventValues(events []EventInfo, value func(EventInfo) string) []string {
+	seen := map[string]bool{}
+	var values []string
+	for _, e := range events {
+		v := value(e)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
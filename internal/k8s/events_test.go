@@ -0,0 +1,282 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEventSelectorFieldSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		sel      EventSelector
+		prefix   string
+		expected string
+	}{
+		{
+			name:     "empty selector",
+			sel:      EventSelector{},
+			prefix:   "regarding",
+			expected: "",
+		},
+		{
+			name:     "pod by name for events.k8s.io",
+			sel:      EventSelector{Kind: "Pod", Name: "api-1"},
+			prefix:   "regarding",
+			expected: "regarding.kind=Pod,regarding.name=api-1",
+		},
+		{
+			name:     "uid and type for core",
+			sel:      EventSelector{UID: "abc", Type: "Warning"},
+			prefix:   "involvedObject",
+			expected: "involvedObject.uid=abc,type=Warning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.sel.fieldSelector(tt.prefix)
+			if result != tt.expected {
+				t.Errorf("fieldSelector(%q) = %q, want %q", tt.prefix, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventV1ToEventInfoSeries(t *testing.T) {
+	first := time.Now().Add(-30 * time.Minute)
+	last := time.Now().Add(-1 * time.Minute)
+
+	e := &eventsv1.Event{
+		ObjectMeta:          metav1.ObjectMeta{UID: "e1"},
+		EventTime:           metav1.NewMicroTime(first),
+		Series:              &eventsv1.EventSeries{Count: 42, LastObservedTime: metav1.NewMicroTime(last)},
+		ReportingController: "kubelet",
+		ReportingInstance:   "kubelet-node-1",
+		Action:              "Pulling",
+		Reason:              "BackOff",
+		Note:                "Back-off restarting failed container",
+		Type:                "Warning",
+		Regarding:           corev1.ObjectReference{Kind: "Pod", Name: "api-1", Namespace: "payments", UID: "p1"},
+		Related:             &corev1.ObjectReference{Kind: "Node", Name: "node-1"},
+		DeprecatedCount:     3,
+	}
+
+	info := eventV1ToEventInfo(e)
+
+	if info.Count != 42 {
+		t.Errorf("Count = %d, want 42 from series", info.Count)
+	}
+	if !info.FirstSeen.Equal(first) {
+		t.Errorf("FirstSeen = %v, want %v", info.FirstSeen, first)
+	}
+	if !info.LastSeen.Equal(last) {
+		t.Errorf("LastSeen = %v, want series last observed time %v", info.LastSeen, last)
+	}
+	if info.Age != "1m" {
+		t.Errorf("Age = %q, want %q", info.Age, "1m")
+	}
+	if info.Message != e.Note {
+		t.Errorf("Message = %q, want note %q", info.Message, e.Note)
+	}
+	if info.Source != "kubelet" || info.ReportingController != "kubelet" || info.ReportingInstance != "kubelet-node-1" {
+		t.Errorf("reporting fields = %q/%q/%q", info.Source, info.ReportingController, info.ReportingInstance)
+	}
+	if info.Object != "Pod/api-1" || info.Regarding.UID != "p1" || info.Regarding.Namespace != "payments" {
+		t.Errorf("Regarding = %+v, Object = %q", info.Regarding, info.Object)
+	}
+	if info.Related == nil || info.Related.String() != "Node/node-1" {
+		t.Errorf("Related = %+v, want Node/node-1", info.Related)
+	}
+}
+
+func TestEventV1ToEventInfoDeprecatedFields(t *testing.T) {
+	first := time.Now().Add(-2 * time.Hour)
+	last := time.Now().Add(-10 * time.Minute)
+
+	e := &eventsv1.Event{
+		DeprecatedFirstTimestamp: metav1.NewTime(first),
+		DeprecatedLastTimestamp:  metav1.NewTime(last),
+		DeprecatedCount:          7,
+		DeprecatedSource:         corev1.EventSource{Component: "default-scheduler"},
+		Reason:                   "FailedScheduling",
+		Type:                     "Warning",
+	}
+
+	info := eventV1ToEventInfo(e)
+
+	if info.Count != 7 {
+		t.Errorf("Count = %d, want 7", info.Count)
+	}
+	if !info.LastSeen.Equal(last) {
+		t.Errorf("LastSeen = %v, want %v", info.LastSeen, last)
+	}
+	if info.Source != "default-scheduler" {
+		t.Errorf("Source = %q, want deprecated source component", info.Source)
+	}
+}
+
+func TestCoreEventToEventInfo(t *testing.T) {
+	eventTime := time.Now().Add(-20 * time.Minute)
+	observed := time.Now().Add(-2 * time.Minute)
+
+	tests := []struct {
+		name      string
+		event     corev1.Event
+		wantCount int32
+		wantLast  time.Time
+	}{
+		{
+			name: "legacy count and timestamps",
+			event: corev1.Event{
+				FirstTimestamp: metav1.NewTime(eventTime),
+				LastTimestamp:  metav1.NewTime(observed),
+				Count:          5,
+			},
+			wantCount: 5,
+			wantLast:  observed,
+		},
+		{
+			name: "new style event with series",
+			event: corev1.Event{
+				EventTime: metav1.NewMicroTime(eventTime),
+				Series:    &corev1.EventSeries{Count: 9, LastObservedTime: metav1.NewMicroTime(observed)},
+			},
+			wantCount: 9,
+			wantLast:  observed,
+		},
+		{
+			name: "single new style event",
+			event: corev1.Event{
+				EventTime: metav1.NewMicroTime(eventTime),
+			},
+			wantCount: 1,
+			wantLast:  eventTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := coreEventToEventInfo(&tt.event)
+			if info.Count != tt.wantCount {
+				t.Errorf("Count = %d, want %d", info.Count, tt.wantCount)
+			}
+			if !info.LastSeen.Equal(tt.wantLast) {
+				t.Errorf("LastSeen = %v, want %v", info.LastSeen, tt.wantLast)
+			}
+			if !info.FirstSeen.Equal(eventTime) {
+				t.Errorf("FirstSeen = %v, want %v", info.FirstSeen, eventTime)
+			}
+		})
+	}
+}
+
+func TestMergeEvent(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		{UID: "a", Reason: "Pulled", LastSeen: now.Add(-time.Minute)},
+		{UID: "b", Reason: "Scheduled", LastSeen: now.Add(-time.Hour)},
+	}
+
+	// Update of an existing event moves it by its new LastSeen
+	events = MergeEvent(events, EventInfo{UID: "b", Reason: "Scheduled", Count: 2, LastSeen: now})
+	if len(events) != 2 {
+		t.Fatalf("len = %d, want 2 after update", len(events))
+	}
+	if events[0].UID != "b" || events[0].Count != 2 {
+		t.Errorf("events[0] = %+v, want updated event b first", events[0])
+	}
+
+	// New event is appended in order
+	events = MergeEvent(events, EventInfo{UID: "c", Reason: "BackOff", LastSeen: now.Add(-30 * time.Minute)})
+	if len(events) != 3 {
+		t.Fatalf("len = %d, want 3 after insert", len(events))
+	}
+	if events[2].UID != "c" {
+		t.Errorf("events[2].UID = %q, want c", events[2].UID)
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	events := []EventInfo{
+		{UID: "1", Type: "Normal", Reason: "Pulling", Message: "Pulling image nginx", Source: "kubelet"},
+		{UID: "2", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Source: "kubelet"},
+		{UID: "3", Type: "Normal", Reason: "Scheduled", Message: "Successfully assigned default/api to node-1", Source: "default-scheduler"},
+		{UID: "4", Type: "Warning", Reason: "FailedMount", Message: "MountVolume.SetUp failed for volume \"config\"", Source: "kubelet"},
+	}
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected []string
+	}{
+		{"empty filter", EventFilter{}, []string{"1", "2", "3", "4"}},
+		{"query matches message", EventFilter{Query: "NGINX"}, []string{"1"}},
+		{"query matches reason", EventFilter{Query: "backoff"}, []string{"2"}},
+		{"by type", EventFilter{Type: "Warning"}, []string{"2", "4"}},
+		{"by reason", EventFilter{Reason: "Scheduled"}, []string{"3"}},
+		{"by source", EventFilter{Source: "kubelet"}, []string{"1", "2", "4"}},
+		{"combined", EventFilter{Type: "Warning", Source: "kubelet", Query: "volume"}, []string{"4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uids []string
+			for _, e := range FilterEvents(events, tt.filter) {
+				uids = append(uids, e.UID)
+			}
+			if strings.Join(uids, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FilterEvents() = %v, want %v", uids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		{UID: "1", Type: "Normal", Reason: "Pulled", Message: "Container image already present", Count: 1, FirstSeen: now.Add(-3 * time.Minute), LastSeen: now.Add(-3 * time.Minute)},
+		{UID: "2", Type: "Normal", Reason: "Pulled", Message: "Container image already present", Count: 4, FirstSeen: now.Add(-10 * time.Minute), LastSeen: now.Add(-1 * time.Minute)},
+		{UID: "3", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 0, LastSeen: now.Add(-2 * time.Minute)},
+		{UID: "4", Type: "Normal", Reason: "Pulled", Message: "Successfully pulled image", Count: 1, LastSeen: now.Add(-5 * time.Minute)},
+	}
+
+	grouped := GroupEvents(events)
+	if len(grouped) != 3 {
+		t.Fatalf("GroupEvents() returned %d entries, want 3", len(grouped))
+	}
+
+	first := grouped[0]
+	if first.UID != "2" || first.Count != 5 {
+		t.Errorf("first entry = %s x%d, want newest event 2 with count 5", first.UID, first.Count)
+	}
+	if !first.FirstSeen.Equal(now.Add(-10 * time.Minute)) {
+		t.Errorf("first entry FirstSeen = %v, want earliest occurrence", first.FirstSeen)
+	}
+	if grouped[1].UID != "3" || grouped[1].Count != 1 {
+		t.Errorf("second entry = %s x%d, want 3 x1", grouped[1].UID, grouped[1].Count)
+	}
+	if grouped[2].UID != "4" {
+		t.Errorf("third entry = %s, want 4", grouped[2].UID)
+	}
+}
+
+func TestEventReasonsAndSources(t *testing.T) {
+	events := []EventInfo{
+		{Reason: "Pulled", Source: "kubelet"},
+		{Reason: "BackOff", Source: "kubelet"},
+		{Reason: "Scheduled", Source: "default-scheduler"},
+		{Reason: "Pulled"},
+	}
+
+	if got := strings.Join(EventReasons(events), ","); got != "BackOff,Pulled,Scheduled" {
+		t.Errorf("EventReasons() = %s", got)
+	}
+	if got := strings.Join(EventSources(events), ","); got != "default-scheduler,kubelet" {
+		t.Errorf("EventSources() = %s", got)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 type LogLine struct {
 	Timestamp time.Time
+	Pod       string // only set by log providers that span several pods
 	Container string
 	Content   string
 	IsError   bool
@@ -36,6 +37,44 @@ func DefaultLogOptions() LogOptions {
 	}
 }
 
+// LogProvider is an optional source of logs beyond what the kubelet still
+// has, typically a log aggregation backend. It is queried by labels and a
+// time range so pods that were already replaced can be found.
+type LogProvider interface {
+	Name() string
+	QueryLogs(ctx context.Context, query HistoricalLogQuery) ([]LogLine, error)
+}
+
+type HistoricalLogQuery struct {
+	Namespace string
+	Labels    map[string]string
+	Pod       string // optional, leave empty to match every pod with Labels
+	Container string
+	Start     time.Time
+	End       time.Time
+	Limit     int
+}
+
+// revisionLabels change with every rollout, so they are dropped when pod
+// labels are used to look up logs of a workload's previous pods.
+var revisionLabels = []string{
+	"pod-template-hash",
+	"controller-revision-hash",
+	"statefulset.kubernetes.io/pod-name",
+	"apps.kubernetes.io/pod-index",
+}
+
+func StableLabels(labels map[string]string) map[string]string {
+	stable := make(map[string]string, len(labels))
+	for k, v := range labels {
+		stable[k] = v
+	}
+	for _, k := range revisionLabels {
+		delete(stable, k)
+	}
+	return stable
+}
+
 func GetPodLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace, podName string, opts LogOptions) ([]LogLine, error) {
 	podLogOpts := &corev1.PodLogOptions{
 		Container:  opts.Container,
@@ -62,6 +101,12 @@ func GetPodLogs(ctx context.Context, clientset *kubernetes.Clientset, namespace,
 	return parseLogStream(stream, opts.Container, opts.Timestamps)
 }
 
+// ParseLogs reads log lines written with kubelet-style timestamps, as
+// kubectl logs --timestamps prints them, for one container.
+func ParseLogs(reader io.Reader, container string) ([]LogLine, error) {
+	return parseLogStream(reader, container, true)
+}
+
 func parseLogStream(reader io.Reader, container string, hasTimestamps bool) ([]LogLine, error) {
 	var lines []LogLine
 	scanner := bufio.NewScanner(reader)
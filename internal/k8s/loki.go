@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LokiProvider reads logs through the Loki HTTP query API. It only needs
+// the labels promtail (or a compatible agent) attaches to each stream, so
+// it keeps working after the pod itself has been deleted.
+type LokiProvider struct {
+	baseURL    string
+	tenant     string
+	httpClient *http.Client
+}
+
+func NewLokiProvider(baseURL, tenant string) *LokiProvider {
+	return &LokiProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		tenant:     tenant,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *LokiProvider) Name() string {
+	return "loki"
+}
+
+type lokiResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (p *LokiProvider) QueryLogs(ctx context.Context, query HistoricalLogQuery) ([]LogLine, error) {
+	end := query.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := query.Start
+	if start.IsZero() {
+		start = end.Add(-time.Hour)
+	}
+
+	params := url.Values{}
+	params.Set("query", LokiSelector(query))
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	params.Set("direction", "backward")
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", p.tenant)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("loki query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("loki query failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var parsed lokiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode loki response: %w", err)
+	}
+	if parsed.Data.ResultType != "" && parsed.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("unexpected loki result type: %s", parsed.Data.ResultType)
+	}
+
+	var lines []LogLine
+	for _, stream := range parsed.Data.Result {
+		for _, v := range stream.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			lines = append(lines, LogLine{
+				Timestamp: time.Unix(0, ns),
+				Pod:       stream.Stream["pod"],
+				Container: stream.Stream["container"],
+				Content:   v[1],
+				IsError:   isErrorLine(v[1]),
+			})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Timestamp.Before(lines[j].Timestamp)
+	})
+	return lines, nil
+}
+
+var lokiLabelInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// LokiSelector builds the LogQL stream selector for a query. Kubernetes
+// label keys are mapped the way promtail does it, e.g.
+// app.kubernetes.io/name becomes app_kubernetes_io_name.
+func LokiSelector(query HistoricalLogQuery) string {
+	matchers := map[string]string{}
+	for k, v := range query.Labels {
+		matchers[lokiLabelInvalidChars.ReplaceAllString(k, "_")] = v
+	}
+	if query.Namespace != "" {
+		matchers["namespace"] = query.Namespace
+	}
+	if query.Pod != "" {
+		matchers["pod"] = query.Pod
+	}
+	if query.Container != "" {
+		matchers["container"] = query.Container
+	}
+
+	keys := make([]string, 0, len(matchers))
+	for k := range matchers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+strconv.Quote(matchers[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
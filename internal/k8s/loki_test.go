@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLokiSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    HistoricalLogQuery
+		expected string
+	}{
+		{
+			name:     "namespace only",
+			query:    HistoricalLogQuery{Namespace: "payments"},
+			expected: `{namespace="payments"}`,
+		},
+		{
+			name: "workload labels are sanitized and sorted",
+			query: HistoricalLogQuery{
+				Namespace: "payments",
+				Labels:    map[string]string{"app.kubernetes.io/name": "api", "tier": "backend"},
+			},
+			expected: `{app_kubernetes_io_name="api", namespace="payments", tier="backend"}`,
+		},
+		{
+			name: "pod and container",
+			query: HistoricalLogQuery{
+				Namespace: "payments",
+				Pod:       "api-7d9f",
+				Container: "app",
+			},
+			expected: `{container="app", namespace="payments", pod="api-7d9f"}`,
+		},
+		{
+			name: "values are quoted",
+			query: HistoricalLogQuery{
+				Labels: map[string]string{"app": `we"ird`},
+			},
+			expected: `{app="we\"ird"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LokiSelector(tt.query)
+			if result != tt.expected {
+				t.Errorf("LokiSelector() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLokiProviderQueryLogs(t *testing.T) {
+	var gotQuery, gotTenant, gotLimit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		gotQuery = r.URL.Query().Get("query")
+		gotLimit = r.URL.Query().Get("limit")
+		gotTenant = r.Header.Get("X-Scope-OrgID")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"status": "success",
+			"data": {
+				"resultType": "streams",
+				"result": [
+					{
+						"stream": {"namespace": "payments", "pod": "api-old", "container": "app"},
+						"values": [
+							["1700000002000000000", "panic: nil map"],
+							["1700000001000000000", "starting server"]
+						]
+					},
+					{
+						"stream": {"namespace": "payments", "pod": "api-new", "container": "app"},
+						"values": [["1700000003000000000", "ready"]]
+					}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := NewLokiProvider(server.URL+"/", "team-a")
+	logs, err := provider.QueryLogs(context.Background(), HistoricalLogQuery{
+		Namespace: "payments",
+		Labels:    map[string]string{"app": "api"},
+		Start:     time.Unix(1700000000, 0),
+		End:       time.Unix(1700000100, 0),
+		Limit:     50,
+	})
+	if err != nil {
+		t.Fatalf("QueryLogs() error = %v", err)
+	}
+
+	if gotQuery != `{app="api", namespace="payments"}` {
+		t.Errorf("query = %q", gotQuery)
+	}
+	if gotTenant != "team-a" {
+		t.Errorf("tenant header = %q, want %q", gotTenant, "team-a")
+	}
+	if gotLimit != "50" {
+		t.Errorf("limit = %q, want %q", gotLimit, "50")
+	}
+
+	if len(logs) != 3 {
+		t.Fatalf("got %d lines, want 3", len(logs))
+	}
+	expected := []struct {
+		pod     string
+		content string
+		isError bool
+	}{
+		{"api-old", "starting server", false},
+		{"api-old", "panic: nil map", true},
+		{"api-new", "ready", false},
+	}
+	for i, want := range expected {
+		if logs[i].Pod != want.pod || logs[i].Content != want.content || logs[i].IsError != want.isError {
+			t.Errorf("logs[%d] = %+v, want pod=%s content=%q isError=%v", i, logs[i], want.pod, want.content, want.isError)
+		}
+		if logs[i].Container != "app" {
+			t.Errorf("logs[%d].Container = %q, want %q", i, logs[i].Container, "app")
+		}
+	}
+}
+
+func TestLokiProviderQueryLogsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "parse error", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	provider := NewLokiProvider(server.URL, "")
+	if _, err := provider.QueryLogs(context.Background(), HistoricalLogQuery{Namespace: "x"}); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestStableLabels(t *testing.T) {
+	labels := map[string]string{
+		"app":               "api",
+		"pod-template-hash": "7d9f",
+	}
+	stable := StableLabels(labels)
+	if _, ok := stable["pod-template-hash"]; ok {
+		t.Error("StableLabels() kept pod-template-hash")
+	}
+	if stable["app"] != "api" {
+		t.Errorf("StableLabels()[app] = %q, want %q", stable["app"], "api")
+	}
+	if _, ok := labels["pod-template-hash"]; !ok {
+		t.Error("StableLabels() modified its input")
+	}
+}
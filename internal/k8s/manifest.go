@@ -0,0 +1,225 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Object is what the typed clients return: object metadata plus type meta.
+type Object interface {
+	metav1.Object
+	runtime.Object
+}
+
+// ManifestFormat is how a live object is printed.
+type ManifestFormat int
+
+const (
+	ManifestYAML ManifestFormat = iota
+	ManifestJSON
+)
+
+func (f ManifestFormat) String() string {
+	if f == ManifestJSON {
+		return "JSON"
+	}
+	return "YAML"
+}
+
+// GetObject fetches the object ref names with its apiVersion and kind set,
+// which the typed clients leave empty. Secrets are deliberately not
+// supported.
+func GetObject(ctx context.Context, clientset *kubernetes.Clientset, ref ObjectReference) (Object, error) {
+	get := metav1.GetOptions{}
+	ns := ref.Namespace
+	var obj Object
+	var err error
+	gv := appsv1.SchemeGroupVersion
+	switch ref.Kind {
+	case "Pod":
+		gv = corev1.SchemeGroupVersion
+		obj, err = clientset.CoreV1().Pods(ns).Get(ctx, ref.Name, get)
+	case "ConfigMap":
+		gv = corev1.SchemeGroupVersion
+		obj, err = clientset.CoreV1().ConfigMaps(ns).Get(ctx, ref.Name, get)
+	case "Service":
+		gv = corev1.SchemeGroupVersion
+		obj, err = clientset.CoreV1().Services(ns).Get(ctx, ref.Name, get)
+	case "Ingress":
+		gv = networkingv1.SchemeGroupVersion
+		obj, err = clientset.NetworkingV1().Ingresses(ns).Get(ctx, ref.Name, get)
+	case "ReplicaSet":
+		obj, err = clientset.AppsV1().ReplicaSets(ns).Get(ctx, ref.Name, get)
+	case "Deployment":
+		obj, err = clientset.AppsV1().Deployments(ns).Get(ctx, ref.Name, get)
+	case "StatefulSet":
+		obj, err = clientset.AppsV1().StatefulSets(ns).Get(ctx, ref.Name, get)
+	case "DaemonSet":
+		obj, err = clientset.AppsV1().DaemonSets(ns).Get(ctx, ref.Name, get)
+	case "Job":
+		gv = batchv1.SchemeGroupVersion
+		obj, err = clientset.BatchV1().Jobs(ns).Get(ctx, ref.Name, get)
+	case "CronJob":
+		gv = batchv1.SchemeGroupVersion
+		obj, err = clientset.BatchV1().CronJobs(ns).Get(ctx, ref.Name, get)
+	default:
+		return nil, fmt.Errorf("kind %s is not supported", ref.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gv.WithKind(ref.Kind))
+	return obj, nil
+}
+
+// ManifestFields converts a typed object to the generic form printed by
+// FormatManifest.
+func ManifestFields(obj runtime.Object) (map[string]interface{}, error) {
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// FormatManifest prints an object as kubectl get -o yaml or -o json would.
+// With hideNoise, metadata.managedFields and status are left out, which is
+// usually most of a pod.
+func FormatManifest(fields map[string]interface{}, format ManifestFormat, hideNoise bool) (string, error) {
+	if hideNoise {
+		trimmed := make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			trimmed[k] = v
+		}
+		delete(trimmed, "status")
+		if meta, ok := fields["metadata"].(map[string]interface{}); ok {
+			m := make(map[string]interface{}, len(meta))
+			for k, v := range meta {
+				m[k] = v
+			}
+			delete(m, "managedFields")
+			trimmed["metadata"] = m
+		}
+		fields = trimmed
+	}
+
+	var data []byte
+	var err error
+	if format == ManifestJSON {
+		data, err = json.MarshalIndent(fields, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(fields)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// editTypes are the typed objects strategic merge patches are computed
+// against, for the patch strategy of lists such as containers and env.
+var editTypes = map[string]interface{}{
+	"Pod":         corev1.Pod{},
+	"ConfigMap":   corev1.ConfigMap{},
+	"Service":     corev1.Service{},
+	"Ingress":     networkingv1.Ingress{},
+	"ReplicaSet":  appsv1.ReplicaSet{},
+	"Deployment":  appsv1.Deployment{},
+	"StatefulSet": appsv1.StatefulSet{},
+	"DaemonSet":   appsv1.DaemonSet{},
+	"Job":         batchv1.Job{},
+	"CronJob":     batchv1.CronJob{},
+}
+
+// EditPatch returns the strategic merge patch turning live into edited,
+// both YAML or JSON manifests of an object of kind, as kubectl edit
+// computes it: fields removed in the edit are deleted. The patch carries
+// live's resourceVersion, so it fails with a conflict if the object changed
+// since live was read.
+func EditPatch(kind string, live, edited []byte) ([]byte, error) {
+	dataStruct, ok := editTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("kind %s is not supported", kind)
+	}
+	original, err := yaml.YAMLToJSON(live)
+	if err != nil {
+		return nil, err
+	}
+	// Syntax errors are reported with their line before reaching the server
+	modified, err := yaml.YAMLToJSON(edited)
+	if err != nil {
+		return nil, err
+	}
+	data, err := strategicpatch.CreateTwoWayMergePatch(original, modified, dataStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch, liveFields map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(original, &liveFields); err != nil {
+		return nil, err
+	}
+	liveMeta, _ := liveFields["metadata"].(map[string]interface{})
+	if version, ok := liveMeta["resourceVersion"].(string); ok {
+		meta, _ := patch["metadata"].(map[string]interface{})
+		if meta == nil {
+			meta = map[string]interface{}{}
+			patch["metadata"] = meta
+		}
+		meta["resourceVersion"] = version
+	}
+	return json.Marshal(patch)
+}
+
+// PatchObject sends a strategic merge patch from EditPatch for the object
+// ref names. With dryRun the server validates the change and returns what
+// it would store without persisting it.
+func PatchObject(ctx context.Context, clientset *kubernetes.Clientset, ref ObjectReference, data []byte, dryRun bool) (Object, error) {
+	opts := metav1.PatchOptions{}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	ns, pt := ref.Namespace, types.StrategicMergePatchType
+	var obj Object
+	var err error
+	switch ref.Kind {
+	case "Pod":
+		obj, err = clientset.CoreV1().Pods(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "ConfigMap":
+		obj, err = clientset.CoreV1().ConfigMaps(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "Service":
+		obj, err = clientset.CoreV1().Services(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "Ingress":
+		obj, err = clientset.NetworkingV1().Ingresses(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "ReplicaSet":
+		obj, err = clientset.AppsV1().ReplicaSets(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "Deployment":
+		obj, err = clientset.AppsV1().Deployments(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "StatefulSet":
+		obj, err = clientset.AppsV1().StatefulSets(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "DaemonSet":
+		obj, err = clientset.AppsV1().DaemonSets(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "Job":
+		obj, err = clientset.BatchV1().Jobs(ns).Patch(ctx, ref.Name, pt, data, opts)
+	case "CronJob":
+		obj, err = clientset.BatchV1().CronJobs(ns).Patch(ctx, ref.Name, pt, data, opts)
+	default:
+		return nil, fmt.Errorf("kind %s is not supported", ref.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFormatManifest(t *testing.T) {
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "api-1",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "api:1.2"}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	fields, err := ManifestFields(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := FormatManifest(fields, ManifestYAML, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"kind: Pod\n", "  name: api-1\n", "managedFields:", "phase: Running", "image: api:1.2"} {
+		if !strings.Contains(full, want) {
+			t.Errorf("YAML is missing %q\n%s", want, full)
+		}
+	}
+
+	trimmed, err := FormatManifest(fields, ManifestYAML, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(trimmed, "managedFields") || strings.Contains(trimmed, "status:") || !strings.Contains(trimmed, "name: api-1") {
+		t.Errorf("hidden noise YAML =\n%s", trimmed)
+	}
+	// Hiding works on a copy
+	if _, ok := fields["status"]; !ok {
+		t.Error("FormatManifest removed status from its input")
+	}
+
+	json, err := FormatManifest(fields, ManifestJSON, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(json, "{\n  \"apiVersion\": \"v1\",") || strings.Contains(json, "managedFields") {
+		t.Errorf("JSON =\n%s", json)
+	}
+}
+
+func TestGetObjectRejectsOtherKinds(t *testing.T) {
+	if _, err := GetObject(context.Background(), nil, ObjectReference{Kind: "Secret", Name: "db"}); err == nil {
+		t.Error("GetObject should not fetch Secrets")
+	}
+}
+
+func TestEditPatch(t *testing.T) {
+	live := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  resourceVersion: "42"
+  labels: {app: api, team: payments}
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: api:1.2
+        env: [{name: MODE, value: prod}, {name: DEBUG, value: "1"}]
+`)
+	// The label, the env var and the image tag are changed or removed
+	edited := []byte(`# Editing deployment/api
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  resourceVersion: "42"
+  labels: {app: api}
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: api:1.3
+        env: [{name: MODE, value: prod}]
+`)
+
+	data, err := EditPatch("Deployment", live, edited)
+	if err != nil {
+		t.Fatalf("EditPatch() error = %v", err)
+	}
+	var patch map[string]interface{}
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatal(err)
+	}
+	meta := patch["metadata"].(map[string]interface{})
+	if labels := meta["labels"].(map[string]interface{}); len(labels) != 1 || labels["team"] != nil {
+		t.Errorf("labels patch = %v, want team deleted", labels)
+	}
+	if meta["resourceVersion"] != "42" {
+		t.Errorf("resourceVersion = %v, want 42", meta["resourceVersion"])
+	}
+	for _, want := range []string{`"image":"api:1.3"`, `"$patch":"delete"`, `"DEBUG"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("EditPatch() = %s, missing %s", data, want)
+		}
+	}
+
+	if _, err := EditPatch("Deployment", live, []byte("spec: [")); err == nil {
+		t.Error("EditPatch() of invalid YAML should fail")
+	}
+	if _, err := EditPatch("Secret", live, edited); err == nil {
+		t.Error("EditPatch() should not support Secrets")
+	}
+}
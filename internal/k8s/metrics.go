@@ -3,14 +3,18 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type PodMetrics struct {
 	Name       string
 	Namespace  string
+	Timestamp  time.Time // when metrics-server scraped the sample
 	Containers []ContainerMetrics
 }
 
@@ -18,6 +22,8 @@ type ContainerMetrics struct {
 	Name        string
 	CPUUsage    string
 	MemoryUsage string
+	CPUMilli    int64
+	MemoryBytes int64
 	CPUPercent  float64
 	MemPercent  float64
 }
@@ -32,23 +38,8 @@ func GetPodMetrics(ctx context.Context, metricsClient *metricsv.Clientset, names
 		return nil, err
 	}
 
-	pm := &PodMetrics{
-		Name:      metrics.Name,
-		Namespace: metrics.Namespace,
-	}
-
-	for _, c := range metrics.Containers {
-		cpu := c.Usage.Cpu()
-		mem := c.Usage.Memory()
-
-		pm.Containers = append(pm.Containers, ContainerMetrics{
-			Name:        c.Name,
-			CPUUsage:    formatCPU(cpu.MilliValue()),
-			MemoryUsage: formatMemory(mem.Value()),
-		})
-	}
-
-	return pm, nil
+	pm := podMetricsFrom(metrics)
+	return &pm, nil
 }
 
 func GetNamespaceMetrics(ctx context.Context, metricsClient *metricsv.Clientset, namespace string) ([]PodMetrics, error) {
@@ -62,26 +53,62 @@ func GetNamespaceMetrics(ctx context.Context, metricsClient *metricsv.Clientset,
 	}
 
 	var result []PodMetrics
-	for _, m := range metricsList.Items {
-		pm := PodMetrics{
-			Name:      m.Name,
-			Namespace: m.Namespace,
-		}
+	for i := range metricsList.Items {
+		result = append(result, podMetricsFrom(&metricsList.Items[i]))
+	}
 
-		for _, c := range m.Containers {
-			cpu := c.Usage.Cpu()
-			mem := c.Usage.Memory()
+	return result, nil
+}
 
-			pm.Containers = append(pm.Containers, ContainerMetrics{
-				Name:        c.Name,
-				CPUUsage:    formatCPU(cpu.MilliValue()),
-				MemoryUsage: formatMemory(mem.Value()),
-			})
-		}
-		result = append(result, pm)
+func podMetricsFrom(m *metricsv1beta1.PodMetrics) PodMetrics {
+	pm := PodMetrics{
+		Name:      m.Name,
+		Namespace: m.Namespace,
+		Timestamp: m.Timestamp.Time,
 	}
 
-	return result, nil
+	for _, c := range m.Containers {
+		cpu := c.Usage.Cpu().MilliValue()
+		mem := c.Usage.Memory().Value()
+
+		pm.Containers = append(pm.Containers, ContainerMetrics{
+			Name:        c.Name,
+			CPUUsage:    formatCPU(cpu),
+			MemoryUsage: formatMemory(mem),
+			CPUMilli:    cpu,
+			MemoryBytes: mem,
+		})
+	}
+	return pm
+}
+
+// CPUMilli parses a CPU quantity such as "250m" or "2" into millicores,
+// returning 0 when the value is unset or invalid.
+func CPUMilli(quantity string) int64 {
+	q, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+// MemoryBytes parses a memory quantity such as "512Mi" into bytes,
+// returning 0 when the value is unset or invalid.
+func MemoryBytes(quantity string) int64 {
+	q, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return 0
+	}
+	return q.Value()
+}
+
+// FormatCPU and FormatMemory render raw usage the same way metrics are shown.
+func FormatCPU(milliCores int64) string {
+	return formatCPU(milliCores)
+}
+
+func FormatMemory(bytes int64) string {
+	return formatMemory(bytes)
 }
 
 func formatCPU(milliCores int64) string {
@@ -110,13 +137,55 @@ func formatMemory(bytes int64) string {
 	}
 }
 
+// ContainerUsage compares a container's live usage with its requests and
+// limits. Percentages are 0 when the corresponding request or limit is unset.
+type ContainerUsage struct {
+	Name               string
+	CPUMilli           int64
+	MemoryBytes        int64
+	CPURequestMilli    int64
+	CPULimitMilli      int64
+	MemoryRequestBytes int64
+	MemoryLimitBytes   int64
+	CPURequestPercent  float64
+	CPULimitPercent    float64
+	MemRequestPercent  float64
+	MemLimitPercent    float64
+}
+
+// ResourceUsageSummary holds per-container usage and pod totals. CPUPercent
+// and MemPercent are relative to the pod's total limit, falling back to the
+// total request when no limit is set. Only the usage of containers that set
+// a limit (or request) counts towards it.
 type ResourceUsageSummary struct {
 	CPUUsed     string
 	CPUPercent  float64
 	MemUsed     string
 	MemPercent  float64
-	IsThrottled bool
-	IsOOM       bool
+	IsThrottled bool // some container is using at least ThrottleThreshold of its CPU limit
+	IsOOM       bool // some container is using at least OOMThreshold of its memory limit
+
+	Containers []ContainerUsage
+	Total      ContainerUsage
+}
+
+const (
+	ThrottleThreshold = 90.0
+	OOMThreshold      = 90.0
+)
+
+func percentOf(used, of int64) float64 {
+	if of <= 0 {
+		return 0
+	}
+	return float64(used) * 100 / float64(of)
+}
+
+func (u *ContainerUsage) computePercents() {
+	u.CPURequestPercent = percentOf(u.CPUMilli, u.CPURequestMilli)
+	u.CPULimitPercent = percentOf(u.CPUMilli, u.CPULimitMilli)
+	u.MemRequestPercent = percentOf(u.MemoryBytes, u.MemoryRequestBytes)
+	u.MemLimitPercent = percentOf(u.MemoryBytes, u.MemoryLimitBytes)
 }
 
 func CalculateResourceUsage(metrics *PodMetrics, pod *PodInfo) *ResourceUsageSummary {
@@ -125,16 +194,102 @@ func CalculateResourceUsage(metrics *PodMetrics, pod *PodInfo) *ResourceUsageSum
 	}
 
 	summary := &ResourceUsageSummary{}
+	total := &summary.Total
+	total.Name = pod.Name
 
-	var totalCPU int64
-	var totalMem int64
+	// Usage of the containers that set a request or limit, so a container
+	// without one does not count against the others' limits
+	var cpuRequested, cpuLimited, memRequested, memLimited int64
 
+	usageByName := map[string]ContainerMetrics{}
 	for _, cm := range metrics.Containers {
-		_ = cm
+		usageByName[cm.Name] = cm
+	}
+
+	for _, c := range pod.Containers {
+		cm := usageByName[c.Name]
+		u := ContainerUsage{
+			Name:               c.Name,
+			CPUMilli:           cm.CPUMilli,
+			MemoryBytes:        cm.MemoryBytes,
+			CPURequestMilli:    CPUMilli(c.Resources.CPURequest),
+			CPULimitMilli:      CPUMilli(c.Resources.CPULimit),
+			MemoryRequestBytes: MemoryBytes(c.Resources.MemoryRequest),
+			MemoryLimitBytes:   MemoryBytes(c.Resources.MemoryLimit),
+		}
+		u.computePercents()
+
+		if u.CPULimitPercent >= ThrottleThreshold {
+			summary.IsThrottled = true
+		}
+		if u.MemLimitPercent >= OOMThreshold {
+			summary.IsOOM = true
+		}
+
+		total.CPUMilli += u.CPUMilli
+		total.MemoryBytes += u.MemoryBytes
+		total.CPURequestMilli += u.CPURequestMilli
+		total.CPULimitMilli += u.CPULimitMilli
+		total.MemoryRequestBytes += u.MemoryRequestBytes
+		total.MemoryLimitBytes += u.MemoryLimitBytes
+		if u.CPURequestMilli > 0 {
+			cpuRequested += u.CPUMilli
+		}
+		if u.CPULimitMilli > 0 {
+			cpuLimited += u.CPUMilli
+		}
+		if u.MemoryRequestBytes > 0 {
+			memRequested += u.MemoryBytes
+		}
+		if u.MemoryLimitBytes > 0 {
+			memLimited += u.MemoryBytes
+		}
+
+		summary.Containers = append(summary.Containers, u)
 	}
+	total.CPURequestPercent = percentOf(cpuRequested, total.CPURequestMilli)
+	total.CPULimitPercent = percentOf(cpuLimited, total.CPULimitMilli)
+	total.MemRequestPercent = percentOf(memRequested, total.MemoryRequestBytes)
+	total.MemLimitPercent = percentOf(memLimited, total.MemoryLimitBytes)
 
-	summary.CPUUsed = formatCPU(totalCPU)
-	summary.MemUsed = formatMemory(totalMem)
+	summary.CPUUsed = formatCPU(total.CPUMilli)
+	summary.MemUsed = formatMemory(total.MemoryBytes)
+	summary.CPUPercent = total.CPULimitPercent
+	if total.CPULimitMilli == 0 {
+		summary.CPUPercent = total.CPURequestPercent
+	}
+	summary.MemPercent = total.MemLimitPercent
+	if total.MemoryLimitBytes == 0 {
+		summary.MemPercent = total.MemRequestPercent
+	}
 
 	return summary
 }
+
+// UsageIssues lists containers using more than they request or running
+// close to their limits.
+func UsageIssues(summary *ResourceUsageSummary) []string {
+	if summary == nil {
+		return nil
+	}
+
+	var issues []string
+	for _, u := range summary.Containers {
+		if u.MemLimitPercent >= OOMThreshold {
+			issues = append(issues, fmt.Sprintf("Container '%s' memory at %.0f%% of limit %s (OOM kill risk)",
+				u.Name, u.MemLimitPercent, formatMemory(u.MemoryLimitBytes)))
+		} else if u.MemoryRequestBytes > 0 && u.MemoryBytes > u.MemoryRequestBytes {
+			issues = append(issues, fmt.Sprintf("Container '%s' memory usage %s exceeds request %s",
+				u.Name, formatMemory(u.MemoryBytes), formatMemory(u.MemoryRequestBytes)))
+		}
+
+		if u.CPULimitPercent >= ThrottleThreshold {
+			issues = append(issues, fmt.Sprintf("Container '%s' CPU at %.0f%% of limit %s (likely throttled)",
+				u.Name, u.CPULimitPercent, formatCPU(u.CPULimitMilli)))
+		} else if u.CPURequestMilli > 0 && u.CPUMilli > u.CPURequestMilli {
+			issues = append(issues, fmt.Sprintf("Container '%s' CPU usage %s exceeds request %s",
+				u.Name, formatCPU(u.CPUMilli), formatCPU(u.CPURequestMilli)))
+		}
+	}
+	return issues
+}
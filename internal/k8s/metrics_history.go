@@ -0,0 +1,192 @@
+package k8s
+
+import (
+	"time"
+)
+
+// MetricSample is one CPU and memory reading for a container.
+type MetricSample struct {
+	Time        time.Time
+	CPUMilli    int64
+	MemoryBytes int64
+}
+
+// SampleStats summarizes one dimension of a series of samples.
+type SampleStats struct {
+	Min int64
+	Avg int64
+	Max int64
+}
+
+// sampleRing is a fixed-capacity circular buffer, oldest sample first.
+type sampleRing struct {
+	samples []MetricSample
+	start   int
+	size    int
+}
+
+func newSampleRing(capacity int) *sampleRing {
+	return &sampleRing{samples: make([]MetricSample, capacity)}
+}
+
+func (r *sampleRing) push(s MetricSample) {
+	if r.size < len(r.samples) {
+		r.samples[(r.start+r.size)%len(r.samples)] = s
+		r.size++
+		return
+	}
+	r.samples[r.start] = s
+	r.start = (r.start + 1) % len(r.samples)
+}
+
+func (r *sampleRing) last() (MetricSample, bool) {
+	if r.size == 0 {
+		return MetricSample{}, false
+	}
+	return r.samples[(r.start+r.size-1)%len(r.samples)], true
+}
+
+func (r *sampleRing) since(cutoff time.Time) []MetricSample {
+	var out []MetricSample
+	for i := 0; i < r.size; i++ {
+		s := r.samples[(r.start+i)%len(r.samples)]
+		if !s.Time.Before(cutoff) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// historySweepInterval is how often Record looks for series to evict.
+const historySweepInterval = time.Minute
+
+// MetricsHistory keeps a bounded per-container time series of metrics
+// samples for the whole session, so history survives moving between pods.
+// Series without a sample within the retention, such as those of deleted
+// pods, are dropped. It is not safe for concurrent use.
+type MetricsHistory struct {
+	capacity  int
+	retention time.Duration
+	series    map[string]*sampleRing
+	lastSweep time.Time
+}
+
+// NewMetricsHistory creates a history holding at most capacity samples per
+// container and dropping containers not sampled for retention.
+func NewMetricsHistory(capacity int, retention time.Duration) *MetricsHistory {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &MetricsHistory{
+		capacity:  capacity,
+		retention: retention,
+		series:    map[string]*sampleRing{},
+	}
+}
+
+// Len is the number of container series kept.
+func (h *MetricsHistory) Len() int {
+	return len(h.series)
+}
+
+// evict drops the series whose last sample is older than the retention.
+func (h *MetricsHistory) evict(now time.Time) {
+	if h.retention <= 0 || now.Sub(h.lastSweep) < historySweepInterval {
+		return
+	}
+	h.lastSweep = now
+	cutoff := now.Add(-h.retention)
+	for key, ring := range h.series {
+		if last, ok := ring.last(); !ok || last.Time.Before(cutoff) {
+			delete(h.series, key)
+		}
+	}
+}
+
+func seriesKey(namespace, pod, container string) string {
+	return namespace + "/" + pod + "/" + container
+}
+
+// Record appends a sample for every container in m. metrics-server only
+// scrapes every 15-60s, so a sample with the same timestamp as the previous
+// one is skipped rather than recorded twice. now is used when m carries no
+// timestamp.
+func (h *MetricsHistory) Record(m *PodMetrics, now time.Time) {
+	if m == nil {
+		return
+	}
+	h.evict(now)
+
+	at := m.Timestamp
+	if at.IsZero() {
+		at = now
+	}
+
+	for _, c := range m.Containers {
+		key := seriesKey(m.Namespace, m.Name, c.Name)
+		ring, ok := h.series[key]
+		if !ok {
+			ring = newSampleRing(h.capacity)
+			h.series[key] = ring
+		}
+		if last, ok := ring.last(); ok && !at.After(last.Time) {
+			continue
+		}
+		ring.push(MetricSample{Time: at, CPUMilli: c.CPUMilli, MemoryBytes: c.MemoryBytes})
+	}
+}
+
+// Samples returns the samples for a container recorded at or after since,
+// oldest first.
+func (h *MetricsHistory) Samples(namespace, pod, container string, since time.Time) []MetricSample {
+	ring, ok := h.series[seriesKey(namespace, pod, container)]
+	if !ok {
+		return nil
+	}
+	return ring.since(since)
+}
+
+// PodSamples returns Samples for every container of pod, keyed by container.
+func (h *MetricsHistory) PodSamples(pod *PodInfo, since time.Time) map[string][]MetricSample {
+	if pod == nil {
+		return nil
+	}
+	result := map[string][]MetricSample{}
+	for _, c := range pod.Containers {
+		if samples := h.Samples(pod.Namespace, pod.Name, c.Name, since); len(samples) > 0 {
+			result[c.Name] = samples
+		}
+	}
+	return result
+}
+
+// CPUStats and MemoryStats summarize a series; both return zero stats for
+// an empty series.
+func CPUStats(samples []MetricSample) SampleStats {
+	return sampleStats(samples, func(s MetricSample) int64 { return s.CPUMilli })
+}
+
+func MemoryStats(samples []MetricSample) SampleStats {
+	return sampleStats(samples, func(s MetricSample) int64 { return s.MemoryBytes })
+}
+
+func sampleStats(samples []MetricSample, value func(MetricSample) int64) SampleStats {
+	if len(samples) == 0 {
+		return SampleStats{}
+	}
+
+	stats := SampleStats{Min: value(samples[0]), Max: value(samples[0])}
+	var total int64
+	for _, s := range samples {
+		v := value(s)
+		if v < stats.Min {
+			stats.Min = v
+		}
+		if v > stats.Max {
+			stats.Max = v
+		}
+		total += v
+	}
+	stats.Avg = total / int64(len(samples))
+	return stats
+}
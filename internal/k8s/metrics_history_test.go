@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func podMetricsAt(at time.Time, cpu, mem int64) *PodMetrics {
+	return &PodMetrics{
+		Name:      "api-1",
+		Namespace: "default",
+		Timestamp: at,
+		Containers: []ContainerMetrics{
+			{Name: "app", CPUMilli: cpu, MemoryBytes: mem},
+		},
+	}
+}
+
+func TestMetricsHistoryRingBuffer(t *testing.T) {
+	h := NewMetricsHistory(3, time.Hour)
+	base := time.Now().Add(-time.Hour)
+
+	for i := 0; i < 5; i++ {
+		h.Record(podMetricsAt(base.Add(time.Duration(i)*time.Minute), int64(100*(i+1)), 0), time.Now())
+	}
+
+	samples := h.Samples("default", "api-1", "app", time.Time{})
+	if len(samples) != 3 {
+		t.Fatalf("Samples() returned %d samples, want capacity 3", len(samples))
+	}
+	for i, want := range []int64{300, 400, 500} {
+		if samples[i].CPUMilli != want {
+			t.Errorf("samples[%d].CPUMilli = %d, want %d", i, samples[i].CPUMilli, want)
+		}
+	}
+}
+
+func TestMetricsHistorySkipsDuplicateScrapes(t *testing.T) {
+	h := NewMetricsHistory(10, time.Hour)
+	at := time.Now()
+
+	h.Record(podMetricsAt(at, 100, 1024), time.Now())
+	h.Record(podMetricsAt(at, 100, 1024), time.Now())
+	h.Record(podMetricsAt(at.Add(15*time.Second), 200, 2048), time.Now())
+
+	if got := len(h.Samples("default", "api-1", "app", time.Time{})); got != 2 {
+		t.Errorf("Samples() returned %d samples, want 2", got)
+	}
+}
+
+func TestMetricsHistoryEvictsStaleSeries(t *testing.T) {
+	h := NewMetricsHistory(10, 30*time.Minute)
+	now := time.Now()
+
+	gone := podMetricsAt(now.Add(-time.Hour), 100, 0)
+	gone.Name = "api-0"
+	h.Record(gone, now.Add(-time.Hour))
+	h.Record(podMetricsAt(now.Add(-10*time.Minute), 100, 0), now.Add(-10*time.Minute))
+	h.Record(podMetricsAt(now, 200, 0), now)
+
+	if h.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 after the deleted pod aged out", h.Len())
+	}
+	if got := len(h.Samples("default", "api-0", "app", time.Time{})); got != 0 {
+		t.Errorf("Samples() of the deleted pod returned %d samples, want 0", got)
+	}
+	if got := len(h.Samples("default", "api-1", "app", time.Time{})); got != 2 {
+		t.Errorf("Samples() of the live pod returned %d samples, want 2", got)
+	}
+}
+
+func TestMetricsHistorySince(t *testing.T) {
+	h := NewMetricsHistory(10, time.Hour)
+	now := time.Now()
+
+	h.Record(podMetricsAt(now.Add(-20*time.Minute), 100, 0), now)
+	h.Record(podMetricsAt(now.Add(-5*time.Minute), 200, 0), now)
+	h.Record(podMetricsAt(now.Add(-1*time.Minute), 300, 0), now)
+
+	samples := h.Samples("default", "api-1", "app", now.Add(-10*time.Minute))
+	if len(samples) != 2 || samples[0].CPUMilli != 200 {
+		t.Errorf("Samples(since 10m) = %+v, want the last two samples", samples)
+	}
+
+	if got := h.Samples("default", "other", "app", time.Time{}); got != nil {
+		t.Errorf("Samples() for unknown pod = %v, want nil", got)
+	}
+
+	pod := &PodInfo{Name: "api-1", Namespace: "default", Containers: []ContainerInfo{{Name: "app"}, {Name: "sidecar"}}}
+	bySeries := h.PodSamples(pod, time.Time{})
+	if len(bySeries) != 1 || len(bySeries["app"]) != 3 {
+		t.Errorf("PodSamples() = %v, want only app with 3 samples", bySeries)
+	}
+}
+
+func TestSampleStats(t *testing.T) {
+	samples := []MetricSample{
+		{CPUMilli: 100, MemoryBytes: 300},
+		{CPUMilli: 300, MemoryBytes: 100},
+		{CPUMilli: 200, MemoryBytes: 200},
+	}
+
+	cpu := CPUStats(samples)
+	if cpu != (SampleStats{Min: 100, Avg: 200, Max: 300}) {
+		t.Errorf("CPUStats() = %+v", cpu)
+	}
+	mem := MemoryStats(samples)
+	if mem != (SampleStats{Min: 100, Avg: 200, Max: 300}) {
+		t.Errorf("MemoryStats() = %+v", mem)
+	}
+	if empty := CPUStats(nil); empty != (SampleStats{}) {
+		t.Errorf("CPUStats(nil) = %+v, want zero", empty)
+	}
+}
+
+func TestQuantityParsing(t *testing.T) {
+	tests := []struct {
+		quantity string
+		cpu      int64
+		mem      int64
+	}{
+		{"250m", 250, 1},
+		{"2", 2000, 2},
+		{"512Mi", 512 * 1024 * 1024 * 1000, 512 * 1024 * 1024},
+		{"", 0, 0},
+		{"bogus", 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := CPUMilli(tt.quantity); got != tt.cpu {
+			t.Errorf("CPUMilli(%q) = %d, want %d", tt.quantity, got, tt.cpu)
+		}
+		if got := MemoryBytes(tt.quantity); got != tt.mem {
+			t.Errorf("MemoryBytes(%q) = %d, want %d", tt.quantity, got, tt.mem)
+		}
+	}
+}
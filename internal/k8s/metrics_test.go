@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+const mi = 1024 * 1024
+
+func TestCalculateResourceUsage(t *testing.T) {
+	pod := &PodInfo{
+		Name: "api-1",
+		Containers: []ContainerInfo{
+			{Name: "app", Resources: ResourceRequirements{CPURequest: "100m", CPULimit: "500m", MemoryRequest: "128Mi", MemoryLimit: "256Mi"}},
+			{Name: "sidecar", Resources: ResourceRequirements{CPURequest: "0", CPULimit: "0", MemoryRequest: "0", MemoryLimit: "0"}},
+		},
+	}
+	metrics := &PodMetrics{
+		Containers: []ContainerMetrics{
+			{Name: "app", CPUMilli: 150, MemoryBytes: 240 * mi},
+			{Name: "sidecar", CPUMilli: 50, MemoryBytes: 16 * mi},
+		},
+	}
+
+	summary := CalculateResourceUsage(metrics, pod)
+	if summary == nil {
+		t.Fatal("CalculateResourceUsage() returned nil")
+	}
+
+	if summary.CPUUsed != "200m" {
+		t.Errorf("CPUUsed = %q, want 200m", summary.CPUUsed)
+	}
+	if summary.MemUsed != "256.0Mi" {
+		t.Errorf("MemUsed = %q, want 256.0Mi", summary.MemUsed)
+	}
+
+	app := summary.Containers[0]
+	if app.CPURequestPercent != 150 || app.CPULimitPercent != 30 {
+		t.Errorf("app CPU percents = %v/%v, want 150/30", app.CPURequestPercent, app.CPULimitPercent)
+	}
+	if app.MemLimitPercent != 93.75 {
+		t.Errorf("app MemLimitPercent = %v, want 93.75", app.MemLimitPercent)
+	}
+
+	sidecar := summary.Containers[1]
+	if sidecar.CPULimitPercent != 0 || sidecar.MemRequestPercent != 0 {
+		t.Errorf("sidecar percents should be 0 without requests or limits, got %+v", sidecar)
+	}
+
+	// The sidecar sets no limit, so its usage does not count against the
+	// app container's
+	if summary.CPUPercent != 30 {
+		t.Errorf("CPUPercent = %v, want 30 (150m of 500m limit)", summary.CPUPercent)
+	}
+	if summary.MemPercent != 93.75 {
+		t.Errorf("MemPercent = %v, want 93.75 (240Mi of 256Mi limit)", summary.MemPercent)
+	}
+	if !summary.IsOOM {
+		t.Error("IsOOM should be set when a container is above the OOM threshold")
+	}
+	if summary.IsThrottled {
+		t.Error("IsThrottled should not be set at 30% of the CPU limit")
+	}
+
+	if CalculateResourceUsage(nil, pod) != nil {
+		t.Error("CalculateResourceUsage(nil, pod) should return nil")
+	}
+}
+
+func TestUsageIssues(t *testing.T) {
+	summary := &ResourceUsageSummary{
+		Containers: []ContainerUsage{
+			{Name: "near-oom", MemoryBytes: 95, MemoryLimitBytes: 100, MemLimitPercent: 95},
+			{Name: "over-request", CPUMilli: 200, CPURequestMilli: 100, CPURequestPercent: 200},
+			{Name: "throttled", CPUMilli: 490, CPULimitMilli: 500, CPULimitPercent: 98},
+			{Name: "fine", CPUMilli: 10, CPURequestMilli: 100, MemoryBytes: 10, MemoryRequestBytes: 100},
+		},
+	}
+
+	issues := UsageIssues(summary)
+	if len(issues) != 3 {
+		t.Fatalf("UsageIssues() returned %d issues, want 3: %v", len(issues), issues)
+	}
+
+	for i, want := range []string{"OOM kill risk", "exceeds request", "likely throttled"} {
+		if !strings.Contains(issues[i], want) {
+			t.Errorf("issues[%d] = %q, want it to mention %q", i, issues[i], want)
+		}
+	}
+}
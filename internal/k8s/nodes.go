@@ -0,0 +1,306 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// ResourceNodes is cluster-scoped and has its own view, so it is not part
+// of AllResourceTypes.
+const ResourceNodes ResourceType = "nodes"
+
+// SelectableResourceTypes is what the resource type picker offers.
+var SelectableResourceTypes = append(append([]ResourceType{}, AllResourceTypes...), ResourceNodes)
+
+type NodeCondition struct {
+	Type               string
+	Status             string
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// NodeInfo summarizes a node with its capacity, what the pods scheduled on
+// it request, and live usage when metrics-server has it.
+type NodeInfo struct {
+	Name             string
+	Status           string
+	Roles            []string
+	Unschedulable    bool
+	Conditions       []NodeCondition
+	Taints           []string
+	KubeletVersion   string
+	OSImage          string
+	ContainerRuntime string
+	InternalIP       string
+	Age              string
+	Labels           map[string]string
+
+	CPUCapacityMilli       int64
+	CPUAllocatableMilli    int64
+	MemoryCapacityBytes    int64
+	MemoryAllocatableBytes int64
+	PodCapacity            int64
+
+	CPURequestMilli    int64
+	CPULimitMilli      int64
+	MemoryRequestBytes int64
+	MemoryLimitBytes   int64
+	PodCount           int
+
+	CPUUsageMilli    int64
+	MemoryUsageBytes int64
+	HasMetrics       bool
+}
+
+// Percent of allocatable for requests, limits and usage. Limits above 100%
+// mean the node is overcommitted.
+func (n NodeInfo) CPURequestPercent() float64 {
+	return percentOf(n.CPURequestMilli, n.CPUAllocatableMilli)
+}
+
+func (n NodeInfo) CPULimitPercent() float64 {
+	return percentOf(n.CPULimitMilli, n.CPUAllocatableMilli)
+}
+
+func (n NodeInfo) CPUUsagePercent() float64 {
+	return percentOf(n.CPUUsageMilli, n.CPUAllocatableMilli)
+}
+
+func (n NodeInfo) MemoryRequestPercent() float64 {
+	return percentOf(n.MemoryRequestBytes, n.MemoryAllocatableBytes)
+}
+
+func (n NodeInfo) MemoryLimitPercent() float64 {
+	return percentOf(n.MemoryLimitBytes, n.MemoryAllocatableBytes)
+}
+
+func (n NodeInfo) MemoryUsagePercent() float64 {
+	return percentOf(n.MemoryUsageBytes, n.MemoryAllocatableBytes)
+}
+
+// Problems lists what is wrong with the node: not being Ready, any
+// pressure condition that is set, and overcommitted memory limits.
+func (n NodeInfo) Problems() []string {
+	var problems []string
+	for _, c := range n.Conditions {
+		switch {
+		case c.Type == string(corev1.NodeReady):
+			if c.Status != string(corev1.ConditionTrue) {
+				problems = append(problems, "NotReady: "+conditionText(c))
+			}
+		case c.Status == string(corev1.ConditionTrue):
+			problems = append(problems, c.Type+": "+conditionText(c))
+		}
+	}
+	if n.Unschedulable {
+		problems = append(problems, "Cordoned: new pods are not scheduled here")
+	}
+	if n.MemoryLimitPercent() > 100 {
+		problems = append(problems, fmt.Sprintf("Memory limits are %.0f%% of allocatable", n.MemoryLimitPercent()))
+	}
+	return problems
+}
+
+func conditionText(c NodeCondition) string {
+	if c.Message != "" {
+		return c.Message
+	}
+	if c.Reason != "" {
+		return c.Reason
+	}
+	return c.Status
+}
+
+// ListNodes returns all nodes with requested resources summed from their
+// running pods and usage from metrics-server when available.
+func ListNodes(ctx context.Context, clientset *kubernetes.Clientset, metricsClient *metricsv.Clientset) ([]NodeInfo, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: activePodsSelector(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[string]corev1.ResourceList{}
+	if metricsClient != nil {
+		if nm, err := metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{}); err == nil {
+			for _, m := range nm.Items {
+				usage[m.Name] = m.Usage
+			}
+		}
+	}
+
+	infos := make([]NodeInfo, 0, len(nodes.Items))
+	byName := make(map[string]int, len(nodes.Items))
+	for i := range nodes.Items {
+		info := nodeToNodeInfo(&nodes.Items[i])
+		if u, ok := usage[info.Name]; ok {
+			info.CPUUsageMilli = u.Cpu().MilliValue()
+			info.MemoryUsageBytes = u.Memory().Value()
+			info.HasMetrics = true
+		}
+		byName[info.Name] = len(infos)
+		infos = append(infos, info)
+	}
+
+	for i := range pods.Items {
+		if idx, ok := byName[pods.Items[i].Spec.NodeName]; ok {
+			addPodResources(&infos[idx], &pods.Items[i])
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// GetNode returns a single node without its pod allocation or usage,
+// which ListNodes fills in.
+func GetNode(ctx context.Context, clientset *kubernetes.Clientset, name string) (*NodeInfo, error) {
+	n, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info := nodeToNodeInfo(n)
+	return &info, nil
+}
+
+// GetNodePods lists the pods scheduled on a node across all namespaces.
+func GetNodePods(ctx context.Context, clientset *kubernetes.Clientset, node string) ([]PodInfo, error) {
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]PodInfo, 0, len(pods.Items))
+	for i := range pods.Items {
+		infos = append(infos, podToPodInfo(&pods.Items[i]))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Namespace != infos[j].Namespace {
+			return infos[i].Namespace < infos[j].Namespace
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
+
+// activePodsSelector matches pods that still hold node resources.
+func activePodsSelector() string {
+	return "status.phase!=" + string(corev1.PodSucceeded) + ",status.phase!=" + string(corev1.PodFailed)
+}
+
+func nodeToNodeInfo(n *corev1.Node) NodeInfo {
+	info := NodeInfo{
+		Name:             n.Name,
+		Unschedulable:    n.Spec.Unschedulable,
+		KubeletVersion:   n.Status.NodeInfo.KubeletVersion,
+		OSImage:          n.Status.NodeInfo.OSImage,
+		ContainerRuntime: n.Status.NodeInfo.ContainerRuntimeVersion,
+		Age:              formatAge(n.CreationTimestamp.Time),
+		Labels:           n.Labels,
+
+		CPUCapacityMilli:       n.Status.Capacity.Cpu().MilliValue(),
+		CPUAllocatableMilli:    n.Status.Allocatable.Cpu().MilliValue(),
+		MemoryCapacityBytes:    n.Status.Capacity.Memory().Value(),
+		MemoryAllocatableBytes: n.Status.Allocatable.Memory().Value(),
+		PodCapacity:            n.Status.Allocatable.Pods().Value(),
+	}
+
+	for label := range n.Labels {
+		if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+			info.Roles = append(info.Roles, role)
+		}
+	}
+	sort.Strings(info.Roles)
+
+	for _, addr := range n.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			info.InternalIP = addr.Address
+			break
+		}
+	}
+
+	info.Status = "Unknown"
+	for _, c := range n.Status.Conditions {
+		info.Conditions = append(info.Conditions, NodeCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+		if c.Type == corev1.NodeReady {
+			if c.Status == corev1.ConditionTrue {
+				info.Status = "Ready"
+			} else {
+				info.Status = "NotReady"
+			}
+		}
+	}
+	if info.Unschedulable {
+		info.Status += ",SchedulingDisabled"
+	}
+
+	for _, t := range n.Spec.Taints {
+		taint := t.Key
+		if t.Value != "" {
+			taint += "=" + t.Value
+		}
+		info.Taints = append(info.Taints, taint+":"+string(t.Effect))
+	}
+
+	return info
+}
+
+// addPodResources adds a pod's effective requests and limits to the node
+// totals. Init containers run one at a time before the app containers, so
+// a pod needs the larger of the biggest init container and the app sum.
+func addPodResources(n *NodeInfo, pod *corev1.Pod) {
+	var cpuReq, cpuLim, memReq, memLim int64
+	for _, c := range pod.Spec.Containers {
+		cpuReq += c.Resources.Requests.Cpu().MilliValue()
+		cpuLim += c.Resources.Limits.Cpu().MilliValue()
+		memReq += c.Resources.Requests.Memory().Value()
+		memLim += c.Resources.Limits.Memory().Value()
+	}
+	for _, c := range pod.Spec.InitContainers {
+		cpuReq = maxInt64(cpuReq, c.Resources.Requests.Cpu().MilliValue())
+		cpuLim = maxInt64(cpuLim, c.Resources.Limits.Cpu().MilliValue())
+		memReq = maxInt64(memReq, c.Resources.Requests.Memory().Value())
+		memLim = maxInt64(memLim, c.Resources.Limits.Memory().Value())
+	}
+	if overhead := pod.Spec.Overhead; overhead != nil {
+		cpuReq += overhead.Cpu().MilliValue()
+		memReq += overhead.Memory().Value()
+	}
+
+	n.CPURequestMilli += cpuReq
+	n.CPULimitMilli += cpuLim
+	n.MemoryRequestBytes += memReq
+	n.MemoryLimitBytes += memLim
+	n.PodCount++
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
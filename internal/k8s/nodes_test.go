@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func resources(cpu, mem string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if mem != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(mem)
+	}
+	return list
+}
+
+func TestNodeToNodeInfo(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "worker-1",
+			Labels: map[string]string{
+				"node-role.kubernetes.io/worker":        "",
+				"node-role.kubernetes.io/control-plane": "",
+				"kubernetes.io/hostname":                "worker-1",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity:    resources("4", "16Gi"),
+			Allocatable: resources("3800m", "15Gi"),
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasDiskPressure"},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			},
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeHostName, Address: "worker-1"},
+				{Type: corev1.NodeInternalIP, Address: "10.0.0.7"},
+			},
+			NodeInfo: corev1.NodeSystemInfo{KubeletVersion: "v1.29.2"},
+		},
+	}
+
+	info := nodeToNodeInfo(node)
+
+	if info.Status != "Ready,SchedulingDisabled" {
+		t.Errorf("Status = %q, want Ready,SchedulingDisabled", info.Status)
+	}
+	if strings.Join(info.Roles, ",") != "control-plane,worker" {
+		t.Errorf("Roles = %v", info.Roles)
+	}
+	if strings.Join(info.Taints, " ") != "dedicated=gpu:NoSchedule node.kubernetes.io/unschedulable:NoSchedule" {
+		t.Errorf("Taints = %v", info.Taints)
+	}
+	if info.CPUAllocatableMilli != 3800 || info.MemoryAllocatableBytes != 15*1024*mi {
+		t.Errorf("allocatable = %dm/%d", info.CPUAllocatableMilli, info.MemoryAllocatableBytes)
+	}
+	if info.InternalIP != "10.0.0.7" || info.KubeletVersion != "v1.29.2" {
+		t.Errorf("InternalIP/KubeletVersion = %s/%s", info.InternalIP, info.KubeletVersion)
+	}
+
+	problems := strings.Join(info.Problems(), "\n")
+	if !strings.Contains(problems, "DiskPressure: KubeletHasDiskPressure") || !strings.Contains(problems, "Cordoned") {
+		t.Errorf("Problems() = %q", problems)
+	}
+	if strings.Contains(problems, "MemoryPressure") {
+		t.Errorf("Problems() reported a condition that is False: %q", problems)
+	}
+}
+
+func TestNodeNotReady(t *testing.T) {
+	info := nodeToNodeInfo(&corev1.Node{
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Message: "Kubelet stopped posting node status."},
+		}},
+	})
+	if info.Status != "NotReady" {
+		t.Errorf("Status = %q, want NotReady", info.Status)
+	}
+	if problems := info.Problems(); len(problems) != 1 || !strings.HasPrefix(problems[0], "NotReady: Kubelet stopped") {
+		t.Errorf("Problems() = %v", problems)
+	}
+}
+
+func TestAddPodResources(t *testing.T) {
+	node := NodeInfo{CPUAllocatableMilli: 2000, MemoryAllocatableBytes: 1024 * mi}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			// Larger than the app containers together, so it wins for CPU
+			{Resources: corev1.ResourceRequirements{Requests: resources("800m", "64Mi")}},
+		},
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{Requests: resources("250m", "256Mi"), Limits: resources("1", "512Mi")}},
+			{Resources: corev1.ResourceRequirements{Requests: resources("250m", "256Mi"), Limits: resources("", "768Mi")}},
+		},
+	}}
+
+	addPodResources(&node, pod)
+	addPodResources(&node, &corev1.Pod{})
+
+	if node.CPURequestMilli != 800 || node.CPULimitMilli != 1000 {
+		t.Errorf("cpu requests/limits = %d/%d, want 800/1000", node.CPURequestMilli, node.CPULimitMilli)
+	}
+	if node.MemoryRequestBytes != 512*mi || node.MemoryLimitBytes != 1280*mi {
+		t.Errorf("memory requests/limits = %d/%d", node.MemoryRequestBytes, node.MemoryLimitBytes)
+	}
+	if node.PodCount != 2 {
+		t.Errorf("PodCount = %d, want 2", node.PodCount)
+	}
+	if node.CPURequestPercent() != 40 || node.MemoryLimitPercent() != 125 {
+		t.Errorf("percents = %.0f/%.0f, want 40/125", node.CPURequestPercent(), node.MemoryLimitPercent())
+	}
+	if !strings.Contains(strings.Join(node.Problems(), ""), "Memory limits are 125%") {
+		t.Errorf("Problems() = %v, want overcommit", node.Problems())
+	}
+}
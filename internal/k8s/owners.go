@@ -0,0 +1,295 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Kind returns the Kubernetes kind for a resource type.
+func (rt ResourceType) Kind() string {
+	switch rt {
+	case ResourcePods:
+		return "Pod"
+	case ResourceDeployments:
+		return "Deployment"
+	case ResourceStatefulSets:
+		return "StatefulSet"
+	case ResourceDaemonSets:
+		return "DaemonSet"
+	case ResourceJobs:
+		return "Job"
+	case ResourceCronJobs:
+		return "CronJob"
+	case ResourceNodes:
+		return "Node"
+	default:
+		return string(rt)
+	}
+}
+
+// ResourceTypeForKind maps a Kubernetes kind back to the resource type
+// the navigator lists it under.
+func ResourceTypeForKind(kind string) (ResourceType, bool) {
+	for _, rt := range AllResourceTypes {
+		if rt.Kind() == kind {
+			return rt, true
+		}
+	}
+	return "", false
+}
+
+// resourceTypeAliases are the kubectl short names accepted besides the
+// plural, singular and kind spellings.
+var resourceTypeAliases = map[string]ResourceType{
+	"po":     ResourcePods,
+	"deploy": ResourceDeployments,
+	"sts":    ResourceStatefulSets,
+	"ds":     ResourceDaemonSets,
+	"cj":     ResourceCronJobs,
+	"no":     ResourceNodes,
+}
+
+// ParseResourceType accepts a resource type the way kubectl does: plural,
+// singular, kind or short name, case-insensitively.
+func ParseResourceType(s string) (ResourceType, bool) {
+	s = strings.ToLower(s)
+	if rt, ok := resourceTypeAliases[s]; ok {
+		return rt, true
+	}
+	for _, rt := range SelectableResourceTypes {
+		if s == string(rt) || s == strings.ToLower(rt.Kind()) {
+			return rt, true
+		}
+	}
+	return "", false
+}
+
+// ResolveWorkload returns the navigable workload behind ref. ReplicaSets
+// resolve to their owning Deployment since they are not listed themselves.
+func ResolveWorkload(ctx context.Context, clientset *kubernetes.Clientset, ref ObjectReference) (*WorkloadInfo, error) {
+	if ref.Kind == "ReplicaSet" {
+		rs, err := clientset.AppsV1().ReplicaSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range rs.OwnerReferences {
+			if owner.Kind == "Deployment" {
+				return GetWorkload(ctx, clientset, ref.Namespace, ResourceDeployments, owner.Name)
+			}
+		}
+		return nil, fmt.Errorf("replicaset %s has no owning deployment", ref.Name)
+	}
+
+	rt, ok := ResourceTypeForKind(ref.Kind)
+	if !ok {
+		return nil, fmt.Errorf("no view for kind %s", ref.Kind)
+	}
+	return GetWorkload(ctx, clientset, ref.Namespace, rt, ref.Name)
+}
+
+// PodWorkload returns the workload whose pod template produced pod. A
+// ReplicaSet owner maps to its Deployment by stripping the pod-template-hash
+// suffix, which is how the Deployment controller names them.
+func PodWorkload(pod *PodInfo) (ResourceType, string, bool) {
+	switch pod.OwnerKind {
+	case "ReplicaSet":
+		hash := pod.Labels["pod-template-hash"]
+		if hash != "" && strings.HasSuffix(pod.OwnerRef, "-"+hash) {
+			return ResourceDeployments, strings.TrimSuffix(pod.OwnerRef, "-"+hash), true
+		}
+	case "StatefulSet":
+		return ResourceStatefulSets, pod.OwnerRef, true
+	case "DaemonSet":
+		return ResourceDaemonSets, pod.OwnerRef, true
+	}
+	return "", "", false
+}
+
+// GetWorkloadObjects returns the workload itself and everything it owns down
+// to its pods (Deployment → ReplicaSets → Pods, CronJob → Jobs → Pods), plus
+// any HorizontalPodAutoscaler targeting it.
+func GetWorkloadObjects(ctx context.Context, clientset *kubernetes.Clientset, workload WorkloadInfo) ([]ObjectReference, error) {
+	ns := workload.Namespace
+	var refs []ObjectReference
+	owners := map[types.UID]bool{}
+
+	addRef := func(kind string, meta metav1.ObjectMeta) {
+		refs = append(refs, ObjectReference{Kind: kind, Namespace: meta.Namespace, Name: meta.Name, UID: string(meta.UID)})
+		owners[meta.UID] = true
+	}
+
+	// Pods are listed per selector and kept when one of owners owns them
+	var podSelectors []string
+	switch workload.Type {
+	case ResourcePods:
+		pod, err := clientset.CoreV1().Pods(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("Pod", pod.ObjectMeta)
+		return refs, nil
+
+	case ResourceDeployments:
+		d, err := clientset.AppsV1().Deployments(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("Deployment", d.ObjectMeta)
+		podSelector := metav1.FormatLabelSelector(d.Spec.Selector)
+		podSelectors = append(podSelectors, podSelector)
+
+		rsList, err := clientset.AppsV1().ReplicaSets(ns).List(ctx, metav1.ListOptions{LabelSelector: podSelector})
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range rsList.Items {
+			if isOwnedBy(rs.OwnerReferences, map[types.UID]bool{d.UID: true}) {
+				addRef("ReplicaSet", rs.ObjectMeta)
+			}
+		}
+
+	case ResourceStatefulSets:
+		s, err := clientset.AppsV1().StatefulSets(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("StatefulSet", s.ObjectMeta)
+		podSelectors = append(podSelectors, metav1.FormatLabelSelector(s.Spec.Selector))
+
+	case ResourceDaemonSets:
+		ds, err := clientset.AppsV1().DaemonSets(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("DaemonSet", ds.ObjectMeta)
+		podSelectors = append(podSelectors, metav1.FormatLabelSelector(ds.Spec.Selector))
+
+	case ResourceJobs:
+		j, err := clientset.BatchV1().Jobs(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("Job", j.ObjectMeta)
+		podSelectors = append(podSelectors, metav1.FormatLabelSelector(j.Spec.Selector))
+
+	case ResourceCronJobs:
+		cj, err := clientset.BatchV1().CronJobs(ns).Get(ctx, workload.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		addRef("CronJob", cj.ObjectMeta)
+
+		// Jobs carry the labels of the job template, which narrows the list
+		// when it sets any
+		jobSelector := labels.SelectorFromSet(cj.Spec.JobTemplate.Labels).String()
+		jobs, err := clientset.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{LabelSelector: jobSelector})
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range jobs.Items {
+			if isOwnedBy(j.OwnerReferences, map[types.UID]bool{cj.UID: true}) {
+				addRef("Job", j.ObjectMeta)
+				// Each job selects its own pods by controller-uid
+				podSelectors = append(podSelectors, metav1.FormatLabelSelector(j.Spec.Selector))
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown resource type: %s", workload.Type)
+	}
+
+	for _, selector := range podSelectors {
+		pods, err := clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range ownedPods(pods.Items, owners) {
+			refs = append(refs, ObjectReference{Kind: "Pod", Namespace: p.Namespace, Name: p.Name, UID: string(p.UID)})
+		}
+	}
+
+	hpas, err := clientset.AutoscalingV1().HorizontalPodAutoscalers(ns).List(ctx, metav1.ListOptions{})
+	if err == nil {
+		for _, hpa := range hpas.Items {
+			if hpa.Spec.ScaleTargetRef.Kind == workload.Type.Kind() && hpa.Spec.ScaleTargetRef.Name == workload.Name {
+				refs = append(refs, ObjectReference{Kind: "HorizontalPodAutoscaler", Namespace: hpa.Namespace, Name: hpa.Name, UID: string(hpa.UID)})
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+func isOwnedBy(refs []metav1.OwnerReference, owners map[types.UID]bool) bool {
+	for _, ref := range refs {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+func ownedPods(pods []corev1.Pod, owners map[types.UID]bool) []corev1.Pod {
+	var owned []corev1.Pod
+	for _, p := range pods {
+		if isOwnedBy(p.OwnerReferences, owners) {
+			owned = append(owned, p)
+		}
+	}
+	return owned
+}
+
+// maxConcurrentEventQueries bounds the per-object event lookups so a large
+// workload doesn't flood the API server.
+const maxConcurrentEventQueries = 8
+
+// GetObjectsEvents fetches events for each object with a uid field selector
+// and merges them newest first.
+func GetObjectsEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string, refs []ObjectReference) ([]EventInfo, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		result   []EventInfo
+		firstErr error
+	)
+	sem := make(chan struct{}, maxConcurrentEventQueries)
+
+	for _, ref := range refs {
+		if ref.UID == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(uid string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			events, err := ListEvents(ctx, clientset, namespace, EventSelector{UID: uid})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			result = append(result, events...)
+		}(ref.UID)
+	}
+	wg.Wait()
+
+	if firstErr != nil && len(result) == 0 {
+		return nil, firstErr
+	}
+
+	sortEventsByLastSeen(result)
+	return result, nil
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestResourceTypeKind(t *testing.T) {
+	expected := map[ResourceType]string{
+		ResourcePods:         "Pod",
+		ResourceDeployments:  "Deployment",
+		ResourceStatefulSets: "StatefulSet",
+		ResourceDaemonSets:   "DaemonSet",
+		ResourceJobs:         "Job",
+		ResourceCronJobs:     "CronJob",
+	}
+
+	for _, rt := range AllResourceTypes {
+		if rt.Kind() != expected[rt] {
+			t.Errorf("%s.Kind() = %q, want %q", rt, rt.Kind(), expected[rt])
+		}
+	}
+}
+
+func TestOwnedPods(t *testing.T) {
+	pod := func(name string, owner types.UID) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if owner != "" {
+			p.OwnerReferences = []metav1.OwnerReference{{UID: owner}}
+		}
+		return p
+	}
+
+	pods := []corev1.Pod{
+		pod("api-rs1-a", "rs1"),
+		pod("api-rs2-a", "rs2"),
+		pod("other-a", "rs3"),
+		pod("standalone", ""),
+	}
+
+	owned := ownedPods(pods, map[types.UID]bool{"rs1": true, "rs2": true})
+	if len(owned) != 2 {
+		t.Fatalf("ownedPods() returned %d pods, want 2", len(owned))
+	}
+	if owned[0].Name != "api-rs1-a" || owned[1].Name != "api-rs2-a" {
+		t.Errorf("ownedPods() = %s, %s", owned[0].Name, owned[1].Name)
+	}
+}
+
+func TestResourceTypeForKind(t *testing.T) {
+	for _, rt := range AllResourceTypes {
+		got, ok := ResourceTypeForKind(rt.Kind())
+		if !ok || got != rt {
+			t.Errorf("ResourceTypeForKind(%q) = %q, %v, want %q", rt.Kind(), got, ok, rt)
+		}
+	}
+	if _, ok := ResourceTypeForKind("Node"); ok {
+		t.Error("ResourceTypeForKind(\"Node\") should not resolve")
+	}
+}
+
+func TestPodWorkload(t *testing.T) {
+	tests := []struct {
+		name   string
+		pod    PodInfo
+		rt     ResourceType
+		owner  string
+		wantOK bool
+	}{
+		{
+			name:   "deployment replicaset",
+			pod:    PodInfo{OwnerKind: "ReplicaSet", OwnerRef: "api-7d9f", Labels: map[string]string{"pod-template-hash": "7d9f"}},
+			rt:     ResourceDeployments,
+			owner:  "api",
+			wantOK: true,
+		},
+		{
+			name: "bare replicaset",
+			pod:  PodInfo{OwnerKind: "ReplicaSet", OwnerRef: "legacy"},
+		},
+		{
+			name:   "statefulset",
+			pod:    PodInfo{OwnerKind: "StatefulSet", OwnerRef: "db"},
+			rt:     ResourceStatefulSets,
+			owner:  "db",
+			wantOK: true,
+		},
+		{
+			name: "job",
+			pod:  PodInfo{OwnerKind: "Job", OwnerRef: "backup-28123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, owner, ok := PodWorkload(&tt.pod)
+			if ok != tt.wantOK || rt != tt.rt || owner != tt.owner {
+				t.Errorf("PodWorkload() = %s, %s, %v, want %s, %s, %v", rt, owner, ok, tt.rt, tt.owner, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseResourceType(t *testing.T) {
+	tests := map[string]ResourceType{
+		"deployments": ResourceDeployments,
+		"deployment":  ResourceDeployments,
+		"Deployment":  ResourceDeployments,
+		"deploy":      ResourceDeployments,
+		"sts":         ResourceStatefulSets,
+		"ds":          ResourceDaemonSets,
+		"job":         ResourceJobs,
+		"cj":          ResourceCronJobs,
+		"po":          ResourcePods,
+		"pod":         ResourcePods,
+		"node":        ResourceNodes,
+	}
+	for s, want := range tests {
+		if got, ok := ParseResourceType(s); !ok || got != want {
+			t.Errorf("ParseResourceType(%q) = %s, %v, want %s", s, got, ok, want)
+		}
+	}
+	if _, ok := ParseResourceType("replicaset"); ok {
+		t.Error("ParseResourceType(replicaset) should fail")
+	}
+}
@@ -0,0 +1,282 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsProvider returns usage over a time range for a pod. metrics-server
+// only knows the current value, so this is what answers "what was memory
+// doing before the OOM kill".
+type MetricsProvider interface {
+	Name() string
+	PodRangeMetrics(ctx context.Context, namespace, pod string, start, end time.Time) (*PodRangeMetrics, error)
+}
+
+// SeriesPoint is one value of a range query.
+type SeriesPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// ContainerRangeMetrics holds the series for one container. CPU is in
+// cores, Memory is the working set in bytes, Throttling is the fraction of
+// CFS periods that were throttled and Restarts the cumulative restart count.
+type ContainerRangeMetrics struct {
+	Container  string
+	CPU        []SeriesPoint
+	Memory     []SeriesPoint
+	Throttling []SeriesPoint
+	Restarts   []SeriesPoint
+}
+
+// PodRangeMetrics holds per-container series plus pod-level network
+// throughput in bytes per second.
+type PodRangeMetrics struct {
+	Start      time.Time
+	End        time.Time
+	Containers []ContainerRangeMetrics
+	NetworkRx  []SeriesPoint
+	NetworkTx  []SeriesPoint
+}
+
+// Container returns the series for name, or nil.
+func (m *PodRangeMetrics) Container(name string) *ContainerRangeMetrics {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Containers {
+		if m.Containers[i].Container == name {
+			return &m.Containers[i]
+		}
+	}
+	return nil
+}
+
+// PrometheusProvider queries cAdvisor and kube-state-metrics series through
+// the Prometheus HTTP API.
+type PrometheusProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewPrometheusProvider(baseURL string) *PrometheusProvider {
+	return &PrometheusProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *PrometheusProvider) Name() string {
+	return "prometheus"
+}
+
+// PromSeries is one series of a range query result.
+type PromSeries struct {
+	Labels map[string]string
+	Points []SeriesPoint
+}
+
+type promResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string    `json:"metric"`
+			Values [][2]json.RawMessage `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange runs a PromQL range query and returns the matrix result.
+func (p *PrometheusProvider) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]PromSeries, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("start", strconv.FormatInt(start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(end.Unix(), 10))
+	params.Set("step", strconv.FormatInt(int64(step.Seconds()), 10))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+
+	var parsed promResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("prometheus query failed: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to decode prometheus response: %w", err)
+	}
+	if parsed.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", parsed.ErrorType, parsed.Error)
+	}
+	if parsed.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected prometheus result type: %s", parsed.Data.ResultType)
+	}
+
+	series := make([]PromSeries, 0, len(parsed.Data.Result))
+	for _, r := range parsed.Data.Result {
+		s := PromSeries{Labels: r.Metric}
+		for _, v := range r.Values {
+			var ts float64
+			var raw string
+			if json.Unmarshal(v[0], &ts) != nil || json.Unmarshal(v[1], &raw) != nil {
+				continue
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			s.Points = append(s.Points, SeriesPoint{
+				Time:  time.Unix(0, int64(ts*float64(time.Second))),
+				Value: value,
+			})
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// rangeStep picks a step giving roughly 120 points over the range, but
+// never finer than the usual 15s scrape interval.
+func rangeStep(start, end time.Time) time.Duration {
+	step := end.Sub(start) / 120
+	if step < 15*time.Second {
+		step = 15 * time.Second
+	}
+	return step.Truncate(time.Second)
+}
+
+// PodQueries returns the PromQL used for each series of a pod, keyed by
+// series name. rate() windows cover at least four scrapes.
+func PodQueries(namespace, pod string, step time.Duration) map[string]string {
+	rateWindow := step * 4
+	if rateWindow < time.Minute {
+		rateWindow = time.Minute
+	}
+	rw := fmt.Sprintf("[%ds]", int64(rateWindow.Seconds()))
+
+	sel := fmt.Sprintf("namespace=%s, pod=%s", strconv.Quote(namespace), strconv.Quote(pod))
+	containerSel := "{" + sel + `, container!="", container!="POD"}`
+
+	return map[string]string{
+		"cpu":    "sum by (container) (rate(container_cpu_usage_seconds_total" + containerSel + rw + "))",
+		"memory": "sum by (container) (container_memory_working_set_bytes" + containerSel + ")",
+		"throttling": "sum by (container) (rate(container_cpu_cfs_throttled_periods_total" + containerSel + rw + "))" +
+			" / sum by (container) (rate(container_cpu_cfs_periods_total" + containerSel + rw + "))",
+		"restarts":   "sum by (container) (kube_pod_container_status_restarts_total{" + sel + "})",
+		"network_rx": "sum(rate(container_network_receive_bytes_total{" + sel + "}" + rw + "))",
+		"network_tx": "sum(rate(container_network_transmit_bytes_total{" + sel + "}" + rw + "))",
+	}
+}
+
+// PodRangeMetrics runs every pod query concurrently. A failing query only
+// leaves its series empty; an error is returned when all of them fail.
+func (p *PrometheusProvider) PodRangeMetrics(ctx context.Context, namespace, pod string, start, end time.Time) (*PodRangeMetrics, error) {
+	step := rangeStep(start, end)
+	queries := PodQueries(namespace, pod, step)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  = map[string][]PromSeries{}
+		firstErr error
+	)
+	for name, query := range queries {
+		wg.Add(1)
+		go func(name, query string) {
+			defer wg.Done()
+			series, err := p.QueryRange(ctx, query, start, end, step)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			results[name] = series
+		}(name, query)
+	}
+	wg.Wait()
+
+	if len(results) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	m := &PodRangeMetrics{Start: start, End: end}
+	container := func(name string) *ContainerRangeMetrics {
+		if c := m.Container(name); c != nil {
+			return c
+		}
+		m.Containers = append(m.Containers, ContainerRangeMetrics{Container: name})
+		return &m.Containers[len(m.Containers)-1]
+	}
+
+	// Fixed order keeps container ordering stable between refreshes
+	for _, name := range []string{"cpu", "memory", "throttling", "restarts"} {
+		for _, s := range results[name] {
+			c := container(s.Labels["container"])
+			switch name {
+			case "cpu":
+				c.CPU = s.Points
+			case "memory":
+				c.Memory = s.Points
+			case "throttling":
+				c.Throttling = s.Points
+			case "restarts":
+				c.Restarts = s.Points
+			}
+		}
+	}
+	if rx := results["network_rx"]; len(rx) > 0 {
+		m.NetworkRx = rx[0].Points
+	}
+	if tx := results["network_tx"]; len(tx) > 0 {
+		m.NetworkTx = tx[0].Points
+	}
+
+	return m, nil
+}
+
+// SeriesValues extracts the values of points, for charting.
+func SeriesValues(points []SeriesPoint) []float64 {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// SeriesMax returns the largest value in points, or 0.
+func SeriesMax(points []SeriesPoint) float64 {
+	var max float64
+	for _, p := range points {
+		if p.Value > max {
+			max = p.Value
+		}
+	}
+	return max
+}
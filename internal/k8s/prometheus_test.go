@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusQueryRange(t *testing.T) {
+	var gotPath, gotQuery, gotStep string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("query")
+		gotStep = r.URL.Query().Get("step")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"container":"app"},"values":[[1700000000,"0.25"],[1700000015.5,"0.5"],[1700000030,"NaN"]]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	p := NewPrometheusProvider(server.URL + "/")
+	start := time.Unix(1700000000, 0)
+	series, err := p.QueryRange(context.Background(), "up", start, start.Add(time.Minute), 15*time.Second)
+	if err != nil {
+		t.Fatalf("QueryRange() error = %v", err)
+	}
+
+	if gotPath != "/api/v1/query_range" || gotQuery != "up" || gotStep != "15" {
+		t.Errorf("request = %s query=%q step=%q", gotPath, gotQuery, gotStep)
+	}
+	if len(series) != 1 || series[0].Labels["container"] != "app" {
+		t.Fatalf("QueryRange() series = %+v", series)
+	}
+	points := series[0].Points
+	if len(points) != 3 {
+		t.Fatalf("QueryRange() returned %d points, want 3", len(points))
+	}
+	if points[1].Value != 0.5 || !points[1].Time.Equal(time.Unix(1700000015, 5e8)) {
+		t.Errorf("points[1] = %+v", points[1])
+	}
+}
+
+func TestPrometheusQueryRangeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error at char 4"}`))
+	}))
+	defer server.Close()
+
+	p := NewPrometheusProvider(server.URL)
+	_, err := p.QueryRange(context.Background(), "up{", time.Now().Add(-time.Hour), time.Now(), time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("QueryRange() error = %v, want the prometheus error message", err)
+	}
+}
+
+func TestPrometheusPodRangeMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		switch {
+		case strings.Contains(query, "container_cpu_cfs_throttled_periods_total"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"container":"app"},"values":[[1700000000,"0.1"]]}]}}`))
+		case strings.Contains(query, "container_cpu_usage_seconds_total"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"container":"app"},"values":[[1700000000,"0.2"]]},
+				{"metric":{"container":"sidecar"},"values":[[1700000000,"0.01"]]}]}}`))
+		case strings.Contains(query, "container_memory_working_set_bytes"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"container":"app"},"values":[[1700000000,"1048576"],[1700000060,"2097152"]]}]}}`))
+		case strings.Contains(query, "container_network_receive_bytes_total"):
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{},"values":[[1700000000,"512"]]}]}}`))
+		default:
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`))
+		}
+	}))
+	defer server.Close()
+
+	p := NewPrometheusProvider(server.URL)
+	end := time.Unix(1700000060, 0)
+	m, err := p.PodRangeMetrics(context.Background(), "default", "api-1", end.Add(-time.Hour), end)
+	if err != nil {
+		t.Fatalf("PodRangeMetrics() error = %v", err)
+	}
+
+	app := m.Container("app")
+	if app == nil {
+		t.Fatal("PodRangeMetrics() has no series for container app")
+	}
+	if len(app.CPU) != 1 || app.CPU[0].Value != 0.2 {
+		t.Errorf("app CPU = %+v", app.CPU)
+	}
+	if SeriesMax(app.Memory) != 2097152 {
+		t.Errorf("app memory max = %v, want 2097152", SeriesMax(app.Memory))
+	}
+	if len(app.Throttling) != 1 || app.Throttling[0].Value != 0.1 {
+		t.Errorf("app throttling = %+v", app.Throttling)
+	}
+	if m.Container("sidecar") == nil {
+		t.Error("PodRangeMetrics() has no series for container sidecar")
+	}
+	if len(m.NetworkRx) != 1 || len(m.NetworkTx) != 0 {
+		t.Errorf("network rx/tx = %d/%d points, want 1/0", len(m.NetworkRx), len(m.NetworkTx))
+	}
+}
+
+func TestPrometheusPodRangeMetricsAllFailing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p := NewPrometheusProvider(server.URL)
+	if _, err := p.PodRangeMetrics(context.Background(), "default", "api-1", time.Now().Add(-time.Hour), time.Now()); err == nil {
+		t.Error("PodRangeMetrics() should fail when every query fails")
+	}
+}
+
+func TestPodQueries(t *testing.T) {
+	queries := PodQueries("payments", "api-1", 30*time.Second)
+
+	want := `sum by (container) (container_memory_working_set_bytes{namespace="payments", pod="api-1", container!="", container!="POD"})`
+	if queries["memory"] != want {
+		t.Errorf("memory query = %q, want %q", queries["memory"], want)
+	}
+	if !strings.Contains(queries["cpu"], "[120s]") {
+		t.Errorf("cpu query should use a rate window of four steps: %q", queries["cpu"])
+	}
+	if !strings.Contains(PodQueries("a", "b", time.Second)["network_rx"], "[60s]") {
+		t.Error("rate window should be at least one minute")
+	}
+}
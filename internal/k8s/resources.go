@@ -45,6 +45,9 @@ type WorkloadInfo struct {
 	Status       string
 	Labels       map[string]string
 	RestartCount int32
+
+	// Health is the controller-reported rollout state, see AnalyzeWorkload
+	Health *WorkloadHealth
 }
 
 type PodInfo struct {
@@ -57,11 +60,27 @@ type PodInfo struct {
 	Age          string
 	IP           string
 	Labels       map[string]string
+	Annotations  map[string]string
+	NodeSelector map[string]string
 	Containers   []ContainerInfo
 	Conditions   []corev1.PodCondition
 	Phase        corev1.PodPhase
 	OwnerRef     string
 	OwnerKind    string
+
+	// InitContainers are the names of the init containers, whose logs can
+	// be read like the others'
+	InitContainers []string
+}
+
+// ContainerNames returns the names of the app containers, then of the init
+// containers.
+func (p PodInfo) ContainerNames() []string {
+	names := make([]string, 0, len(p.Containers)+len(p.InitContainers))
+	for _, c := range p.Containers {
+		names = append(names, c.Name)
+	}
+	return append(names, p.InitContainers...)
 }
 
 type ContainerInfo struct {
@@ -73,6 +92,11 @@ type ContainerInfo struct {
 	Reason       string
 	Resources    ResourceRequirements
 	Ports        []int32
+
+	// Terminated is set while the container is terminated, LastTerminated
+	// describes the run before the current one
+	Terminated     *TerminationInfo
+	LastTerminated *TerminationInfo
 }
 
 type ResourceRequirements struct {
@@ -122,29 +146,34 @@ func listDeployments(ctx context.Context, clientset *kubernetes.Clientset, names
 	}
 
 	var workloads []WorkloadInfo
-	for _, d := range deps.Items {
-		status := "Running"
-		if d.Status.ReadyReplicas < d.Status.Replicas {
-			status = "Progressing"
-		}
-		if d.Status.ReadyReplicas == 0 && d.Status.Replicas > 0 {
-			status = "NotReady"
-		}
-
-		workloads = append(workloads, WorkloadInfo{
-			Name:      d.Name,
-			Namespace: d.Namespace,
-			Type:      ResourceDeployments,
-			Ready:     fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Status.Replicas),
-			Replicas:  d.Status.Replicas,
-			Age:       formatAge(d.CreationTimestamp.Time),
-			Status:    status,
-			Labels:    d.Spec.Selector.MatchLabels,
-		})
+	for i := range deps.Items {
+		workloads = append(workloads, deploymentToWorkloadInfo(&deps.Items[i]))
 	}
 	return workloads, nil
 }
 
+func deploymentToWorkloadInfo(d *appsv1.Deployment) WorkloadInfo {
+	status := "Running"
+	if d.Status.ReadyReplicas < d.Status.Replicas {
+		status = "Progressing"
+	}
+	if d.Status.ReadyReplicas == 0 && d.Status.Replicas > 0 {
+		status = "NotReady"
+	}
+
+	return WorkloadInfo{
+		Name:      d.Name,
+		Namespace: d.Namespace,
+		Type:      ResourceDeployments,
+		Ready:     fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Status.Replicas),
+		Replicas:  d.Status.Replicas,
+		Age:       formatAge(d.CreationTimestamp.Time),
+		Status:    status,
+		Labels:    d.Spec.Selector.MatchLabels,
+		Health:    deploymentHealth(d),
+	}
+}
+
 func listStatefulSets(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]WorkloadInfo, error) {
 	sts, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -152,26 +181,31 @@ func listStatefulSets(ctx context.Context, clientset *kubernetes.Clientset, name
 	}
 
 	var workloads []WorkloadInfo
-	for _, s := range sts.Items {
-		status := "Running"
-		if s.Status.ReadyReplicas < s.Status.Replicas {
-			status = "Progressing"
-		}
-
-		workloads = append(workloads, WorkloadInfo{
-			Name:      s.Name,
-			Namespace: s.Namespace,
-			Type:      ResourceStatefulSets,
-			Ready:     fmt.Sprintf("%d/%d", s.Status.ReadyReplicas, s.Status.Replicas),
-			Replicas:  s.Status.Replicas,
-			Age:       formatAge(s.CreationTimestamp.Time),
-			Status:    status,
-			Labels:    s.Spec.Selector.MatchLabels,
-		})
+	for i := range sts.Items {
+		workloads = append(workloads, statefulSetToWorkloadInfo(&sts.Items[i]))
 	}
 	return workloads, nil
 }
 
+func statefulSetToWorkloadInfo(s *appsv1.StatefulSet) WorkloadInfo {
+	status := "Running"
+	if s.Status.ReadyReplicas < s.Status.Replicas {
+		status = "Progressing"
+	}
+
+	return WorkloadInfo{
+		Name:      s.Name,
+		Namespace: s.Namespace,
+		Type:      ResourceStatefulSets,
+		Ready:     fmt.Sprintf("%d/%d", s.Status.ReadyReplicas, s.Status.Replicas),
+		Replicas:  s.Status.Replicas,
+		Age:       formatAge(s.CreationTimestamp.Time),
+		Status:    status,
+		Labels:    s.Spec.Selector.MatchLabels,
+		Health:    statefulSetHealth(s),
+	}
+}
+
 func listDaemonSets(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]WorkloadInfo, error) {
 	ds, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -179,26 +213,31 @@ func listDaemonSets(ctx context.Context, clientset *kubernetes.Clientset, namesp
 	}
 
 	var workloads []WorkloadInfo
-	for _, d := range ds.Items {
-		status := "Running"
-		if d.Status.NumberReady < d.Status.DesiredNumberScheduled {
-			status = "Progressing"
-		}
-
-		workloads = append(workloads, WorkloadInfo{
-			Name:      d.Name,
-			Namespace: d.Namespace,
-			Type:      ResourceDaemonSets,
-			Ready:     fmt.Sprintf("%d/%d", d.Status.NumberReady, d.Status.DesiredNumberScheduled),
-			Replicas:  d.Status.DesiredNumberScheduled,
-			Age:       formatAge(d.CreationTimestamp.Time),
-			Status:    status,
-			Labels:    d.Spec.Selector.MatchLabels,
-		})
+	for i := range ds.Items {
+		workloads = append(workloads, daemonSetToWorkloadInfo(&ds.Items[i]))
 	}
 	return workloads, nil
 }
 
+func daemonSetToWorkloadInfo(d *appsv1.DaemonSet) WorkloadInfo {
+	status := "Running"
+	if d.Status.NumberReady < d.Status.DesiredNumberScheduled {
+		status = "Progressing"
+	}
+
+	return WorkloadInfo{
+		Name:      d.Name,
+		Namespace: d.Namespace,
+		Type:      ResourceDaemonSets,
+		Ready:     fmt.Sprintf("%d/%d", d.Status.NumberReady, d.Status.DesiredNumberScheduled),
+		Replicas:  d.Status.DesiredNumberScheduled,
+		Age:       formatAge(d.CreationTimestamp.Time),
+		Status:    status,
+		Labels:    d.Spec.Selector.MatchLabels,
+		Health:    daemonSetHealth(d),
+	}
+}
+
 func listJobs(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]WorkloadInfo, error) {
 	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -206,27 +245,38 @@ func listJobs(ctx context.Context, clientset *kubernetes.Clientset, namespace st
 	}
 
 	var workloads []WorkloadInfo
-	for _, j := range jobs.Items {
-		status := "Running"
-		if j.Status.Succeeded > 0 {
-			status = "Completed"
-		} else if j.Status.Failed > 0 {
-			status = "Failed"
-		}
-
-		workloads = append(workloads, WorkloadInfo{
-			Name:      j.Name,
-			Namespace: j.Namespace,
-			Type:      ResourceJobs,
-			Ready:     fmt.Sprintf("%d/%d", j.Status.Succeeded, *j.Spec.Completions),
-			Age:       formatAge(j.CreationTimestamp.Time),
-			Status:    status,
-			Labels:    j.Spec.Selector.MatchLabels,
-		})
+	for i := range jobs.Items {
+		workloads = append(workloads, jobToWorkloadInfo(&jobs.Items[i]))
 	}
 	return workloads, nil
 }
 
+func jobToWorkloadInfo(j *batchv1.Job) WorkloadInfo {
+	status := "Running"
+	if j.Status.Succeeded > 0 {
+		status = "Completed"
+	} else if j.Status.Failed > 0 {
+		status = "Failed"
+	}
+
+	// Work-queue jobs leave completions unset; one success completes them
+	completions := int32(1)
+	if j.Spec.Completions != nil {
+		completions = *j.Spec.Completions
+	}
+
+	return WorkloadInfo{
+		Name:      j.Name,
+		Namespace: j.Namespace,
+		Type:      ResourceJobs,
+		Ready:     fmt.Sprintf("%d/%d", j.Status.Succeeded, completions),
+		Age:       formatAge(j.CreationTimestamp.Time),
+		Status:    status,
+		Labels:    j.Spec.Selector.MatchLabels,
+		Health:    jobHealth(j),
+	}
+}
+
 func listCronJobs(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]WorkloadInfo, error) {
 	cjs, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -234,24 +284,28 @@ func listCronJobs(ctx context.Context, clientset *kubernetes.Clientset, namespac
 	}
 
 	var workloads []WorkloadInfo
-	for _, cj := range cjs.Items {
-		status := "Active"
-		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
-			status = "Suspended"
-		}
-
-		workloads = append(workloads, WorkloadInfo{
-			Name:      cj.Name,
-			Namespace: cj.Namespace,
-			Type:      ResourceCronJobs,
-			Ready:     fmt.Sprintf("%d active", len(cj.Status.Active)),
-			Age:       formatAge(cj.CreationTimestamp.Time),
-			Status:    status,
-		})
+	for i := range cjs.Items {
+		workloads = append(workloads, cronJobToWorkloadInfo(&cjs.Items[i]))
 	}
 	return workloads, nil
 }
 
+func cronJobToWorkloadInfo(cj *batchv1.CronJob) WorkloadInfo {
+	status := "Active"
+	if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+		status = "Suspended"
+	}
+
+	return WorkloadInfo{
+		Name:      cj.Name,
+		Namespace: cj.Namespace,
+		Type:      ResourceCronJobs,
+		Ready:     fmt.Sprintf("%d active", len(cj.Status.Active)),
+		Age:       formatAge(cj.CreationTimestamp.Time),
+		Status:    status,
+	}
+}
+
 func listPodsAsWorkloads(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]WorkloadInfo, error) {
 	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -259,31 +313,81 @@ func listPodsAsWorkloads(ctx context.Context, clientset *kubernetes.Clientset, n
 	}
 
 	var workloads []WorkloadInfo
-	for _, p := range pods.Items {
-		var restartCount int32
-		for _, cs := range p.Status.ContainerStatuses {
-			restartCount += cs.RestartCount
-		}
+	for i := range pods.Items {
+		workloads = append(workloads, podToWorkloadInfo(&pods.Items[i]))
+	}
+	return workloads, nil
+}
 
-		ready := 0
-		for _, cs := range p.Status.ContainerStatuses {
-			if cs.Ready {
-				ready++
-			}
+func podToWorkloadInfo(p *corev1.Pod) WorkloadInfo {
+	var restartCount int32
+	for _, cs := range p.Status.ContainerStatuses {
+		restartCount += cs.RestartCount
+	}
+
+	ready := 0
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
 		}
+	}
 
-		workloads = append(workloads, WorkloadInfo{
-			Name:         p.Name,
-			Namespace:    p.Namespace,
-			Type:         ResourcePods,
-			Ready:        fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers)),
-			Age:          formatAge(p.CreationTimestamp.Time),
-			Status:       string(p.Status.Phase),
-			Labels:       p.Labels,
-			RestartCount: restartCount,
-		})
+	return WorkloadInfo{
+		Name:         p.Name,
+		Namespace:    p.Namespace,
+		Type:         ResourcePods,
+		Ready:        fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers)),
+		Age:          formatAge(p.CreationTimestamp.Time),
+		Status:       string(p.Status.Phase),
+		Labels:       p.Labels,
+		RestartCount: restartCount,
 	}
-	return workloads, nil
+}
+
+// GetWorkload fetches a single workload by name.
+func GetWorkload(ctx context.Context, clientset *kubernetes.Clientset, namespace string, resourceType ResourceType, name string) (*WorkloadInfo, error) {
+	var info WorkloadInfo
+	switch resourceType {
+	case ResourceDeployments:
+		d, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = deploymentToWorkloadInfo(d)
+	case ResourceStatefulSets:
+		s, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = statefulSetToWorkloadInfo(s)
+	case ResourceDaemonSets:
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = daemonSetToWorkloadInfo(ds)
+	case ResourceJobs:
+		j, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = jobToWorkloadInfo(j)
+	case ResourceCronJobs:
+		cj, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = cronJobToWorkloadInfo(cj)
+	case ResourcePods:
+		p, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		info = podToWorkloadInfo(p)
+	default:
+		return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+	}
+	return &info, nil
 }
 
 func GetWorkloadPods(ctx context.Context, clientset *kubernetes.Clientset, workload WorkloadInfo) ([]PodInfo, error) {
@@ -354,6 +458,8 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 				ci.State = "Terminated"
 				ci.Reason = cs.State.Terminated.Reason
 			}
+			ci.Terminated = terminationInfo(cs.State.Terminated)
+			ci.LastTerminated = terminationInfo(cs.LastTerminationState.Terminated)
 		}
 
 		containers = append(containers, ci)
@@ -366,6 +472,11 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 		}
 	}
 
+	var initContainers []string
+	for _, c := range p.Spec.InitContainers {
+		initContainers = append(initContainers, c.Name)
+	}
+
 	var ownerRef, ownerKind string
 	if len(p.OwnerReferences) > 0 {
 		ownerRef = p.OwnerReferences[0].Name
@@ -373,20 +484,24 @@ func podToPodInfo(p *corev1.Pod) PodInfo {
 	}
 
 	return PodInfo{
-		Name:       p.Name,
-		Namespace:  p.Namespace,
-		Node:       p.Spec.NodeName,
-		Status:     getPodStatus(p),
-		Ready:      fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers)),
-		Restarts:   restarts,
-		Age:        formatAge(p.CreationTimestamp.Time),
-		IP:         p.Status.PodIP,
-		Labels:     p.Labels,
-		Containers: containers,
-		Conditions: p.Status.Conditions,
-		Phase:      p.Status.Phase,
-		OwnerRef:   ownerRef,
-		OwnerKind:  ownerKind,
+		Name:         p.Name,
+		Namespace:    p.Namespace,
+		Node:         p.Spec.NodeName,
+		Status:       getPodStatus(p),
+		Ready:        fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers)),
+		Restarts:     restarts,
+		Age:          formatAge(p.CreationTimestamp.Time),
+		IP:           p.Status.PodIP,
+		Labels:       p.Labels,
+		Annotations:  p.Annotations,
+		NodeSelector: p.Spec.NodeSelector,
+		Containers:   containers,
+		Conditions:   p.Status.Conditions,
+		Phase:        p.Status.Phase,
+		OwnerRef:     ownerRef,
+		OwnerKind:    ownerKind,
+
+		InitContainers: initContainers,
 	}
 }
 
@@ -1,7 +1,12 @@
 package k8s
 
 import (
+	"strings"
 	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestLabelsMatch(t *testing.T) {
@@ -98,3 +103,37 @@ func TestAllResourceTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestJobToWorkloadInfoWorkQueue(t *testing.T) {
+	// A work-queue job sets parallelism and leaves completions unset
+	parallelism := int32(3)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "batch"},
+		Spec: batchv1.JobSpec{
+			Parallelism: &parallelism,
+			Completions: nil,
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "worker"}},
+		},
+		Status: batchv1.JobStatus{Active: 3},
+	}
+
+	info := jobToWorkloadInfo(job)
+	if info.Ready != "0/1" {
+		t.Errorf("Ready = %q, want 0/1", info.Ready)
+	}
+	if info.Health == nil || info.Health.Desired != 1 {
+		t.Errorf("Health = %+v, want Desired 1", info.Health)
+	}
+}
+
+func TestPodContainerNames(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate"}},
+		Containers:     []corev1.Container{{Name: "app"}, {Name: "istio-proxy"}},
+	}}
+
+	names := podToPodInfo(pod).ContainerNames()
+	if strings.Join(names, ",") != "app,istio-proxy,migrate" {
+		t.Errorf("ContainerNames() = %v, want app containers then init containers", names)
+	}
+}
@@ -0,0 +1,287 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// RequestHeadroom is applied to p95 usage for requests, LimitHeadroom
+	// to the observed maximum for limits.
+	RequestHeadroom = 1.15
+	LimitHeadroom   = 1.3
+
+	// MinRecommendSamples is how many samples are needed before usage is
+	// trusted for a recommendation.
+	MinRecommendSamples = 10
+
+	minCPURequestMilli    = 10
+	minMemoryRequestBytes = 32 * 1024 * 1024
+)
+
+// Recommendation suggests requests and limits for a container from its
+// observed usage, next to what it currently has.
+type Recommendation struct {
+	Container string
+	Current   ResourceRequirements
+	Samples   int
+
+	CPUP95Milli int64
+	CPUMaxMilli int64
+	MemP95Bytes int64
+	MemMaxBytes int64
+
+	CPURequestMilli    int64
+	CPULimitMilli      int64
+	MemoryRequestBytes int64
+	MemoryLimitBytes   int64
+}
+
+// CPURequest and friends return the suggestions as quantity strings.
+func (r Recommendation) CPURequest() string {
+	return resource.NewMilliQuantity(r.CPURequestMilli, resource.DecimalSI).String()
+}
+
+func (r Recommendation) CPULimit() string {
+	return resource.NewMilliQuantity(r.CPULimitMilli, resource.DecimalSI).String()
+}
+
+func (r Recommendation) MemoryRequest() string {
+	return resource.NewQuantity(r.MemoryRequestBytes, resource.BinarySI).String()
+}
+
+func (r Recommendation) MemoryLimit() string {
+	return resource.NewQuantity(r.MemoryLimitBytes, resource.BinarySI).String()
+}
+
+// RecommendResources suggests requests from p95 usage and limits from the
+// maximum, each with headroom. CPU is rounded up to 10m and memory to Mi.
+// It returns false when there are too few samples to go on.
+func RecommendResources(c ContainerInfo, cpuMilli, memBytes []int64) (Recommendation, bool) {
+	rec := Recommendation{Container: c.Name, Current: c.Resources}
+	rec.Samples = minInt(len(cpuMilli), len(memBytes))
+	if rec.Samples < MinRecommendSamples {
+		return rec, false
+	}
+
+	rec.CPUP95Milli = percentile(cpuMilli, 95)
+	rec.CPUMaxMilli = percentile(cpuMilli, 100)
+	rec.MemP95Bytes = percentile(memBytes, 95)
+	rec.MemMaxBytes = percentile(memBytes, 100)
+
+	rec.CPURequestMilli = roundUp(int64(float64(rec.CPUP95Milli)*RequestHeadroom), 10)
+	if rec.CPURequestMilli < minCPURequestMilli {
+		rec.CPURequestMilli = minCPURequestMilli
+	}
+	rec.CPULimitMilli = roundUp(int64(float64(rec.CPUMaxMilli)*LimitHeadroom), 10)
+	if rec.CPULimitMilli < rec.CPURequestMilli {
+		rec.CPULimitMilli = rec.CPURequestMilli
+	}
+
+	const mib = 1024 * 1024
+	rec.MemoryRequestBytes = roundUp(int64(float64(rec.MemP95Bytes)*RequestHeadroom), mib)
+	if rec.MemoryRequestBytes < minMemoryRequestBytes {
+		rec.MemoryRequestBytes = minMemoryRequestBytes
+	}
+	rec.MemoryLimitBytes = roundUp(int64(float64(rec.MemMaxBytes)*LimitHeadroom), mib)
+	if rec.MemoryLimitBytes < rec.MemoryRequestBytes {
+		rec.MemoryLimitBytes = rec.MemoryRequestBytes
+	}
+
+	return rec, true
+}
+
+// RecommendFromSamples recommends from the metrics-server samples recorded
+// during the session.
+func RecommendFromSamples(c ContainerInfo, samples []MetricSample) (Recommendation, bool) {
+	cpu := make([]int64, len(samples))
+	mem := make([]int64, len(samples))
+	for i, s := range samples {
+		cpu[i] = s.CPUMilli
+		mem[i] = s.MemoryBytes
+	}
+	return RecommendResources(c, cpu, mem)
+}
+
+// RecommendFromRange recommends from metrics provider series.
+func RecommendFromRange(c ContainerInfo, r *ContainerRangeMetrics) (Recommendation, bool) {
+	cpu := make([]int64, len(r.CPU))
+	for i, p := range r.CPU {
+		cpu[i] = int64(p.Value * 1000)
+	}
+	mem := make([]int64, len(r.Memory))
+	for i, p := range r.Memory {
+		mem[i] = int64(p.Value)
+	}
+	return RecommendResources(c, cpu, mem)
+}
+
+// percentile returns the nearest-rank percentile p (0-100) of values.
+func percentile(values []int64, p float64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func roundUp(v, step int64) int64 {
+	if v%step == 0 {
+		return v
+	}
+	return (v/step + 1) * step
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// SetResourcesCommand returns the kubectl set resources command applying
+// rec to the workload.
+func SetResourcesCommand(rt ResourceType, namespace, name string, rec Recommendation) string {
+	return fmt.Sprintf("kubectl set resources %s/%s -n %s -c %s --requests=cpu=%s,memory=%s --limits=cpu=%s,memory=%s",
+		strings.ToLower(rt.Kind()), name, namespace, rec.Container,
+		rec.CPURequest(), rec.MemoryRequest(), rec.CPULimit(), rec.MemoryLimit())
+}
+
+// WorkloadContainers returns the names of the containers in a workload's
+// pod template. Sidecars injected into pods at admission are not among them.
+func WorkloadContainers(ctx context.Context, clientset *kubernetes.Clientset, namespace string, rt ResourceType, name string) ([]string, error) {
+	var spec corev1.PodSpec
+	switch rt {
+	case ResourceDeployments:
+		deploy, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = deploy.Spec.Template.Spec
+	case ResourceStatefulSets:
+		sts, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = sts.Spec.Template.Spec
+	case ResourceDaemonSets:
+		ds, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = ds.Spec.Template.Spec
+	case ResourceJobs:
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = job.Spec.Template.Spec
+	case ResourceCronJobs:
+		cj, err := clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		spec = cj.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil, fmt.Errorf("%s has no pod template", rt.Kind())
+	}
+
+	names := make([]string, 0, len(spec.Containers))
+	for _, c := range spec.Containers {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// TemplateRecommendations keeps the recommendations for containers in the
+// pod template. Patching any other container, such as an injected
+// istio-proxy, would add it to the template without an image and the API
+// would reject the patch; those are returned as skipped.
+func TemplateRecommendations(recs []Recommendation, containers []string) (kept []Recommendation, skipped []string) {
+	inTemplate := make(map[string]bool, len(containers))
+	for _, name := range containers {
+		inTemplate[name] = true
+	}
+	for _, rec := range recs {
+		if inTemplate[rec.Container] {
+			kept = append(kept, rec)
+		} else {
+			skipped = append(skipped, rec.Container)
+		}
+	}
+	return kept, skipped
+}
+
+// ResourcesPatch returns a strategic merge patch setting the resources of
+// every recommended container in the workload's pod template. Containers
+// are merged by name, so others are left alone.
+func ResourcesPatch(recs []Recommendation) ([]byte, error) {
+	containers := make([]map[string]interface{}, 0, len(recs))
+	for _, rec := range recs {
+		containers = append(containers, map[string]interface{}{
+			"name": rec.Container,
+			"resources": map[string]interface{}{
+				"requests": map[string]string{"cpu": rec.CPURequest(), "memory": rec.MemoryRequest()},
+				"limits":   map[string]string{"cpu": rec.CPULimit(), "memory": rec.MemoryLimit()},
+			},
+		})
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": containers},
+			},
+		},
+	})
+}
+
+// PatchCommand returns the kubectl patch command equivalent to
+// ApplyResources.
+func PatchCommand(rt ResourceType, namespace, name string, recs []Recommendation) (string, error) {
+	patch, err := ResourcesPatch(recs)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("kubectl patch %s %s -n %s --type strategic -p '%s'",
+		strings.ToLower(rt.Kind()), name, namespace, patch), nil
+}
+
+// ApplyResources patches the workload's pod template with recs, which
+// rolls its pods. Only workloads with a mutable pod template are supported.
+func ApplyResources(ctx context.Context, clientset *kubernetes.Clientset, namespace string, rt ResourceType, name string, recs []Recommendation) error {
+	patch, err := ResourcesPatch(recs)
+	if err != nil {
+		return err
+	}
+
+	switch rt {
+	case ResourceDeployments:
+		_, err = clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceStatefulSets:
+		_, err = clientset.AppsV1().StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case ResourceDaemonSets:
+		_, err = clientset.AppsV1().DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("cannot change resources of %s", rt.Kind())
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecommendResources(t *testing.T) {
+	c := ContainerInfo{Name: "app", Resources: ResourceRequirements{CPURequest: "1", MemoryRequest: "1Gi"}}
+
+	// 20 samples: 100m..290m and 100Mi..290Mi, so p95 is the 19th value
+	var cpu, mem []int64
+	for i := int64(0); i < 20; i++ {
+		cpu = append(cpu, 100+i*10)
+		mem = append(mem, (100+i*10)*mi)
+	}
+
+	rec, ok := RecommendResources(c, cpu, mem)
+	if !ok {
+		t.Fatal("RecommendResources() should have enough samples")
+	}
+	if rec.CPUP95Milli != 280 || rec.CPUMaxMilli != 290 {
+		t.Errorf("cpu p95/max = %d/%d, want 280/290", rec.CPUP95Milli, rec.CPUMaxMilli)
+	}
+	// 280m * 1.15 = 322m -> 330m, 290m * 1.3 = 377m -> 380m
+	if rec.CPURequest() != "330m" || rec.CPULimit() != "380m" {
+		t.Errorf("cpu = %s/%s, want 330m/380m", rec.CPURequest(), rec.CPULimit())
+	}
+	// 280Mi * 1.15 = 322Mi, 290Mi * 1.3 = 377Mi
+	if rec.MemoryRequest() != "322Mi" || rec.MemoryLimit() != "377Mi" {
+		t.Errorf("memory = %s/%s, want 322Mi/377Mi", rec.MemoryRequest(), rec.MemoryLimit())
+	}
+	if rec.Current.CPURequest != "1" {
+		t.Errorf("Current = %+v, want the container's resources", rec.Current)
+	}
+}
+
+func TestRecommendResourcesMinimums(t *testing.T) {
+	idle := make([]int64, MinRecommendSamples)
+	rec, ok := RecommendResources(ContainerInfo{Name: "idle"}, idle, idle)
+	if !ok {
+		t.Fatal("RecommendResources() should have enough samples")
+	}
+	if rec.CPURequest() != "10m" || rec.CPULimit() != "10m" {
+		t.Errorf("cpu = %s/%s, want 10m/10m", rec.CPURequest(), rec.CPULimit())
+	}
+	if rec.MemoryRequest() != "32Mi" || rec.MemoryLimit() != "32Mi" {
+		t.Errorf("memory = %s/%s, want 32Mi/32Mi", rec.MemoryRequest(), rec.MemoryLimit())
+	}
+
+	if _, ok := RecommendResources(ContainerInfo{Name: "new"}, idle[:3], idle[:3]); ok {
+		t.Error("RecommendResources() should refuse fewer than MinRecommendSamples samples")
+	}
+}
+
+func TestResourcesCommands(t *testing.T) {
+	rec := Recommendation{
+		Container:          "app",
+		CPURequestMilli:    250,
+		CPULimitMilli:      1000,
+		MemoryRequestBytes: 256 * mi,
+		MemoryLimitBytes:   512 * mi,
+	}
+
+	cmd := SetResourcesCommand(ResourceDeployments, "shop", "api", rec)
+	want := "kubectl set resources deployment/api -n shop -c app --requests=cpu=250m,memory=256Mi --limits=cpu=1,memory=512Mi"
+	if cmd != want {
+		t.Errorf("SetResourcesCommand() = %q, want %q", cmd, want)
+	}
+
+	patch, err := ResourcesPatch([]Recommendation{rec})
+	if err != nil {
+		t.Fatalf("ResourcesPatch() error = %v", err)
+	}
+	var parsed struct {
+		Spec struct {
+			Template struct {
+				Spec struct {
+					Containers []struct {
+						Name      string
+						Resources struct {
+							Requests map[string]string
+							Limits   map[string]string
+						}
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(patch, &parsed); err != nil {
+		t.Fatalf("ResourcesPatch() is not JSON: %v", err)
+	}
+	containers := parsed.Spec.Template.Spec.Containers
+	if len(containers) != 1 || containers[0].Name != "app" ||
+		containers[0].Resources.Requests["memory"] != "256Mi" || containers[0].Resources.Limits["cpu"] != "1" {
+		t.Errorf("ResourcesPatch() = %s", patch)
+	}
+
+	patchCmd, err := PatchCommand(ResourceStatefulSets, "shop", "db", []Recommendation{rec})
+	if err != nil || !strings.HasPrefix(patchCmd, "kubectl patch statefulset db -n shop --type strategic -p '{") {
+		t.Errorf("PatchCommand() = %q, %v", patchCmd, err)
+	}
+}
+
+func TestTemplateRecommendations(t *testing.T) {
+	// istio-proxy was injected at admission and is not in the template
+	recs := []Recommendation{
+		{Container: "app", CPURequestMilli: 250, CPULimitMilli: 500, MemoryRequestBytes: 128 * mi, MemoryLimitBytes: 256 * mi},
+		{Container: "istio-proxy", CPURequestMilli: 10, CPULimitMilli: 100, MemoryRequestBytes: 32 * mi, MemoryLimitBytes: 64 * mi},
+	}
+
+	kept, skipped := TemplateRecommendations(recs, []string{"app"})
+	if len(kept) != 1 || kept[0].Container != "app" {
+		t.Errorf("kept = %+v, want only app", kept)
+	}
+	if len(skipped) != 1 || skipped[0] != "istio-proxy" {
+		t.Errorf("skipped = %v, want [istio-proxy]", skipped)
+	}
+
+	patch, err := ResourcesPatch(kept)
+	if err != nil {
+		t.Fatalf("ResourcesPatch() error = %v", err)
+	}
+	if strings.Contains(string(patch), "istio-proxy") {
+		t.Errorf("ResourcesPatch() = %s, should leave istio-proxy out", patch)
+	}
+}
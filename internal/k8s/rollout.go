@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RolloutState is how far a workload or pod is from being healthy.
+type RolloutState int
+
+const (
+	RolloutProgressing RolloutState = iota
+	RolloutComplete
+	RolloutFailed
+)
+
+// RolloutStatus reports whether a workload has finished rolling out, along
+// the lines of kubectl rollout status, with a message saying what is still
+// pending or why the rollout failed. Only failures the controller gives up
+// on are RolloutFailed; crash-looping pods keep a rollout progressing.
+func RolloutStatus(w *WorkloadInfo) (RolloutState, string) {
+	h := w.Health
+	if h == nil {
+		return RolloutFailed, fmt.Sprintf("%s have no rollout to wait for", w.Type)
+	}
+	if h.Stale() {
+		return RolloutProgressing, fmt.Sprintf("waiting for the controller to observe generation %d", h.Generation)
+	}
+
+	switch w.Type {
+	case ResourceDeployments:
+		if c := h.Condition(string(appsv1.DeploymentProgressing)); c != nil && c.Reason == "ProgressDeadlineExceeded" {
+			return RolloutFailed, "progress deadline exceeded: " + conditionSuggestion(c)
+		}
+		switch {
+		case h.Paused:
+			return RolloutProgressing, "rollout is paused"
+		case h.Updated < h.Desired:
+			return RolloutProgressing, fmt.Sprintf("%d of %d replicas updated", h.Updated, h.Desired)
+		case w.Replicas > h.Updated:
+			return RolloutProgressing, fmt.Sprintf("%d old replicas pending termination", w.Replicas-h.Updated)
+		case h.Available < h.Updated:
+			return RolloutProgressing, fmt.Sprintf("%d of %d updated replicas available", h.Available, h.Updated)
+		}
+		return RolloutComplete, fmt.Sprintf("%d of %d replicas updated and available", h.Available, h.Desired)
+
+	case ResourceStatefulSets:
+		switch {
+		case h.Ready < h.Desired:
+			return RolloutProgressing, fmt.Sprintf("%d of %d pods ready", h.Ready, h.Desired)
+		case h.Updated < h.Desired:
+			return RolloutProgressing, fmt.Sprintf("%d of %d pods updated", h.Updated, h.Desired)
+		case h.UpdateRevision != "" && h.CurrentRevision != h.UpdateRevision:
+			return RolloutProgressing, "waiting for pods to reach revision " + h.UpdateRevision
+		}
+		return RolloutComplete, fmt.Sprintf("%d of %d pods updated and ready", h.Ready, h.Desired)
+
+	case ResourceDaemonSets:
+		switch {
+		case h.Updated < h.Desired:
+			return RolloutProgressing, fmt.Sprintf("%d of %d pods updated", h.Updated, h.Desired)
+		case h.Available < h.Desired:
+			return RolloutProgressing, fmt.Sprintf("%d of %d updated pods available", h.Available, h.Desired)
+		}
+		return RolloutComplete, fmt.Sprintf("%d of %d pods updated and available", h.Available, h.Desired)
+
+	case ResourceJobs:
+		if c := h.Condition(string(batchv1.JobFailed)); c != nil && c.Status == string(corev1.ConditionTrue) {
+			return RolloutFailed, "job failed: " + conditionSuggestion(c)
+		}
+		if c := h.Condition(string(batchv1.JobComplete)); c != nil && c.Status == string(corev1.ConditionTrue) {
+			return RolloutComplete, fmt.Sprintf("%d of %d completions", h.Ready, h.Desired)
+		}
+		if h.Paused {
+			return RolloutProgressing, "job is suspended"
+		}
+		return RolloutProgressing, fmt.Sprintf("%d of %d completions, %d active, %d failed", h.Ready, h.Desired, h.Active, h.Failed)
+	}
+	return RolloutFailed, fmt.Sprintf("%s have no rollout to wait for", w.Type)
+}
+
+// PodRolloutStatus reports whether a pod is healthy: running with every
+// container ready, or completed.
+func PodRolloutStatus(pod *PodInfo) (RolloutState, string) {
+	switch pod.Phase {
+	case corev1.PodSucceeded:
+		return RolloutComplete, "completed"
+	case corev1.PodFailed:
+		return RolloutFailed, "pod failed: " + pod.Status
+	case corev1.PodRunning:
+		if pod.Status != "Terminating" && allContainersReady(pod) {
+			return RolloutComplete, fmt.Sprintf("running, %s ready", pod.Ready)
+		}
+	}
+	return RolloutProgressing, fmt.Sprintf("%s, %s ready", pod.Status, pod.Ready)
+}
+
+func allContainersReady(pod *PodInfo) bool {
+	for _, c := range pod.Containers {
+		if !c.Ready {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRolloutStatusDeployment(t *testing.T) {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3},
+	}
+
+	tests := []struct {
+		name  string
+		apply func()
+		state RolloutState
+		msg   string
+	}{
+		{"stale", func() {}, RolloutProgressing, "waiting for the controller to observe generation 2"},
+		{"updating", func() { d.Status.ObservedGeneration = 2; d.Status.Replicas = 4; d.Status.UpdatedReplicas = 1 }, RolloutProgressing, "1 of 3 replicas updated"},
+		{"old replicas", func() { d.Status.UpdatedReplicas = 3 }, RolloutProgressing, "1 old replicas pending termination"},
+		{"unavailable", func() { d.Status.Replicas = 3; d.Status.AvailableReplicas = 2 }, RolloutProgressing, "2 of 3 updated replicas available"},
+		{"complete", func() { d.Status.AvailableReplicas = 3 }, RolloutComplete, "3 of 3 replicas updated and available"},
+		{"deadline", func() {
+			d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse,
+				Reason: "ProgressDeadlineExceeded", Message: `ReplicaSet "api-7d9" has timed out progressing.`}}
+		}, RolloutFailed, `progress deadline exceeded: ReplicaSet "api-7d9" has timed out progressing.`},
+	}
+	for _, tt := range tests {
+		tt.apply()
+		w := deploymentToWorkloadInfo(d)
+		if state, msg := RolloutStatus(&w); state != tt.state || msg != tt.msg {
+			t.Errorf("%s: RolloutStatus() = %d, %q, want %d, %q", tt.name, state, msg, tt.state, tt.msg)
+		}
+	}
+}
+
+func TestRolloutStatusStatefulSetAndDaemonSet(t *testing.T) {
+	s := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: int32Ptr(2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: 2, UpdatedReplicas: 2,
+			CurrentRevision: "db-1", UpdateRevision: "db-2"},
+	}
+	w := statefulSetToWorkloadInfo(s)
+	if state, msg := RolloutStatus(&w); state != RolloutProgressing || msg != "waiting for pods to reach revision db-2" {
+		t.Errorf("statefulset mid-update: %d, %q", state, msg)
+	}
+	s.Status.CurrentRevision = "db-2"
+	w = statefulSetToWorkloadInfo(s)
+	if state, _ := RolloutStatus(&w); state != RolloutComplete {
+		t.Errorf("statefulset at update revision should be complete, got %d", state)
+	}
+
+	ds := &appsv1.DaemonSet{
+		Spec:   appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}}},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+	}
+	w = daemonSetToWorkloadInfo(ds)
+	if state, msg := RolloutStatus(&w); state != RolloutProgressing || msg != "2 of 3 updated pods available" {
+		t.Errorf("daemonset: %d, %q", state, msg)
+	}
+}
+
+func TestRolloutStatusJob(t *testing.T) {
+	j := &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			Completions: int32Ptr(1),
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "migrate"}},
+		},
+		Status: batchv1.JobStatus{Active: 1, Failed: 2},
+	}
+	w := jobToWorkloadInfo(j)
+	if state, msg := RolloutStatus(&w); state != RolloutProgressing || msg != "0 of 1 completions, 1 active, 2 failed" {
+		t.Errorf("running job: %d, %q", state, msg)
+	}
+
+	j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue,
+		Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}}
+	w = jobToWorkloadInfo(j)
+	if state, msg := RolloutStatus(&w); state != RolloutFailed || msg != "job failed: Job has reached the specified backoff limit" {
+		t.Errorf("failed job: %d, %q", state, msg)
+	}
+
+	j.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	j.Status.Succeeded = 1
+	w = jobToWorkloadInfo(j)
+	if state, _ := RolloutStatus(&w); state != RolloutComplete {
+		t.Errorf("completed job should be complete, got %d", state)
+	}
+}
+
+func TestPodRolloutStatus(t *testing.T) {
+	pod := &PodInfo{Phase: corev1.PodRunning, Status: "Running", Ready: "1/2",
+		Containers: []ContainerInfo{{Name: "app", Ready: true}, {Name: "sidecar"}}}
+	if state, msg := PodRolloutStatus(pod); state != RolloutProgressing || msg != "Running, 1/2 ready" {
+		t.Errorf("unready pod: %d, %q", state, msg)
+	}
+
+	pod.Containers[1].Ready = true
+	pod.Ready = "2/2"
+	if state, _ := PodRolloutStatus(pod); state != RolloutComplete {
+		t.Errorf("ready pod should be complete, got %d", state)
+	}
+
+	pod.Phase, pod.Status = corev1.PodFailed, "Error"
+	if state, msg := PodRolloutStatus(pod); state != RolloutFailed || msg != "pod failed: Error" {
+		t.Errorf("failed pod: %d, %q", state, msg)
+	}
+}
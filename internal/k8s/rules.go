@@ -0,0 +1,710 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severities a rule can report, most severe first.
+var Severities = []string{"High", "Medium", "Warning", "Info"}
+
+// SeverityRank orders severities for sorting; lower is more severe and
+// unknown severities sort last.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// Rule is a failure signature. Every condition set in Match has to hold
+// for the rule to fire. Issue and Suggestions may reference what matched
+// with {container}, {reason}, {event}, {log}, {exit_code}, {exit_meaning}
+// and {termination_message}; a suggestion referencing an empty value is
+// left out.
+type Rule struct {
+	Name        string    `json:"name"`
+	Issue       string    `json:"issue"`
+	Severity    string    `json:"severity"`
+	Suggestions []string  `json:"suggestions,omitempty"`
+	Docs        []string  `json:"docs,omitempty"`
+	Match       RuleMatch `json:"match"`
+
+	// Disabled turns off a built-in rule of the same name.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// regexps are the rule's patterns compiled by Compile, by expression
+	regexps map[string]*regexp.Regexp
+}
+
+// RuleMatch holds the pod-level conditions of a rule. Label and annotation
+// values are regular expressions that must match in full; an empty value
+// only requires the key.
+type RuleMatch struct {
+	Status             []string          `json:"status,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	MissingLabels      []string          `json:"missing_labels,omitempty"`
+	Annotations        map[string]string `json:"annotations,omitempty"`
+	MissingAnnotations []string          `json:"missing_annotations,omitempty"`
+	Node               string            `json:"node,omitempty"`
+	NodeSelector       map[string]string `json:"node_selector,omitempty"`
+	MinRestarts        int32             `json:"min_restarts,omitempty"`
+
+	// MissingContainer fires when no container has this name, e.g. a
+	// sidecar that should have been injected.
+	MissingContainer string `json:"missing_container,omitempty"`
+
+	Container *ContainerMatch `json:"container,omitempty"`
+	Event     *EventMatch     `json:"event,omitempty"`
+
+	// Log is a regular expression matched against the recent log lines.
+	Log string `json:"log,omitempty"`
+}
+
+// ContainerMatch matches a single container; the rule fires once for each
+// container that matches.
+type ContainerMatch struct {
+	Name          string   `json:"name,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	State         []string `json:"state,omitempty"`
+	Reason        []string `json:"reason,omitempty"`
+	MinRestarts   int32    `json:"min_restarts,omitempty"`
+	NoMemoryLimit bool     `json:"no_memory_limit,omitempty"`
+	NoCPULimit    bool     `json:"no_cpu_limit,omitempty"`
+
+	// ExitCode and TerminationReason match how the container last ended
+	// while that is still relevant, see ContainerInfo.RecentTermination
+	ExitCode          []int32  `json:"exit_code,omitempty"`
+	TerminationReason []string `json:"termination_reason,omitempty"`
+}
+
+// EventMatch matches a single event of the pod; the rule fires once for
+// each event that matches.
+type EventMatch struct {
+	Type    string   `json:"type,omitempty"`
+	Reason  []string `json:"reason,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// DiagnosticInput is what rules are evaluated against.
+type DiagnosticInput struct {
+	Pod    *PodInfo
+	Events []EventInfo
+	Logs   []LogLine
+}
+
+// Validate checks the rule and that its regular expressions compile.
+func (r Rule) Validate() error {
+	_, err := r.compile()
+	return err
+}
+
+// Compile validates the rule and keeps its regular expressions compiled,
+// so evaluating it for every pod and container does not compile them
+// again.
+func (r *Rule) Compile() error {
+	regexps, err := r.compile()
+	if err != nil {
+		return err
+	}
+	r.regexps = regexps
+	return nil
+}
+
+func (r Rule) compile() (map[string]*regexp.Regexp, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("rule has no name")
+	}
+	if r.Disabled {
+		return nil, nil
+	}
+	if r.Issue == "" {
+		return nil, fmt.Errorf("rule %s: issue is required", r.Name)
+	}
+	if SeverityRank(r.Severity) == len(Severities) {
+		return nil, fmt.Errorf("rule %s: severity %q is not one of %s", r.Name, r.Severity, strings.Join(Severities, ", "))
+	}
+
+	// Names, nodes and label values match in full, the rest anywhere
+	full := []string{r.Match.Node}
+	for _, m := range []map[string]string{r.Match.Labels, r.Match.Annotations, r.Match.NodeSelector} {
+		for _, v := range m {
+			full = append(full, v)
+		}
+	}
+	partial := []string{r.Match.Log}
+	if c := r.Match.Container; c != nil {
+		full = append(full, c.Name)
+		partial = append(partial, c.Image)
+	}
+	if e := r.Match.Event; e != nil {
+		partial = append(partial, e.Message)
+	}
+
+	regexps := map[string]*regexp.Regexp{}
+	for _, p := range partial {
+		if _, err := compilePattern(regexps, p); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+	for _, p := range full {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		if _, err := compilePattern(regexps, fullPattern(p)); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+	return regexps, nil
+}
+
+func compilePattern(regexps map[string]*regexp.Regexp, expr string) (*regexp.Regexp, error) {
+	if re, ok := regexps[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexps[expr] = re
+	return re, nil
+}
+
+func fullPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// pattern returns the compiled expression, compiling it when the rule was
+// not compiled beforehand.
+func (r Rule) pattern(expr string) *regexp.Regexp {
+	if re, ok := r.regexps[expr]; ok {
+		return re
+	}
+	re, _ := regexp.Compile(expr)
+	return re
+}
+
+func (r Rule) fullMatch(pattern, s string) bool {
+	re := r.pattern(fullPattern(pattern))
+	return re != nil && re.MatchString(s)
+}
+
+func (r Rule) partialMatch(pattern, s string) bool {
+	re := r.pattern(pattern)
+	return re != nil && re.MatchString(s)
+}
+
+// ruleBinding is what a rule matched, used to fill in its templates.
+type ruleBinding struct {
+	container *ContainerInfo
+	event     *EventInfo
+	log       string
+}
+
+// Evaluate returns a DebugHelper for each way the rule matches the input.
+func (r Rule) Evaluate(in DiagnosticInput) []DebugHelper {
+	if r.Disabled || in.Pod == nil || !r.matchPod(in.Pod) {
+		return nil
+	}
+
+	var log string
+	if r.Match.Log != "" {
+		re := r.pattern(r.Match.Log)
+		if re == nil {
+			return nil
+		}
+		for _, l := range in.Logs {
+			if re.MatchString(l.Content) {
+				log = l.Content
+				break
+			}
+		}
+		if log == "" {
+			return nil
+		}
+	}
+
+	containers := []*ContainerInfo{nil}
+	if r.Match.Container != nil {
+		containers = nil
+		for i := range in.Pod.Containers {
+			if r.containerMatches(&in.Pod.Containers[i]) {
+				containers = append(containers, &in.Pod.Containers[i])
+			}
+		}
+	}
+
+	events := []*EventInfo{nil}
+	if r.Match.Event != nil {
+		events = nil
+		for i := range in.Events {
+			if r.eventMatches(&in.Events[i]) {
+				events = append(events, &in.Events[i])
+			}
+		}
+	}
+
+	var helpers []DebugHelper
+	for _, c := range containers {
+		for _, e := range events {
+			helpers = append(helpers, r.helper(ruleBinding{container: c, event: e, log: log}, in.Pod))
+		}
+	}
+	return helpers
+}
+
+func (r Rule) matchPod(pod *PodInfo) bool {
+	m := r.Match
+	if len(m.Status) > 0 && !containsFold(m.Status, pod.Status) {
+		return false
+	}
+	if pod.Restarts < m.MinRestarts {
+		return false
+	}
+	if m.Node != "" && !r.fullMatch(m.Node, pod.Node) {
+		return false
+	}
+	if !r.matchMap(m.Labels, pod.Labels) || !r.matchMap(m.Annotations, pod.Annotations) || !r.matchMap(m.NodeSelector, pod.NodeSelector) {
+		return false
+	}
+	for _, k := range m.MissingLabels {
+		if _, ok := pod.Labels[k]; ok {
+			return false
+		}
+	}
+	for _, k := range m.MissingAnnotations {
+		if _, ok := pod.Annotations[k]; ok {
+			return false
+		}
+	}
+	if m.MissingContainer != "" {
+		for _, c := range pod.Containers {
+			if c.Name == m.MissingContainer {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r Rule) containerMatches(ci *ContainerInfo) bool {
+	c := r.Match.Container
+	if c.Name != "" && !r.fullMatch(c.Name, ci.Name) {
+		return false
+	}
+	if c.Image != "" && !r.partialMatch(c.Image, ci.Image) {
+		return false
+	}
+	if len(c.State) > 0 && !containsFold(c.State, ci.State) {
+		return false
+	}
+	if len(c.Reason) > 0 && !containsFold(c.Reason, ci.Reason) {
+		return false
+	}
+	if ci.RestartCount < c.MinRestarts {
+		return false
+	}
+	if c.NoMemoryLimit && !unsetQuantity(ci.Resources.MemoryLimit) {
+		return false
+	}
+	if c.NoCPULimit && !unsetQuantity(ci.Resources.CPULimit) {
+		return false
+	}
+	if len(c.ExitCode) > 0 || len(c.TerminationReason) > 0 {
+		t := ci.RecentTermination(time.Now())
+		if t == nil {
+			return false
+		}
+		if len(c.ExitCode) > 0 && !containsInt32(c.ExitCode, t.ExitCode) {
+			return false
+		}
+		if len(c.TerminationReason) > 0 && !containsFold(c.TerminationReason, t.Reason) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Rule) eventMatches(ev *EventInfo) bool {
+	e := r.Match.Event
+	if e.Type != "" && !strings.EqualFold(e.Type, ev.Type) {
+		return false
+	}
+	if len(e.Reason) > 0 && !containsFold(e.Reason, ev.Reason) {
+		return false
+	}
+	if e.Message != "" && !r.partialMatch(e.Message, ev.Message) {
+		return false
+	}
+	return true
+}
+
+var templateFields = []string{"{container}", "{reason}", "{event}", "{log}", "{exit_code}", "{exit_meaning}", "{termination_message}"}
+
+func (r Rule) helper(b ruleBinding, pod *PodInfo) DebugHelper {
+	values := map[string]string{"{reason}": pod.Status, "{log}": b.log}
+	if c := b.container; c != nil {
+		values["{container}"] = c.Name
+		if c.Reason != "" {
+			values["{reason}"] = c.Reason
+		}
+		if t := c.LastTermination(); t != nil {
+			values["{exit_code}"] = fmt.Sprintf("%d", t.ExitCode)
+			values["{exit_meaning}"] = t.Meaning()
+			values["{termination_message}"] = t.Message
+		}
+	}
+	if b.event != nil {
+		values["{event}"] = b.event.Message
+	}
+
+	var replacements []string
+	for _, f := range templateFields {
+		replacements = append(replacements, f, values[f])
+	}
+	fill := strings.NewReplacer(replacements...).Replace
+
+	h := DebugHelper{
+		Rule:     r.Name,
+		Issue:    fill(r.Issue),
+		Severity: r.Severity,
+		Docs:     r.Docs,
+	}
+	for _, s := range r.Suggestions {
+		if referencesEmpty(s, values) {
+			continue
+		}
+		h.Suggestions = append(h.Suggestions, fill(s))
+	}
+	return h
+}
+
+func referencesEmpty(s string, values map[string]string) bool {
+	for _, f := range templateFields {
+		if strings.Contains(s, f) && values[f] == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt32(list []int32, v int32) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func unsetQuantity(q string) bool {
+	return q == "" || q == "0"
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMap requires every key of want in have, with a value fully matching
+// the pattern unless the pattern is empty.
+func (r Rule) matchMap(want, have map[string]string) bool {
+	for k, pattern := range want {
+		v, ok := have[k]
+		if !ok || (pattern != "" && !r.fullMatch(pattern, v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// RuleEngine evaluates rules in order.
+type RuleEngine struct {
+	rules []Rule
+}
+
+// NewRuleEngine compiles rules' regular expressions once for all
+// evaluations. Rules that fail to compile never match.
+func NewRuleEngine(rules []Rule) *RuleEngine {
+	compiled := make([]Rule, len(rules))
+	for i, r := range rules {
+		r.Compile()
+		compiled[i] = r
+	}
+	return &RuleEngine{rules: compiled}
+}
+
+// Rules returns the active rules in evaluation order.
+func (e *RuleEngine) Rules() []Rule {
+	return e.rules
+}
+
+// Analyze runs every rule against the input.
+func (e *RuleEngine) Analyze(in DiagnosticInput) []DebugHelper {
+	var helpers []DebugHelper
+	for _, r := range e.rules {
+		helpers = append(helpers, r.Evaluate(in)...)
+	}
+	return helpers
+}
+
+// MergeRules overlays custom rules on the base ones. A custom rule with the
+// name of a base rule replaces it in place, or removes it when disabled;
+// other custom rules are appended.
+func MergeRules(base, custom []Rule) []Rule {
+	merged := append([]Rule(nil), base...)
+	for _, c := range custom {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == c.Name {
+				merged[i] = c
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, c)
+		}
+	}
+
+	active := merged[:0]
+	for _, r := range merged {
+		if !r.Disabled {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// ruleFile is the format of a rules file: either a list of rules or an
+// object with a rules list.
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ParseRules parses and validates the rules of one file.
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, err
+		}
+	} else {
+		var f ruleFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, err
+		}
+		rules = f.Rules
+	}
+
+	for i := range rules {
+		if err := rules[i].Compile(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// LoadRules reads every *.json file in dir in name order. A missing
+// directory yields no rules. Files that fail to parse are skipped and
+// reported in the returned error, so one broken file does not drop the
+// others.
+func LoadRules(dir string) ([]Rule, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var rules []Rule
+	var errs []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		parsed, err := ParseRules(data)
+		if err != nil {
+			errs = append(errs, filepath.Base(path)+": "+err.Error())
+			continue
+		}
+		rules = append(rules, parsed...)
+	}
+
+	if len(errs) > 0 {
+		return rules, fmt.Errorf("invalid rules: %s", strings.Join(errs, "; "))
+	}
+	return rules, nil
+}
+
+// LoadRuleEngine builds an engine from the built-in rules extended by the
+// custom rules in dir. The engine is usable even when the error reports
+// broken rule files.
+func LoadRuleEngine(dir string) (*RuleEngine, error) {
+	custom, err := LoadRules(dir)
+	return NewRuleEngine(MergeRules(DefaultRules(), custom)), err
+}
+
+// DefaultRules are the built-in checks.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:     "crash-loop",
+			Issue:    "CrashLoopBackOff",
+			Severity: "High",
+			Match:    RuleMatch{Status: []string{"CrashLoopBackOff"}},
+			Suggestions: []string{
+				"Check container logs for crash reason",
+				"Verify resource limits aren't too restrictive",
+				"Check liveness probe configuration",
+				"Look for application startup errors",
+			},
+		},
+		{
+			Name:     "image-pull",
+			Issue:    "Image Pull Failed",
+			Severity: "High",
+			Match:    RuleMatch{Status: []string{"ImagePullBackOff", "ErrImagePull"}},
+			Suggestions: []string{
+				"Verify image name and tag are correct",
+				"Check image registry credentials",
+				"Ensure node has network access to registry",
+				"Verify image exists in the registry",
+			},
+		},
+		{
+			Name:     "pending",
+			Issue:    "Pod Pending",
+			Severity: "Medium",
+			Match:    RuleMatch{Status: []string{"Pending"}},
+			Suggestions: []string{
+				"Check scheduler events for scheduling failures",
+				"Verify node resources are available",
+				"Check node selectors and tolerations",
+				"Review resource requests against available capacity",
+			},
+		},
+		{
+			Name:     "oom-killed",
+			Issue:    "Out of Memory",
+			Severity: "High",
+			Match:    RuleMatch{Status: []string{"OOMKilled"}},
+			Suggestions: []string{
+				"Increase memory limits for the container",
+				"Check for memory leaks in application",
+				"Review memory usage patterns in metrics",
+				"Consider horizontal scaling instead",
+			},
+		},
+		{
+			Name:     "exit-oom-killed",
+			Issue:    "Container {container} was OOM killed (exit 137)",
+			Severity: "High",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{137}, TerminationReason: []string{"OOMKilled"}}},
+			Suggestions: []string{
+				"The container used more memory than its limit",
+				"Raise the memory limit or reduce the working set",
+				"Compare memory usage against the limit in the metrics panel",
+			},
+		},
+		{
+			Name:     "exit-sigkill",
+			Issue:    "Container {container} was killed with SIGKILL (exit 137)",
+			Severity: "High",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{137}, TerminationReason: []string{"Error"}}},
+			Suggestions: []string{
+				"Failing liveness probes end in SIGKILL; check Unhealthy events",
+				"The process may ignore SIGTERM until terminationGracePeriodSeconds runs out",
+				"Termination message: {termination_message}",
+			},
+		},
+		{
+			Name:     "exit-segfault",
+			Issue:    "Container {container} crashed with a segmentation fault (exit 139)",
+			Severity: "High",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{139}}},
+			Suggestions: []string{
+				"Look for a stack trace in the previous container logs",
+				"Check native libraries and that the image matches the node architecture",
+				"Termination message: {termination_message}",
+			},
+		},
+		{
+			Name:     "exit-sigterm",
+			Issue:    "Container {container} was stopped by SIGTERM (exit 143)",
+			Severity: "Medium",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{143}}},
+			Suggestions: []string{
+				"Expected during rollouts, evictions and scale-downs",
+				"If unexpected, check events for preemption, eviction or probe failures",
+				"Handle SIGTERM and exit 0 for a clean shutdown",
+			},
+		},
+		{
+			Name:     "exit-app-error",
+			Issue:    "Container {container} exited with an application error (exit 1)",
+			Severity: "High",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{1}}},
+			Suggestions: []string{
+				"Termination message: {termination_message}",
+				"Check the previous container logs for the error",
+				"Verify configuration, environment variables and mounted secrets",
+			},
+		},
+		{
+			Name:     "exit-bad-command",
+			Issue:    "Container {container} could not start its command (exit {exit_code}: {exit_meaning})",
+			Severity: "High",
+			Match:    RuleMatch{Container: &ContainerMatch{ExitCode: []int32{126, 127}}},
+			Suggestions: []string{
+				"Verify command and args in the pod spec",
+				"Check the binary exists in the image and is executable",
+				"Termination message: {termination_message}",
+			},
+		},
+		{
+			Name:     "no-memory-limit",
+			Issue:    "No memory limit on container {container}",
+			Severity: "Warning",
+			Match:    RuleMatch{Container: &ContainerMatch{NoMemoryLimit: true}},
+			Suggestions: []string{
+				"Set memory limits to prevent OOM issues",
+				"Memory limits help with resource planning",
+			},
+		},
+		{
+			Name:     "no-cpu-limit",
+			Issue:    "No CPU limit on container {container}",
+			Severity: "Info",
+			Match:    RuleMatch{Container: &ContainerMatch{NoCPULimit: true}},
+			Suggestions: []string{
+				"Consider setting CPU limits for predictable performance",
+			},
+		},
+		{
+			Name:     "failed-scheduling",
+			Issue:    "Scheduling Failed",
+			Severity: "High",
+			Match:    RuleMatch{Event: &EventMatch{Type: "Warning", Reason: []string{"FailedScheduling"}}},
+			Suggestions: []string{
+				"{event}",
+				"Check node resources and selectors",
+			},
+		},
+	}
+}
+
+var defaultEngine = NewRuleEngine(DefaultRules())
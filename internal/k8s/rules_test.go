@@ -0,0 +1,303 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func defaultRule(t *testing.T, name string) Rule {
+	t.Helper()
+	for _, r := range DefaultRules() {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no default rule %q", name)
+	return Rule{}
+}
+
+func issues(helpers []DebugHelper) []string {
+	var out []string
+	for _, h := range helpers {
+		out = append(out, h.Issue)
+	}
+	return out
+}
+
+func TestDefaultRulesAreValid(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range DefaultRules() {
+		if err := r.Validate(); err != nil {
+			t.Errorf("default rule invalid: %v", err)
+		}
+		if seen[r.Name] {
+			t.Errorf("duplicate default rule %q", r.Name)
+		}
+		seen[r.Name] = true
+	}
+}
+
+func TestDefaultRules(t *testing.T) {
+	limited := ResourceRequirements{CPULimit: "500m", MemoryLimit: "256Mi"}
+
+	tests := []struct {
+		rule   string
+		input  DiagnosticInput
+		issues []string
+	}{
+		{
+			rule:   "crash-loop",
+			input:  DiagnosticInput{Pod: &PodInfo{Status: "CrashLoopBackOff"}},
+			issues: []string{"CrashLoopBackOff"},
+		},
+		{
+			rule:  "crash-loop",
+			input: DiagnosticInput{Pod: &PodInfo{Status: "Running"}},
+		},
+		{
+			rule:   "image-pull",
+			input:  DiagnosticInput{Pod: &PodInfo{Status: "ErrImagePull"}},
+			issues: []string{"Image Pull Failed"},
+		},
+		{
+			rule:   "pending",
+			input:  DiagnosticInput{Pod: &PodInfo{Status: "Pending"}},
+			issues: []string{"Pod Pending"},
+		},
+		{
+			rule:   "oom-killed",
+			input:  DiagnosticInput{Pod: &PodInfo{Status: "OOMKilled"}},
+			issues: []string{"Out of Memory"},
+		},
+		{
+			rule: "no-memory-limit",
+			input: DiagnosticInput{Pod: &PodInfo{Containers: []ContainerInfo{
+				{Name: "app", Resources: ResourceRequirements{MemoryLimit: "0"}},
+				{Name: "proxy", Resources: limited},
+				{Name: "init"},
+			}}},
+			issues: []string{"No memory limit on container app", "No memory limit on container init"},
+		},
+		{
+			rule: "no-cpu-limit",
+			input: DiagnosticInput{Pod: &PodInfo{Containers: []ContainerInfo{
+				{Name: "app", Resources: limited},
+			}}},
+		},
+		{
+			rule: "failed-scheduling",
+			input: DiagnosticInput{
+				Pod: &PodInfo{Status: "Pending"},
+				Events: []EventInfo{
+					{Type: "Warning", Reason: "FailedScheduling", Message: "0/3 nodes are available"},
+					{Type: "Normal", Reason: "Scheduled"},
+				},
+			},
+			issues: []string{"Scheduling Failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got := issues(defaultRule(t, tt.rule).Evaluate(tt.input))
+			if strings.Join(got, "|") != strings.Join(tt.issues, "|") {
+				t.Errorf("Evaluate() issues = %v, want %v", got, tt.issues)
+			}
+		})
+	}
+}
+
+func TestRuleTemplates(t *testing.T) {
+	helpers := defaultRule(t, "failed-scheduling").Evaluate(DiagnosticInput{
+		Pod:    &PodInfo{},
+		Events: []EventInfo{{Type: "Warning", Reason: "FailedScheduling", Message: "0/3 nodes are available"}},
+	})
+	if len(helpers) != 1 || helpers[0].Suggestions[0] != "0/3 nodes are available" {
+		t.Fatalf("Evaluate() = %+v, want the event message as first suggestion", helpers)
+	}
+	if helpers[0].Rule != "failed-scheduling" {
+		t.Errorf("Rule = %q, want failed-scheduling", helpers[0].Rule)
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [
+		{
+			"name": "sidecar-missing",
+			"issue": "Istio sidecar not injected",
+			"severity": "High",
+			"docs": ["https://wiki.example.com/mesh"],
+			"match": {"labels": {"mesh": "enabled"}, "missing_container": "istio-proxy"}
+		},
+		{
+			"name": "vault-annotation",
+			"issue": "Missing Vault role annotation",
+			"severity": "Warning",
+			"match": {"annotations": {"vault.hashicorp.com/agent-inject": "true"}, "missing_annotations": ["vault.hashicorp.com/role"]}
+		},
+		{
+			"name": "wrong-pool",
+			"issue": "GPU workload on {reason} node pool",
+			"severity": "Medium",
+			"match": {"labels": {"gpu": ""}, "node": "general-.*"}
+		},
+		{
+			"name": "db-timeout",
+			"issue": "Database unreachable",
+			"severity": "High",
+			"suggestions": ["Last error: {log}"],
+			"match": {"log": "(?i):5432.*connection refused"}
+		},
+		{
+			"name": "probe-kills",
+			"issue": "{container} keeps restarting",
+			"severity": "Medium",
+			"match": {"container": {"image": "^registry.example.com/", "min_restarts": 3}, "event": {"reason": ["Unhealthy"], "message": "Liveness"}}
+		}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseRules() error = %v", err)
+	}
+	engine := NewRuleEngine(rules)
+	// Patterns are compiled once, not on every evaluation
+	for _, r := range engine.Rules() {
+		if len(r.regexps) == 0 {
+			t.Errorf("rule %s has no compiled patterns", r.Name)
+		}
+	}
+
+	pod := &PodInfo{
+		Status:      "Running",
+		Node:        "general-abc12",
+		Labels:      map[string]string{"mesh": "enabled", "gpu": "a100"},
+		Annotations: map[string]string{"vault.hashicorp.com/agent-inject": "true"},
+		Containers: []ContainerInfo{
+			{Name: "api", Image: "registry.example.com/api:1.2", RestartCount: 5},
+			{Name: "cache", Image: "redis:7", RestartCount: 5},
+		},
+	}
+	in := DiagnosticInput{
+		Pod:    pod,
+		Events: []EventInfo{{Type: "Warning", Reason: "Unhealthy", Message: "Liveness probe failed"}},
+		Logs: []LogLine{
+			{Content: "starting"},
+			{Content: "dial tcp 10.0.0.5:5432: Connection refused"},
+		},
+	}
+
+	helpers := engine.Analyze(in)
+	want := []string{
+		"Istio sidecar not injected",
+		"Missing Vault role annotation",
+		"GPU workload on Running node pool",
+		"Database unreachable",
+		"api keeps restarting",
+	}
+	if got := issues(helpers); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Analyze() issues = %v, want %v", got, want)
+	}
+	if len(helpers[0].Docs) != 1 {
+		t.Errorf("Docs = %v, want the rule's doc link", helpers[0].Docs)
+	}
+	if helpers[3].Suggestions[0] != "Last error: dial tcp 10.0.0.5:5432: Connection refused" {
+		t.Errorf("log template = %q", helpers[3].Suggestions[0])
+	}
+
+	// Satisfy every signature and nothing should fire
+	pod.Containers = append(pod.Containers, ContainerInfo{Name: "istio-proxy"})
+	pod.Annotations["vault.hashicorp.com/role"] = "api"
+	pod.Node = "gpu-xyz"
+	pod.Containers[0].RestartCount = 0
+	in.Logs = nil
+	if got := engine.Analyze(in); len(got) != 0 {
+		t.Errorf("Analyze() = %v, want no issues", issues(got))
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := map[string]string{
+		"no name":          `[{"issue": "x", "severity": "High"}]`,
+		"no issue":         `[{"name": "a", "severity": "High"}]`,
+		"unknown severity": `[{"name": "a", "issue": "x", "severity": "Critical"}]`,
+		"bad regexp":       `[{"name": "a", "issue": "x", "severity": "High", "match": {"log": "("}}]`,
+		"bad json":         `[{"name": }]`,
+	}
+	for name, data := range tests {
+		if _, err := ParseRules([]byte(data)); err == nil {
+			t.Errorf("%s: ParseRules() should fail", name)
+		}
+	}
+
+	// Disabling a rule only needs its name
+	if _, err := ParseRules([]byte(`[{"name": "no-cpu-limit", "disabled": true}]`)); err != nil {
+		t.Errorf("ParseRules() error = %v for a disabled rule", err)
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	base := DefaultRules()
+	custom := []Rule{
+		{Name: "no-cpu-limit", Disabled: true},
+		{Name: "pending", Issue: "Stuck", Severity: "High", Match: RuleMatch{Status: []string{"Pending"}}},
+		{Name: "extra", Issue: "Extra", Severity: "Info"},
+	}
+	merged := MergeRules(base, custom)
+
+	var want []string
+	for _, r := range base {
+		if r.Name != "no-cpu-limit" {
+			want = append(want, r.Name)
+		}
+	}
+	want = append(want, "extra")
+
+	var names []string
+	for _, r := range merged {
+		names = append(names, r.Name)
+		if r.Name == "pending" && r.Issue != "Stuck" {
+			t.Errorf("pending rule was not replaced: %+v", r)
+		}
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("MergeRules() = %v, want %v", names, want)
+	}
+	if base[1].Name != "image-pull" || len(base) != len(DefaultRules()) {
+		t.Error("MergeRules() must not modify the base rules")
+	}
+	for _, r := range base {
+		if r.Name == "pending" && r.Issue == "Stuck" {
+			t.Error("MergeRules() must not modify the base rules")
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.json"), []byte(`[{"name": "a", "issue": "A", "severity": "Info"}]`), 0644)
+	os.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"rules": [{"name": "b", "issue": "B", "severity": "Info"}]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`not json`), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`ignored`), 0644)
+
+	rules, err := LoadRules(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("LoadRules() error = %v, want it to name broken.json", err)
+	}
+	if len(rules) != 2 || rules[0].Name != "a" || rules[1].Name != "b" {
+		t.Errorf("LoadRules() = %+v, want rules a and b", rules)
+	}
+
+	if rules, err := LoadRules(filepath.Join(dir, "missing")); err != nil || len(rules) != 0 {
+		t.Errorf("LoadRules() on a missing dir = %v, %v", rules, err)
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank("High") < SeverityRank("medium") && SeverityRank("Medium") < SeverityRank("Info")) {
+		t.Error("severities are not ordered High < Medium < Info")
+	}
+	if SeverityRank("bogus") != len(Severities) {
+		t.Error("unknown severity should rank last")
+	}
+}
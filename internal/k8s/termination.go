@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TerminationInfo describes how a container run ended. Age is the time
+// since it finished.
+type TerminationInfo struct {
+	ExitCode   int32
+	Signal     int32
+	Reason     string
+	Message    string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Age        string
+}
+
+// Meaning explains the exit code.
+func (t TerminationInfo) Meaning() string {
+	return ExitCodeMeaning(t.ExitCode, t.Reason)
+}
+
+// Summary is a one-line description, e.g. "exit 137 (OOMKilled): OOM
+// killed, SIGKILL".
+func (t TerminationInfo) Summary() string {
+	s := fmt.Sprintf("exit %d", t.ExitCode)
+	if t.Reason != "" {
+		s += " (" + t.Reason + ")"
+	}
+	return s + ": " + t.Meaning()
+}
+
+// ExitCodeMeaning explains a container exit code. Codes above 128 mean the
+// process was killed by signal code-128; the kubelet reports OOM kills as
+// 137 with reason OOMKilled.
+func ExitCodeMeaning(code int32, reason string) string {
+	switch {
+	case code == 0:
+		return "completed successfully"
+	case code == 137 && reason == "OOMKilled":
+		return "OOM killed, SIGKILL"
+	case code == 137:
+		return "killed by SIGKILL"
+	case code == 139:
+		return "segmentation fault, SIGSEGV"
+	case code == 143:
+		return "terminated by SIGTERM"
+	case code == 126:
+		return "command cannot be executed"
+	case code == 127:
+		return "command not found"
+	case code > 128 && code < 160:
+		return fmt.Sprintf("killed by signal %d", code-128)
+	default:
+		return "application error"
+	}
+}
+
+// RecentTerminationWindow is how long after a container ended its exit
+// still counts as a problem once it is running and ready again.
+const RecentTerminationWindow = time.Hour
+
+// RecentTermination returns LastTermination when it is still relevant: the
+// container is terminated, not ready or crash looping, or it ended within
+// RecentTerminationWindow of now. A ready container that OOM'd weeks ago
+// is healthy.
+func (c ContainerInfo) RecentTermination(now time.Time) *TerminationInfo {
+	t := c.LastTermination()
+	if t == nil {
+		return nil
+	}
+	if c.Terminated != nil || !c.Ready || c.Reason == "CrashLoopBackOff" || now.Sub(t.FinishedAt) < RecentTerminationWindow {
+		return t
+	}
+	return nil
+}
+
+// LastTermination returns how the container last ended: its current state
+// when terminated, otherwise its previous run. Nil if it never terminated.
+func (c ContainerInfo) LastTermination() *TerminationInfo {
+	if c.Terminated != nil {
+		return c.Terminated
+	}
+	return c.LastTerminated
+}
+
+func terminationInfo(t *corev1.ContainerStateTerminated) *TerminationInfo {
+	if t == nil {
+		return nil
+	}
+	return &TerminationInfo{
+		ExitCode:   t.ExitCode,
+		Signal:     t.Signal,
+		Reason:     t.Reason,
+		Message:    strings.TrimSpace(t.Message),
+		StartedAt:  t.StartedAt.Time,
+		FinishedAt: t.FinishedAt.Time,
+		Age:        formatAge(t.FinishedAt.Time),
+	}
+}
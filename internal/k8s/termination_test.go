@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExitCodeMeaning(t *testing.T) {
+	tests := []struct {
+		code   int32
+		reason string
+		want   string
+	}{
+		{0, "Completed", "completed successfully"},
+		{1, "Error", "application error"},
+		{2, "Error", "application error"},
+		{126, "Error", "command cannot be executed"},
+		{127, "Error", "command not found"},
+		{137, "OOMKilled", "OOM killed, SIGKILL"},
+		{137, "Error", "killed by SIGKILL"},
+		{139, "Error", "segmentation fault, SIGSEGV"},
+		{143, "Error", "terminated by SIGTERM"},
+		{134, "Error", "killed by signal 6"},
+		{255, "Error", "application error"},
+	}
+	for _, tt := range tests {
+		if got := ExitCodeMeaning(tt.code, tt.reason); got != tt.want {
+			t.Errorf("ExitCodeMeaning(%d, %q) = %q, want %q", tt.code, tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestPodToPodInfoTermination(t *testing.T) {
+	finished := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 3,
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+				},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						ExitCode:   137,
+						Reason:     "OOMKilled",
+						Message:    "  heap exhausted\n",
+						FinishedAt: finished,
+					},
+				},
+			}},
+		},
+	}
+
+	c := podToPodInfo(pod).Containers[0]
+	if c.Terminated != nil {
+		t.Errorf("Terminated = %+v, want nil for a waiting container", c.Terminated)
+	}
+	last := c.LastTermination()
+	if last == nil || last != c.LastTerminated {
+		t.Fatalf("LastTermination() = %+v, want the previous run", last)
+	}
+	if last.ExitCode != 137 || last.Message != "heap exhausted" || last.Age != "5m" {
+		t.Errorf("LastTerminated = %+v", last)
+	}
+	if got := last.Summary(); got != "exit 137 (OOMKilled): OOM killed, SIGKILL" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestExitCodeRules(t *testing.T) {
+	exited := func(code int32, reason, message string) DiagnosticInput {
+		return DiagnosticInput{Pod: &PodInfo{Containers: []ContainerInfo{{
+			Name:           "app",
+			Resources:      ResourceRequirements{CPULimit: "1", MemoryLimit: "1Gi"},
+			LastTerminated: &TerminationInfo{ExitCode: code, Reason: reason, Message: message},
+		}}}}
+	}
+
+	tests := []struct {
+		name  string
+		input DiagnosticInput
+		rule  string
+		issue string
+	}{
+		{"oom", exited(137, "OOMKilled", ""), "exit-oom-killed", "Container app was OOM killed (exit 137)"},
+		{"sigkill", exited(137, "Error", ""), "exit-sigkill", "Container app was killed with SIGKILL (exit 137)"},
+		{"segfault", exited(139, "Error", ""), "exit-segfault", "Container app crashed with a segmentation fault (exit 139)"},
+		{"sigterm", exited(143, "Error", ""), "exit-sigterm", "Container app was stopped by SIGTERM (exit 143)"},
+		{"app error", exited(1, "Error", "config missing"), "exit-app-error", "Container app exited with an application error (exit 1)"},
+		{"not found", exited(127, "ContainerCannotRun", ""), "exit-bad-command", "Container app could not start its command (exit 127: command not found)"},
+		{"success", exited(0, "Completed", ""), "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []DebugHelper
+			for _, h := range AnalyzePodIssues(tt.input.Pod, nil) {
+				if strings.HasPrefix(h.Rule, "exit-") {
+					got = append(got, h)
+				}
+			}
+			if tt.rule == "" {
+				if len(got) != 0 {
+					t.Errorf("AnalyzePodIssues() = %v, want no exit code issues", issues(got))
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Rule != tt.rule || got[0].Issue != tt.issue {
+				t.Fatalf("AnalyzePodIssues() = %+v, want %s: %q", got, tt.rule, tt.issue)
+			}
+		})
+	}
+
+	helpers := defaultRule(t, "exit-app-error").Evaluate(exited(1, "Error", "config missing"))
+	if len(helpers) != 1 || helpers[0].Suggestions[0] != "Termination message: config missing" {
+		t.Errorf("exit-app-error suggestions = %v, want the termination message first", helpers)
+	}
+	helpers = defaultRule(t, "exit-app-error").Evaluate(exited(1, "Error", ""))
+	for _, s := range helpers[0].Suggestions {
+		if s == "Termination message: " {
+			t.Error("suggestion with an empty termination message should be left out")
+		}
+	}
+}
+
+func TestExitCodeRulesIgnoreOldTerminations(t *testing.T) {
+	exitIssues := func(c ContainerInfo) []string {
+		var got []string
+		for _, h := range AnalyzePodIssues(&PodInfo{Status: "Running", Containers: []ContainerInfo{c}}, nil) {
+			if strings.HasPrefix(h.Rule, "exit-") {
+				got = append(got, h.Rule)
+			}
+		}
+		return got
+	}
+	healthy := func(finished time.Time) ContainerInfo {
+		return ContainerInfo{
+			Name:           "app",
+			Ready:          true,
+			State:          "Running",
+			Resources:      ResourceRequirements{CPULimit: "1", MemoryLimit: "1Gi"},
+			LastTerminated: &TerminationInfo{ExitCode: 137, Reason: "OOMKilled", FinishedAt: finished},
+		}
+	}
+
+	if got := exitIssues(healthy(time.Now().Add(-21 * 24 * time.Hour))); len(got) != 0 {
+		t.Errorf("ready container that OOM'd weeks ago = %v, want no exit issues", got)
+	}
+	if got := exitIssues(healthy(time.Now().Add(-10 * time.Minute))); len(got) != 1 || got[0] != "exit-oom-killed" {
+		t.Errorf("ready container that OOM'd minutes ago = %v, want exit-oom-killed", got)
+	}
+
+	crashing := healthy(time.Now().Add(-21 * 24 * time.Hour))
+	crashing.Ready = false
+	crashing.State = "Waiting"
+	crashing.Reason = "CrashLoopBackOff"
+	if got := exitIssues(crashing); len(got) != 1 || got[0] != "exit-oom-killed" {
+		t.Errorf("crash looping container = %v, want exit-oom-killed", got)
+	}
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	metricsv "k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+// PodUsage is one row of the top view: a pod with its summed container
+// usage compared to its requests and limits.
+type PodUsage struct {
+	Pod        PodInfo
+	Usage      ContainerUsage
+	HasMetrics bool
+}
+
+type TopSortColumn int
+
+const (
+	SortByCPU TopSortColumn = iota
+	SortByCPURequest
+	SortByCPULimit
+	SortByMemory
+	SortByMemoryRequest
+	SortByMemoryLimit
+	SortByRestarts
+	SortByName
+)
+
+// TopSortColumns is the order the top view cycles through.
+var TopSortColumns = []TopSortColumn{
+	SortByCPU, SortByCPURequest, SortByCPULimit,
+	SortByMemory, SortByMemoryRequest, SortByMemoryLimit,
+	SortByRestarts, SortByName,
+}
+
+// GetPodUsages lists every pod in namespace (all namespaces when empty)
+// joined with metrics-server usage. Pods are still returned when metrics
+// are unavailable, with HasMetrics false; the raw metrics are returned too
+// so callers can record them.
+func GetPodUsages(ctx context.Context, clientset *kubernetes.Clientset, metricsClient *metricsv.Clientset, namespace string) ([]PodUsage, []PodMetrics, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metrics, _ := GetNamespaceMetrics(ctx, metricsClient, namespace)
+	byPod := make(map[string]*PodMetrics, len(metrics))
+	for i := range metrics {
+		byPod[metrics[i].Namespace+"/"+metrics[i].Name] = &metrics[i]
+	}
+
+	usages := make([]PodUsage, 0, len(pods.Items))
+	for i := range pods.Items {
+		info := podToPodInfo(&pods.Items[i])
+		pu := PodUsage{Pod: info}
+		if pm, ok := byPod[info.Namespace+"/"+info.Name]; ok {
+			if summary := CalculateResourceUsage(pm, &info); summary != nil {
+				pu.Usage = summary.Total
+				pu.HasMetrics = true
+			}
+		}
+		usages = append(usages, pu)
+	}
+	return usages, metrics, nil
+}
+
+// SortPodUsages sorts in place by column, largest first unless ascending.
+// Ties and pods without metrics fall back to namespace/name order.
+func SortPodUsages(usages []PodUsage, column TopSortColumn, ascending bool) {
+	value := func(u PodUsage) float64 {
+		switch column {
+		case SortByCPU:
+			return float64(u.Usage.CPUMilli)
+		case SortByCPURequest:
+			return u.Usage.CPURequestPercent
+		case SortByCPULimit:
+			return u.Usage.CPULimitPercent
+		case SortByMemory:
+			return float64(u.Usage.MemoryBytes)
+		case SortByMemoryRequest:
+			return u.Usage.MemRequestPercent
+		case SortByMemoryLimit:
+			return u.Usage.MemLimitPercent
+		case SortByRestarts:
+			return float64(u.Pod.Restarts)
+		}
+		return 0
+	}
+
+	byName := func(a, b PodUsage) bool {
+		if a.Pod.Namespace != b.Pod.Namespace {
+			return a.Pod.Namespace < b.Pod.Namespace
+		}
+		return a.Pod.Name < b.Pod.Name
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if column == SortByName {
+			if ascending {
+				return byName(a, b)
+			}
+			return byName(b, a)
+		}
+		va, vb := value(a), value(b)
+		if va == vb {
+			return byName(a, b)
+		}
+		if ascending {
+			return va < vb
+		}
+		return va > vb
+	})
+}
@@ -0,0 +1,55 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+)
+
+func podUsage(ns, name string, cpu, mem int64, cpuLimitPct float64, restarts int32) PodUsage {
+	return PodUsage{
+		Pod:        PodInfo{Name: name, Namespace: ns, Restarts: restarts},
+		Usage:      ContainerUsage{CPUMilli: cpu, MemoryBytes: mem, CPULimitPercent: cpuLimitPct},
+		HasMetrics: true,
+	}
+}
+
+func usageNames(usages []PodUsage) string {
+	var names []string
+	for _, u := range usages {
+		names = append(names, u.Pod.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSortPodUsages(t *testing.T) {
+	base := []PodUsage{
+		podUsage("default", "api", 200, 100*mi, 40, 0),
+		podUsage("default", "worker", 800, 50*mi, 80, 3),
+		podUsage("default", "cache", 200, 400*mi, 95, 1),
+		{Pod: PodInfo{Name: "pending", Namespace: "default"}},
+	}
+
+	tests := []struct {
+		name      string
+		column    TopSortColumn
+		ascending bool
+		expected  string
+	}{
+		{"cpu descending with name tie-break", SortByCPU, false, "worker,api,cache,pending"},
+		{"cpu ascending", SortByCPU, true, "pending,api,cache,worker"},
+		{"memory descending", SortByMemory, false, "cache,api,worker,pending"},
+		{"cpu percent of limit", SortByCPULimit, false, "cache,worker,api,pending"},
+		{"restarts", SortByRestarts, false, "worker,cache,api,pending"},
+		{"name ascending", SortByName, true, "api,cache,pending,worker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usages := append([]PodUsage(nil), base...)
+			SortPodUsages(usages, tt.column, tt.ascending)
+			if got := usageNames(usages); got != tt.expected {
+				t.Errorf("SortPodUsages() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
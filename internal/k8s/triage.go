@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// triageLookback is how far back Warning events count against a pod
+	triageLookback = time.Hour
+	// triageLogLines is the log tail read from unhealthy pods so log rules
+	// can fire; healthy pods are not read at all
+	triageLogLines = 50
+	// maxConcurrentTriage bounds the per-pod log reads of a scan
+	maxConcurrentTriage = 8
+)
+
+// PodTriage is one pod's result in a triage scan.
+type PodTriage struct {
+	Pod      PodInfo
+	Helpers  []DebugHelper // most severe first
+	Warnings []EventInfo   // recent Warning events about the pod, newest first
+}
+
+// WarningCount sums the occurrences of the pod's Warning events.
+func (t PodTriage) WarningCount() int32 {
+	var n int32
+	for _, e := range t.Warnings {
+		if e.Count > 1 {
+			n += e.Count
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// Severity is the worst signal found: the most severe significant rule
+// finding, Warning for warning events and Info for restarts alone.
+func (t PodTriage) Severity() string {
+	switch {
+	case t.significant():
+		return t.Helpers[0].Severity
+	case len(t.Warnings) > 0:
+		return "Warning"
+	case t.Pod.Restarts > 0:
+		return "Info"
+	}
+	return ""
+}
+
+// TopHint is a one-line description of what is most wrong with the pod.
+func (t PodTriage) TopHint() string {
+	switch {
+	case t.significant():
+		return t.Helpers[0].Issue
+	case len(t.Warnings) > 0:
+		return t.Warnings[0].Reason + ": " + t.Warnings[0].Message
+	case t.Pod.Restarts > 0:
+		return fmt.Sprintf("%d restarts", t.Pod.Restarts)
+	}
+	return ""
+}
+
+// significant reports whether the most severe finding is above Warning.
+// Lesser findings, such as a missing limit, are kept in Helpers but don't
+// list or rank a pod on their own.
+func (t PodTriage) significant() bool {
+	return len(t.Helpers) > 0 && SeverityRank(t.Helpers[0].Severity) < SeverityRank("Warning")
+}
+
+func (t PodTriage) broken() bool {
+	return t.Severity() != ""
+}
+
+// TriagePods analyzes every pod in namespace (all namespaces when empty)
+// with the rule engine, its recent Warning events and restart count, and
+// returns the broken ones ranked by RankTriage. Unhealthy pods get their
+// recent logs read concurrently so log rules apply too.
+func TriagePods(ctx context.Context, clientset *kubernetes.Clientset, namespace string, engine *RuleEngine) ([]PodTriage, error) {
+	if engine == nil {
+		engine = defaultEngine
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Missing events only weaken the ranking, so a failed list is not fatal
+	warnings, _ := GetRecentWarnings(ctx, clientset, namespace, triageLookback)
+	byPod := podWarnings(warnings)
+
+	results := make([]PodTriage, len(pods.Items))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentTriage)
+
+	for i := range pods.Items {
+		pod := podToPodInfo(&pods.Items[i])
+		results[i] = PodTriage{Pod: pod, Warnings: byPod[pod.Namespace+"/"+pod.Name]}
+		if !needsTriageLogs(pod) {
+			results[i].Helpers = engine.Analyze(DiagnosticInput{Pod: &results[i].Pod, Events: results[i].Warnings})
+			continue
+		}
+
+		wg.Add(1)
+		go func(t *PodTriage) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			logs, _ := GetAllContainerLogs(ctx, clientset, t.Pod.Namespace, t.Pod.Name, triageLogLines)
+			t.Helpers = engine.Analyze(DiagnosticInput{Pod: &t.Pod, Events: t.Warnings, Logs: logs})
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return RankTriage(results), nil
+}
+
+// podWarnings groups Warning events about pods by namespace/name.
+func podWarnings(events []EventInfo) map[string][]EventInfo {
+	byPod := map[string][]EventInfo{}
+	for _, e := range events {
+		if !IsWarningEvent(e) || e.Regarding.Kind != "Pod" {
+			continue
+		}
+		k := e.Regarding.Namespace + "/" + e.Regarding.Name
+		byPod[k] = append(byPod[k], e)
+	}
+	for _, evs := range byPod {
+		sortEventsByLastSeen(evs)
+	}
+	return byPod
+}
+
+// needsTriageLogs reports whether a pod looks unhealthy enough to read its
+// logs during a scan.
+func needsTriageLogs(pod PodInfo) bool {
+	if pod.Restarts > 0 {
+		return true
+	}
+	switch pod.Phase {
+	case corev1.PodSucceeded:
+		return false
+	case corev1.PodRunning:
+		for _, c := range pod.Containers {
+			if !c.Ready {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// RankTriage drops pods with nothing worth looking at and sorts the rest
+// worst first: by severity, then warning count, restarts and name. Each
+// pod's helpers are sorted most severe first.
+func RankTriage(results []PodTriage) []PodTriage {
+	var ranked []PodTriage
+	for _, t := range results {
+		sort.SliceStable(t.Helpers, func(i, j int) bool {
+			return SeverityRank(t.Helpers[i].Severity) < SeverityRank(t.Helpers[j].Severity)
+		})
+		if t.broken() {
+			ranked = append(ranked, t)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if ra, rb := SeverityRank(a.Severity()), SeverityRank(b.Severity()); ra != rb {
+			return ra < rb
+		}
+		if wa, wb := a.WarningCount(), b.WarningCount(); wa != wb {
+			return wa > wb
+		}
+		if a.Pod.Restarts != b.Pod.Restarts {
+			return a.Pod.Restarts > b.Pod.Restarts
+		}
+		if a.Pod.Namespace != b.Pod.Namespace {
+			return a.Pod.Namespace < b.Pod.Namespace
+		}
+		return a.Pod.Name < b.Pod.Name
+	})
+	return ranked
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRankTriage(t *testing.T) {
+	now := time.Now()
+	warning := func(reason string, count int32, ago time.Duration) EventInfo {
+		return EventInfo{Type: "Warning", Reason: reason, Message: reason + " happened", Count: count, LastSeen: now.Add(-ago)}
+	}
+
+	results := []PodTriage{
+		{Pod: PodInfo{Namespace: "a", Name: "healthy"}},
+		{
+			Pod:     PodInfo{Namespace: "a", Name: "unlimited"},
+			Helpers: []DebugHelper{{Issue: "No CPU limit", Severity: "Info"}, {Issue: "No memory limit", Severity: "Warning"}},
+		},
+		{Pod: PodInfo{Namespace: "a", Name: "restarted", Restarts: 2}},
+		{Pod: PodInfo{Namespace: "a", Name: "probed"}, Warnings: []EventInfo{warning("Unhealthy", 12, time.Minute)}},
+		{Pod: PodInfo{Namespace: "a", Name: "evicted"}, Warnings: []EventInfo{warning("Evicted", 1, time.Minute)}},
+		{
+			Pod:     PodInfo{Namespace: "b", Name: "pending"},
+			Helpers: []DebugHelper{{Issue: "Pod Pending", Severity: "Medium"}},
+		},
+		{
+			Pod: PodInfo{Namespace: "a", Name: "crashing", Restarts: 9},
+			Helpers: []DebugHelper{
+				{Issue: "No memory limit", Severity: "Warning"},
+				{Issue: "CrashLoopBackOff", Severity: "High"},
+			},
+			Warnings: []EventInfo{warning("BackOff", 30, time.Minute)},
+		},
+	}
+
+	ranked := RankTriage(results)
+	var names []string
+	for _, r := range ranked {
+		names = append(names, r.Pod.Name)
+	}
+	want := "crashing pending probed evicted restarted"
+	if strings.Join(names, " ") != want {
+		t.Fatalf("RankTriage() = %v, want %s", names, want)
+	}
+
+	hints := []string{"CrashLoopBackOff", "Pod Pending", "Unhealthy: Unhealthy happened", "Evicted: Evicted happened", "2 restarts"}
+	severities := []string{"High", "Medium", "Warning", "Warning", "Info"}
+	for i, r := range ranked {
+		if r.TopHint() != hints[i] {
+			t.Errorf("%s: TopHint() = %q, want %q", r.Pod.Name, r.TopHint(), hints[i])
+		}
+		if r.Severity() != severities[i] {
+			t.Errorf("%s: Severity() = %q, want %q", r.Pod.Name, r.Severity(), severities[i])
+		}
+	}
+	if ranked[0].Helpers[0].Severity != "High" {
+		t.Errorf("helpers should be sorted most severe first, got %+v", ranked[0].Helpers)
+	}
+}
+
+func TestPodWarnings(t *testing.T) {
+	now := time.Now()
+	pod := ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1"}
+	events := []EventInfo{
+		{Type: "Warning", Reason: "BackOff", Regarding: pod, LastSeen: now.Add(-time.Hour)},
+		{Type: "Normal", Reason: "Pulled", Regarding: pod, LastSeen: now},
+		{Type: "Warning", Reason: "Unhealthy", Regarding: pod, LastSeen: now},
+		{Type: "Warning", Reason: "FailedCreate", Regarding: ObjectReference{Kind: "ReplicaSet", Namespace: "shop", Name: "api"}},
+	}
+
+	byPod := podWarnings(events)
+	if len(byPod) != 1 {
+		t.Fatalf("podWarnings() = %v, want only the pod", byPod)
+	}
+	got := byPod["shop/api-1"]
+	if len(got) != 2 || got[0].Reason != "Unhealthy" || got[1].Reason != "BackOff" {
+		t.Errorf("podWarnings() = %+v, want Unhealthy then BackOff", got)
+	}
+}
+
+func TestNeedsTriageLogs(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  PodInfo
+		want bool
+	}{
+		{"running and ready", PodInfo{Phase: corev1.PodRunning, Containers: []ContainerInfo{{Ready: true}}}, false},
+		{"running not ready", PodInfo{Phase: corev1.PodRunning, Containers: []ContainerInfo{{Ready: true}, {Ready: false}}}, true},
+		{"restarted", PodInfo{Phase: corev1.PodRunning, Restarts: 1, Containers: []ContainerInfo{{Ready: true}}}, true},
+		{"completed", PodInfo{Phase: corev1.PodSucceeded}, false},
+		{"pending", PodInfo{Phase: corev1.PodPending}, true},
+		{"failed", PodInfo{Phase: corev1.PodFailed}, true},
+	}
+	for _, tt := range tests {
+		if got := needsTriageLogs(tt.pod); got != tt.want {
+			t.Errorf("%s: needsTriageLogs() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -63,98 +63,14 @@ func FormatLabels(labels map[string]string) string {
 }
 
 type DebugHelper struct {
+	Rule        string
 	Issue       string
 	Severity    string
 	Suggestions []string
+	Docs        []string
 }
 
+// AnalyzePodIssues runs the built-in rules against a pod and its events.
 func AnalyzePodIssues(pod *PodInfo, events []EventInfo) []DebugHelper {
-	var helpers []DebugHelper
-
-	switch pod.Status {
-	case "CrashLoopBackOff":
-		helpers = append(helpers, DebugHelper{
-			Issue:    "CrashLoopBackOff",
-			Severity: "High",
-			Suggestions: []string{
-				"Check container logs for crash reason",
-				"Verify resource limits aren't too restrictive",
-				"Check liveness probe configuration",
-				"Look for application startup errors",
-			},
-		})
-
-	case "ImagePullBackOff", "ErrImagePull":
-		helpers = append(helpers, DebugHelper{
-			Issue:    "Image Pull Failed",
-			Severity: "High",
-			Suggestions: []string{
-				"Verify image name and tag are correct",
-				"Check image registry credentials",
-				"Ensure node has network access to registry",
-				"Verify image exists in the registry",
-			},
-		})
-
-	case "Pending":
-		helpers = append(helpers, DebugHelper{
-			Issue:    "Pod Pending",
-			Severity: "Medium",
-			Suggestions: []string{
-				"Check scheduler events for scheduling failures",
-				"Verify node resources are available",
-				"Check node selectors and tolerations",
-				"Review resource requests against available capacity",
-			},
-		})
-
-	case "OOMKilled":
-		helpers = append(helpers, DebugHelper{
-			Issue:    "Out of Memory",
-			Severity: "High",
-			Suggestions: []string{
-				"Increase memory limits for the container",
-				"Check for memory leaks in application",
-				"Review memory usage patterns in metrics",
-				"Consider horizontal scaling instead",
-			},
-		})
-	}
-
-	for _, c := range pod.Containers {
-		if c.Resources.MemoryLimit == "0" || c.Resources.MemoryLimit == "" {
-			helpers = append(helpers, DebugHelper{
-				Issue:    fmt.Sprintf("No memory limit on container %s", c.Name),
-				Severity: "Warning",
-				Suggestions: []string{
-					"Set memory limits to prevent OOM issues",
-					"Memory limits help with resource planning",
-				},
-			})
-		}
-		if c.Resources.CPULimit == "0" || c.Resources.CPULimit == "" {
-			helpers = append(helpers, DebugHelper{
-				Issue:    fmt.Sprintf("No CPU limit on container %s", c.Name),
-				Severity: "Info",
-				Suggestions: []string{
-					"Consider setting CPU limits for predictable performance",
-				},
-			})
-		}
-	}
-
-	for _, e := range events {
-		if e.Type == "Warning" && e.Reason == "FailedScheduling" {
-			helpers = append(helpers, DebugHelper{
-				Issue:    "Scheduling Failed",
-				Severity: "High",
-				Suggestions: []string{
-					e.Message,
-					"Check node resources and selectors",
-				},
-			})
-		}
-	}
-
-	return helpers
+	return defaultEngine.Analyze(DiagnosticInput{Pod: pod, Events: events})
 }
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"sort"
+	"time"
+)
+
+// WarningGroup collapses Warning events that share a reason and involved
+// object into a single feed entry.
+type WarningGroup struct {
+	Reason    string
+	Object    ObjectReference
+	Count     int32 // occurrences summed over every event in the group
+	Events    int
+	Message   string // message of the most recent event
+	Source    string
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+func (g WarningGroup) Age() string {
+	return formatAge(g.LastSeen)
+}
+
+func (g WarningGroup) key() string {
+	return warningKey(g.Reason, g.Object)
+}
+
+func warningKey(reason string, obj ObjectReference) string {
+	return reason + "|" + obj.Kind + "|" + obj.Namespace + "|" + obj.Name
+}
+
+// GroupWarnings groups events by reason and involved object, most recently
+// seen group first. Non-warning events are ignored.
+func GroupWarnings(events []EventInfo) []WarningGroup {
+	index := map[string]int{}
+	var groups []WarningGroup
+
+	for _, e := range events {
+		if !IsWarningEvent(e) {
+			continue
+		}
+		count := e.Count
+		if count < 1 {
+			count = 1
+		}
+
+		k := warningKey(e.Reason, e.Regarding)
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(groups)
+			groups = append(groups, WarningGroup{
+				Reason:    e.Reason,
+				Object:    e.Regarding,
+				Count:     count,
+				Events:    1,
+				Message:   e.Message,
+				Source:    e.Source,
+				FirstSeen: e.FirstSeen,
+				LastSeen:  e.LastSeen,
+			})
+			continue
+		}
+
+		g := &groups[i]
+		g.Count += count
+		g.Events++
+		if e.LastSeen.After(g.LastSeen) {
+			g.LastSeen = e.LastSeen
+			g.Message = e.Message
+			g.Source = e.Source
+		}
+		if !e.FirstSeen.IsZero() && (g.FirstSeen.IsZero() || e.FirstSeen.Before(g.FirstSeen)) {
+			g.FirstSeen = e.FirstSeen
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if !groups[i].LastSeen.Equal(groups[j].LastSeen) {
+			return groups[i].LastSeen.After(groups[j].LastSeen)
+		}
+		return groups[i].key() < groups[j].key()
+	})
+	return groups
+}
+
+// WarningFilter narrows the warnings feed. Empty fields match everything.
+type WarningFilter struct {
+	Reason string
+	Kind   string
+	MaxAge time.Duration
+}
+
+// FilterWarnings returns the events matching f, judging age against now.
+func FilterWarnings(events []EventInfo, f WarningFilter, now time.Time) []EventInfo {
+	var filtered []EventInfo
+	for _, e := range events {
+		if !IsWarningEvent(e) {
+			continue
+		}
+		if f.Reason != "" && e.Reason != f.Reason {
+			continue
+		}
+		if f.Kind != "" && e.Regarding.Kind != f.Kind {
+			continue
+		}
+		if f.MaxAge > 0 && now.Sub(e.LastSeen) > f.MaxAge {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// WarningReasons and WarningKinds list the distinct values present in
+// events, sorted, for building filter choices.
+func WarningReasons(events []EventInfo) []string {
+	return distinctWarningValues(events, func(e EventInfo) string { return e.Reason })
+}
+
+func WarningKinds(events []EventInfo) []string {
+	return distinctWarningValues(events, func(e EventInfo) string { return e.Regarding.Kind })
+}
+
+func distinctWarningValues(events []EventInfo, value func(EventInfo) string) []string {
+	return distinctEventValues(events, func(e EventInfo) string {
+		if !IsWarningEvent(e) {
+			return ""
+		}
+		return value(e)
+	})
+}
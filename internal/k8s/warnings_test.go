@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func warningEvent(uid, reason, kind, name string, count int32, lastSeen time.Time) EventInfo {
+	return EventInfo{
+		UID:       uid,
+		Type:      "Warning",
+		Reason:    reason,
+		Message:   reason + " on " + name,
+		Count:     count,
+		FirstSeen: lastSeen.Add(-time.Minute),
+		LastSeen:  lastSeen,
+		Regarding: ObjectReference{Kind: kind, Namespace: "default", Name: name},
+	}
+}
+
+func TestGroupWarnings(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		warningEvent("1", "BackOff", "Pod", "api-1", 3, now.Add(-10*time.Minute)),
+		warningEvent("2", "BackOff", "Pod", "api-1", 2, now.Add(-1*time.Minute)),
+		warningEvent("3", "FailedMount", "Pod", "api-1", 0, now.Add(-5*time.Minute)),
+		warningEvent("4", "BackOff", "Pod", "api-2", 1, now.Add(-20*time.Minute)),
+		{UID: "5", Type: "Normal", Reason: "Pulled", LastSeen: now},
+	}
+
+	groups := GroupWarnings(events)
+	if len(groups) != 3 {
+		t.Fatalf("GroupWarnings() returned %d groups, want 3", len(groups))
+	}
+
+	first := groups[0]
+	if first.Reason != "BackOff" || first.Object.Name != "api-1" {
+		t.Errorf("first group = %s %s, want BackOff api-1", first.Reason, first.Object)
+	}
+	if first.Count != 5 || first.Events != 2 {
+		t.Errorf("first group count = %d events = %d, want 5 and 2", first.Count, first.Events)
+	}
+	if !first.LastSeen.Equal(now.Add(-1 * time.Minute)) {
+		t.Errorf("first group LastSeen = %v, want newest event", first.LastSeen)
+	}
+	if !first.FirstSeen.Equal(now.Add(-11 * time.Minute)) {
+		t.Errorf("first group FirstSeen = %v, want oldest event", first.FirstSeen)
+	}
+
+	if groups[1].Reason != "FailedMount" || groups[1].Count != 1 {
+		t.Errorf("second group = %s x%d, want FailedMount x1", groups[1].Reason, groups[1].Count)
+	}
+	if groups[2].Object.Name != "api-2" {
+		t.Errorf("third group object = %s, want api-2", groups[2].Object.Name)
+	}
+}
+
+func TestFilterWarnings(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		warningEvent("1", "BackOff", "Pod", "api-1", 1, now.Add(-2*time.Minute)),
+		warningEvent("2", "FailedCreate", "ReplicaSet", "api-7d9", 1, now.Add(-30*time.Minute)),
+		warningEvent("3", "BackOff", "Pod", "api-2", 1, now.Add(-3*time.Hour)),
+		{UID: "4", Type: "Normal", Reason: "BackOff", LastSeen: now},
+	}
+
+	tests := []struct {
+		name     string
+		filter   WarningFilter
+		expected []string
+	}{
+		{"no filter", WarningFilter{}, []string{"1", "2", "3"}},
+		{"by reason", WarningFilter{Reason: "BackOff"}, []string{"1", "3"}},
+		{"by kind", WarningFilter{Kind: "ReplicaSet"}, []string{"2"}},
+		{"by age", WarningFilter{MaxAge: time.Hour}, []string{"1", "2"}},
+		{"combined", WarningFilter{Reason: "BackOff", MaxAge: time.Hour}, []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uids []string
+			for _, e := range FilterWarnings(events, tt.filter, now) {
+				uids = append(uids, e.UID)
+			}
+			if !reflect.DeepEqual(uids, tt.expected) {
+				t.Errorf("FilterWarnings() = %v, want %v", uids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWarningReasonsAndKinds(t *testing.T) {
+	now := time.Now()
+	events := []EventInfo{
+		warningEvent("1", "FailedMount", "Pod", "a", 1, now),
+		warningEvent("2", "BackOff", "Pod", "b", 1, now),
+		warningEvent("3", "BackOff", "Node", "n", 1, now),
+		{UID: "4", Type: "Normal", Reason: "Scheduled", LastSeen: now},
+	}
+
+	if got := WarningReasons(events); !reflect.DeepEqual(got, []string{"BackOff", "FailedMount"}) {
+		t.Errorf("WarningReasons() = %v", got)
+	}
+	if got := WarningKinds(events); !reflect.DeepEqual(got, []string{"Node", "Pod"}) {
+		t.Errorf("WarningKinds() = %v", got)
+	}
+}
@@ -0,0 +1,409 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadCondition is a status condition of a workload.
+type WorkloadCondition struct {
+	Type               string
+	Status             string
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// WorkloadHealth is the rollout state a controller reports for its
+// workload. Which counters are meaningful depends on the kind.
+type WorkloadHealth struct {
+	Generation         int64
+	ObservedGeneration int64
+	Conditions         []WorkloadCondition
+	Paused             bool
+
+	Desired     int32
+	Updated     int32
+	Ready       int32
+	Available   int32
+	Unavailable int32
+
+	// StatefulSet
+	CurrentRevision     string
+	UpdateRevision      string
+	PodManagementPolicy string
+
+	// DaemonSet
+	Misscheduled int32
+
+	// Job
+	Active       int32
+	Failed       int32
+	BackoffLimit int32
+}
+
+// Condition returns the condition of the given type, or nil.
+func (h *WorkloadHealth) Condition(conditionType string) *WorkloadCondition {
+	if h == nil {
+		return nil
+	}
+	for i := range h.Conditions {
+		if h.Conditions[i].Type == conditionType {
+			return &h.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// Stale reports whether the controller has not yet seen the latest spec.
+func (h *WorkloadHealth) Stale() bool {
+	return h != nil && h.ObservedGeneration < h.Generation
+}
+
+func deploymentHealth(d *appsv1.Deployment) *WorkloadHealth {
+	h := &WorkloadHealth{
+		Generation:         d.Generation,
+		ObservedGeneration: d.Status.ObservedGeneration,
+		Paused:             d.Spec.Paused,
+		Desired:            1,
+		Updated:            d.Status.UpdatedReplicas,
+		Ready:              d.Status.ReadyReplicas,
+		Available:          d.Status.AvailableReplicas,
+		Unavailable:        d.Status.UnavailableReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		h.Desired = *d.Spec.Replicas
+	}
+	for _, c := range d.Status.Conditions {
+		h.Conditions = append(h.Conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return h
+}
+
+func statefulSetHealth(s *appsv1.StatefulSet) *WorkloadHealth {
+	h := &WorkloadHealth{
+		Generation:          s.Generation,
+		ObservedGeneration:  s.Status.ObservedGeneration,
+		Desired:             1,
+		Updated:             s.Status.UpdatedReplicas,
+		Ready:               s.Status.ReadyReplicas,
+		Available:           s.Status.AvailableReplicas,
+		CurrentRevision:     s.Status.CurrentRevision,
+		UpdateRevision:      s.Status.UpdateRevision,
+		PodManagementPolicy: string(s.Spec.PodManagementPolicy),
+	}
+	if s.Spec.Replicas != nil {
+		h.Desired = *s.Spec.Replicas
+	}
+	if h.PodManagementPolicy == "" {
+		h.PodManagementPolicy = string(appsv1.OrderedReadyPodManagement)
+	}
+	for _, c := range s.Status.Conditions {
+		h.Conditions = append(h.Conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return h
+}
+
+func daemonSetHealth(d *appsv1.DaemonSet) *WorkloadHealth {
+	h := &WorkloadHealth{
+		Generation:         d.Generation,
+		ObservedGeneration: d.Status.ObservedGeneration,
+		Desired:            d.Status.DesiredNumberScheduled,
+		Updated:            d.Status.UpdatedNumberScheduled,
+		Ready:              d.Status.NumberReady,
+		Available:          d.Status.NumberAvailable,
+		Unavailable:        d.Status.NumberUnavailable,
+		Misscheduled:       d.Status.NumberMisscheduled,
+	}
+	for _, c := range d.Status.Conditions {
+		h.Conditions = append(h.Conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return h
+}
+
+func jobHealth(j *batchv1.Job) *WorkloadHealth {
+	h := &WorkloadHealth{
+		Generation:         j.Generation,
+		ObservedGeneration: j.Generation,
+		Paused:             j.Spec.Suspend != nil && *j.Spec.Suspend,
+		Desired:            1,
+		Ready:              j.Status.Succeeded,
+		Active:             j.Status.Active,
+		Failed:             j.Status.Failed,
+		BackoffLimit:       6,
+	}
+	if j.Spec.Completions != nil {
+		h.Desired = *j.Spec.Completions
+	}
+	if j.Spec.BackoffLimit != nil {
+		h.BackoffLimit = *j.Spec.BackoffLimit
+	}
+	for _, c := range j.Status.Conditions {
+		h.Conditions = append(h.Conditions, WorkloadCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return h
+}
+
+// AnalyzeWorkload reads a workload's conditions and counters for stuck or
+// failing rollouts. Hints are sorted most severe first.
+func AnalyzeWorkload(w *WorkloadInfo) []DebugHelper {
+	if w == nil || w.Health == nil {
+		return nil
+	}
+
+	var helpers []DebugHelper
+	switch w.Type {
+	case ResourceDeployments:
+		helpers = analyzeDeployment(w.Health)
+	case ResourceStatefulSets:
+		helpers = analyzeStatefulSet(w.Name, w.Health)
+	case ResourceDaemonSets:
+		helpers = analyzeDaemonSet(w.Health)
+	case ResourceJobs:
+		helpers = analyzeJob(w.Health)
+	}
+
+	if w.Health.Stale() && w.Type != ResourceJobs {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "generation-not-observed",
+			Issue:    fmt.Sprintf("Controller has not observed generation %d yet (at %d)", w.Health.Generation, w.Health.ObservedGeneration),
+			Severity: "Info",
+			Suggestions: []string{
+				"The status below may describe the previous spec",
+				"If this persists, check that the controller manager is healthy",
+			},
+		})
+	}
+
+	sort.SliceStable(helpers, func(i, j int) bool {
+		return SeverityRank(helpers[i].Severity) < SeverityRank(helpers[j].Severity)
+	})
+	return helpers
+}
+
+func conditionSuggestion(c *WorkloadCondition) string {
+	if c.Message != "" {
+		return c.Message
+	}
+	return c.Reason
+}
+
+func analyzeDeployment(h *WorkloadHealth) []DebugHelper {
+	var helpers []DebugHelper
+
+	if c := h.Condition(string(appsv1.DeploymentProgressing)); c != nil && c.Reason == "ProgressDeadlineExceeded" {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "progress-deadline-exceeded",
+			Issue:    fmt.Sprintf("Rollout stuck: progress deadline exceeded (%d/%d updated)", h.Updated, h.Desired),
+			Severity: "High",
+			Suggestions: []string{
+				conditionSuggestion(c),
+				"Check the new pods for crash loops, image pulls or failing readiness probes",
+				"Roll back with kubectl rollout undo if the new version is broken",
+			},
+		})
+	}
+	if c := h.Condition(string(appsv1.DeploymentReplicaFailure)); c != nil && c.Status == string(corev1.ConditionTrue) {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "replica-failure",
+			Issue:    "Pods cannot be created: " + c.Reason,
+			Severity: "High",
+			Suggestions: []string{
+				conditionSuggestion(c),
+				"Check resource quotas, limit ranges and admission webhooks",
+			},
+		})
+	}
+	if c := h.Condition(string(appsv1.DeploymentAvailable)); c != nil && c.Status == string(corev1.ConditionFalse) {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "deployment-unavailable",
+			Issue:    fmt.Sprintf("Below minimum availability (%d/%d available)", h.Available, h.Desired),
+			Severity: "High",
+			Suggestions: []string{
+				conditionSuggestion(c),
+				"Open the pods to see why they are not ready",
+			},
+		})
+	}
+	if h.Paused {
+		helpers = append(helpers, DebugHelper{
+			Rule:        "rollout-paused",
+			Issue:       "Rollout is paused",
+			Severity:    "Warning",
+			Suggestions: []string{"Resume with kubectl rollout resume"},
+		})
+	}
+	if len(helpers) == 0 && !h.Paused && h.Updated < h.Desired {
+		helpers = append(helpers, DebugHelper{
+			Rule:        "rollout-in-progress",
+			Issue:       fmt.Sprintf("Rollout in progress (%d/%d updated)", h.Updated, h.Desired),
+			Severity:    "Info",
+			Suggestions: []string{"Follow it with kubectl rollout status"},
+		})
+	}
+	return helpers
+}
+
+func analyzeStatefulSet(name string, h *WorkloadHealth) []DebugHelper {
+	var helpers []DebugHelper
+
+	rollingOut := h.UpdateRevision != "" && h.UpdateRevision != h.CurrentRevision
+	switch {
+	case rollingOut && h.Ready < h.Desired:
+		// Updates go from the highest ordinal down and wait for each pod to
+		// be Ready, so the last updated ordinal is the one holding it up
+		ordinal := h.Desired - h.Updated
+		if ordinal >= h.Desired {
+			ordinal = h.Desired - 1
+		}
+		helpers = append(helpers, DebugHelper{
+			Rule:     "statefulset-rollout-halted",
+			Issue:    fmt.Sprintf("Rollout halted on unready pod %s-%d (%d/%d updated, %d ready)", name, ordinal, h.Updated, h.Desired, h.Ready),
+			Severity: "High",
+			Suggestions: []string{
+				fmt.Sprintf("Open %s-%d and check why it is not Ready", name, ordinal),
+				"A rolling update does not continue past an unready pod, even after the spec is fixed the pod may need to be deleted",
+			},
+		})
+	case h.Ready < h.Desired && h.PodManagementPolicy == string(appsv1.OrderedReadyPodManagement):
+		helpers = append(helpers, DebugHelper{
+			Rule:     "statefulset-ordinal-unready",
+			Issue:    fmt.Sprintf("Pod %s-%d is not Ready, later ordinals wait for it (%d/%d ready)", name, h.Ready, h.Ready, h.Desired),
+			Severity: "High",
+			Suggestions: []string{
+				fmt.Sprintf("Open %s-%d and check why it is not Ready", name, h.Ready),
+				"With OrderedReady pod management pods start one at a time in ordinal order",
+			},
+		})
+	case h.Ready < h.Desired:
+		helpers = append(helpers, DebugHelper{
+			Rule:        "statefulset-unready",
+			Issue:       fmt.Sprintf("%d of %d pods not Ready", h.Desired-h.Ready, h.Desired),
+			Severity:    "Medium",
+			Suggestions: []string{"Open the pods to see why they are not ready"},
+		})
+	case rollingOut:
+		helpers = append(helpers, DebugHelper{
+			Rule:        "rollout-in-progress",
+			Issue:       fmt.Sprintf("Rollout in progress (%d/%d updated)", h.Updated, h.Desired),
+			Severity:    "Info",
+			Suggestions: []string{"Follow it with kubectl rollout status"},
+		})
+	}
+	return helpers
+}
+
+func analyzeDaemonSet(h *WorkloadHealth) []DebugHelper {
+	var helpers []DebugHelper
+
+	if h.Misscheduled > 0 {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "daemonset-misscheduled",
+			Issue:    fmt.Sprintf("%d pods running on nodes they should not run on", h.Misscheduled),
+			Severity: "Warning",
+			Suggestions: []string{
+				"Node labels or taints changed after the pods were scheduled",
+				"Check the nodeSelector, affinity and tolerations of the DaemonSet",
+			},
+		})
+	}
+	if h.Unavailable > 0 {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "daemonset-unavailable",
+			Issue:    fmt.Sprintf("%d of %d pods unavailable", h.Unavailable, h.Desired),
+			Severity: "Medium",
+			Suggestions: []string{
+				"Open the pods to see why they are not ready",
+				"A single bad node often shows up here first; check the node view",
+			},
+		})
+	}
+	if h.Updated < h.Desired {
+		helpers = append(helpers, DebugHelper{
+			Rule:        "rollout-in-progress",
+			Issue:       fmt.Sprintf("Rollout in progress (%d/%d updated)", h.Updated, h.Desired),
+			Severity:    "Info",
+			Suggestions: []string{"Follow it with kubectl rollout status"},
+		})
+	}
+	return helpers
+}
+
+func analyzeJob(h *WorkloadHealth) []DebugHelper {
+	var helpers []DebugHelper
+
+	if c := h.Condition(string(batchv1.JobFailed)); c != nil && c.Status == string(corev1.ConditionTrue) {
+		issue := "Job failed: " + c.Reason
+		var suggestions []string
+		switch c.Reason {
+		case "BackoffLimitExceeded":
+			issue = fmt.Sprintf("Job hit its backoffLimit (%d failed pods, limit %d)", h.Failed, h.BackoffLimit)
+			suggestions = []string{
+				"Check the logs and exit codes of the failed pods",
+				"Retry by deleting and recreating the Job once the cause is fixed",
+			}
+		case "DeadlineExceeded":
+			issue = "Job exceeded activeDeadlineSeconds"
+			suggestions = []string{
+				"The job ran longer than allowed; check for slow or hanging work",
+				"Raise activeDeadlineSeconds if the runtime is expected",
+			}
+		}
+		helpers = append(helpers, DebugHelper{
+			Rule:        "job-failed",
+			Issue:       issue,
+			Severity:    "High",
+			Suggestions: append([]string{conditionSuggestion(c)}, suggestions...),
+		})
+		return helpers
+	}
+
+	if h.Failed > 0 {
+		helpers = append(helpers, DebugHelper{
+			Rule:     "job-retrying",
+			Issue:    fmt.Sprintf("%d pod failures so far (backoffLimit %d)", h.Failed, h.BackoffLimit),
+			Severity: "Warning",
+			Suggestions: []string{
+				"Check the logs and exit codes of the failed pods before the limit is reached",
+			},
+		})
+	}
+	if h.Paused {
+		helpers = append(helpers, DebugHelper{
+			Rule:        "job-suspended",
+			Issue:       "Job is suspended",
+			Severity:    "Info",
+			Suggestions: []string{"Unsuspend by setting spec.suspend to false"},
+		})
+	}
+	return helpers
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func hintRules(helpers []DebugHelper) []string {
+	var rules []string
+	for _, h := range helpers {
+		rules = append(rules, h.Rule)
+	}
+	return rules
+}
+
+func TestAnalyzeDeployment(t *testing.T) {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Generation: 4},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 4,
+			Replicas:           4,
+			UpdatedReplicas:    1,
+			ReadyReplicas:      3,
+			AvailableReplicas:  3,
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+					Message: `ReplicaSet "api-7d9" has timed out progressing.`},
+			},
+		},
+	}
+
+	w := deploymentToWorkloadInfo(d)
+	hints := AnalyzeWorkload(&w)
+	if len(hints) != 1 || hints[0].Rule != "progress-deadline-exceeded" {
+		t.Fatalf("AnalyzeWorkload() = %v, want progress-deadline-exceeded", hintRules(hints))
+	}
+	if hints[0].Issue != "Rollout stuck: progress deadline exceeded (1/3 updated)" {
+		t.Errorf("Issue = %q", hints[0].Issue)
+	}
+	if hints[0].Suggestions[0] != `ReplicaSet "api-7d9" has timed out progressing.` {
+		t.Errorf("first suggestion should be the condition message, got %q", hints[0].Suggestions[0])
+	}
+
+	// A new spec the controller has not seen yet, still rolling
+	d.Generation = 5
+	d.Status.Conditions[1] = appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "ReplicaSetUpdated"}
+	w = deploymentToWorkloadInfo(d)
+	if got := strings.Join(hintRules(AnalyzeWorkload(&w)), ","); got != "rollout-in-progress,generation-not-observed" {
+		t.Errorf("AnalyzeWorkload() = %s, want rollout-in-progress,generation-not-observed", got)
+	}
+
+	// Fully rolled out
+	d.Status.ObservedGeneration = 5
+	d.Status.UpdatedReplicas = 3
+	w = deploymentToWorkloadInfo(d)
+	if hints := AnalyzeWorkload(&w); len(hints) != 0 {
+		t.Errorf("AnalyzeWorkload() = %v, want no hints for a healthy deployment", hintRules(hints))
+	}
+}
+
+func TestAnalyzeDeploymentReplicaFailure(t *testing.T) {
+	w := WorkloadInfo{Type: ResourceDeployments, Health: &WorkloadHealth{
+		Desired: 2,
+		Updated: 2,
+		Conditions: []WorkloadCondition{
+			{Type: "Available", Status: "False", Reason: "MinimumReplicasUnavailable"},
+			{Type: "ReplicaFailure", Status: "True", Reason: "FailedCreate", Message: "exceeded quota: compute"},
+		},
+	}}
+	hints := AnalyzeWorkload(&w)
+	if got := strings.Join(hintRules(hints), ","); got != "replica-failure,deployment-unavailable" {
+		t.Fatalf("AnalyzeWorkload() = %s", got)
+	}
+	if hints[0].Suggestions[0] != "exceeded quota: compute" {
+		t.Errorf("first suggestion = %q", hints[0].Suggestions[0])
+	}
+}
+
+func TestAnalyzeStatefulSet(t *testing.T) {
+	s := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: int32Ptr(3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:        3,
+			ReadyReplicas:   2,
+			UpdatedReplicas: 1,
+			CurrentRevision: "db-1",
+			UpdateRevision:  "db-2",
+		},
+	}
+
+	w := statefulSetToWorkloadInfo(s)
+	hints := AnalyzeWorkload(&w)
+	if len(hints) != 1 || hints[0].Rule != "statefulset-rollout-halted" {
+		t.Fatalf("AnalyzeWorkload() = %v", hintRules(hints))
+	}
+	if !strings.Contains(hints[0].Issue, "db-2") {
+		t.Errorf("Issue = %q, want the last updated ordinal db-2", hints[0].Issue)
+	}
+
+	// Not rolling out, ordinal 1 never became ready
+	s.Status.UpdateRevision = "db-1"
+	s.Status.ReadyReplicas = 1
+	w = statefulSetToWorkloadInfo(s)
+	hints = AnalyzeWorkload(&w)
+	if len(hints) != 1 || hints[0].Rule != "statefulset-ordinal-unready" || !strings.Contains(hints[0].Issue, "db-1 ") {
+		t.Errorf("AnalyzeWorkload() = %+v, want db-1 blocking later ordinals", hints)
+	}
+
+	s.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+	w = statefulSetToWorkloadInfo(s)
+	if hints := AnalyzeWorkload(&w); len(hints) != 1 || hints[0].Rule != "statefulset-unready" {
+		t.Errorf("AnalyzeWorkload() = %v, want statefulset-unready for parallel pods", hintRules(hints))
+	}
+}
+
+func TestAnalyzeDaemonSet(t *testing.T) {
+	d := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent"},
+		Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{}},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 5,
+			UpdatedNumberScheduled: 5,
+			NumberReady:            4,
+			NumberAvailable:        4,
+			NumberUnavailable:      1,
+			NumberMisscheduled:     2,
+		},
+	}
+
+	w := daemonSetToWorkloadInfo(d)
+	hints := AnalyzeWorkload(&w)
+	// Sorted most severe first
+	if got := strings.Join(hintRules(hints), ","); got != "daemonset-unavailable,daemonset-misscheduled" {
+		t.Errorf("AnalyzeWorkload() = %s", got)
+	}
+}
+
+func TestAnalyzeJob(t *testing.T) {
+	j := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+		Spec: batchv1.JobSpec{
+			Completions:  int32Ptr(1),
+			BackoffLimit: int32Ptr(3),
+			Selector:     &metav1.LabelSelector{},
+		},
+		Status: batchv1.JobStatus{Failed: 2},
+	}
+
+	w := jobToWorkloadInfo(j)
+	if hints := AnalyzeWorkload(&w); len(hints) != 1 || hints[0].Rule != "job-retrying" {
+		t.Errorf("AnalyzeWorkload() = %v, want job-retrying", hintRules(hints))
+	}
+
+	j.Status.Failed = 4
+	j.Status.Conditions = []batchv1.JobCondition{
+		{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+	}
+	w = jobToWorkloadInfo(j)
+	hints := AnalyzeWorkload(&w)
+	if len(hints) != 1 || hints[0].Issue != "Job hit its backoffLimit (4 failed pods, limit 3)" {
+		t.Errorf("AnalyzeWorkload() = %+v", hints)
+	}
+}
+
+func TestAnalyzeWorkloadWithoutHealth(t *testing.T) {
+	if hints := AnalyzeWorkload(&WorkloadInfo{Type: ResourcePods}); hints != nil {
+		t.Errorf("AnalyzeWorkload() = %v, want nil without health", hints)
+	}
+	if hints := AnalyzeWorkload(nil); hints != nil {
+		t.Errorf("AnalyzeWorkload(nil) = %v", hints)
+	}
+}
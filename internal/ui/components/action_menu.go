@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
 	"github.com/doganarif/k9sight/internal/ui/styles"
 )
 
@@ -231,6 +232,7 @@ type PodActionItem struct {
 	Description string
 	Action      string // "delete", "exec", "port-forward", "copy"
 	Command     string // kubectl command if applicable
+	Target      string // object the action is on, when not the pod
 }
 
 // PodActionMenuResult is returned when a pod action is selected
@@ -391,7 +393,7 @@ func (m PodActionMenu) IsVisible() bool {
 type WorkloadActionItem struct {
 	Label       string
 	Description string
-	Action      string // "scale", "restart", "copy"
+	Action      string // "scale", "restart", "copy", "cordon", "uncordon", "drain"
 	Replicas    int32  // For scale actions
 	Command     string // kubectl command
 }
@@ -549,6 +551,43 @@ func ScaleActions(namespace, name, resourceType string, currentReplicas int32) [
 	return items
 }
 
+// NodeActions returns cordon/uncordon and drain for a node, each with a
+// copyable kubectl equivalent
+func NodeActions(node *k8s.NodeInfo) []WorkloadActionItem {
+	var items []WorkloadActionItem
+	if node.Unschedulable {
+		items = append(items, WorkloadActionItem{
+			Label:       "Uncordon",
+			Description: "(allow new pods)",
+			Action:      "uncordon",
+		})
+	} else {
+		items = append(items, WorkloadActionItem{
+			Label:       "Cordon",
+			Description: "(stop scheduling new pods)",
+			Action:      "cordon",
+		})
+	}
+	items = append(items,
+		WorkloadActionItem{
+			Label:       "Drain",
+			Description: "(cordon and evict pods, skips DaemonSets)",
+			Action:      "drain",
+		},
+		WorkloadActionItem{
+			Label:   "Copy cordon/uncordon command",
+			Action:  "copy",
+			Command: k8s.CordonCommand(node.Name, !node.Unschedulable),
+		},
+		WorkloadActionItem{
+			Label:   "Copy drain command",
+			Action:  "copy",
+			Command: k8s.DrainCommand(node.Name),
+		},
+	)
+	return items
+}
+
 // PodActions returns the available actions for a pod
 func PodActions(namespace, podName string, containers []string) []PodActionItem {
 	items := []PodActionItem{
@@ -602,6 +641,14 @@ func PodActions(namespace, podName string, containers []string) []PodActionItem
 		Command:     fmt.Sprintf("kubectl describe pod -n %s %s", namespace, podName),
 	})
 
+	// Edit - opens $EDITOR, then shows a dry-run diff to confirm
+	items = append(items, PodActionItem{
+		Label:       "Edit Pod",
+		Description: "in $EDITOR, diff before apply",
+		Action:      "edit",
+		Command:     fmt.Sprintf("kubectl edit pod -n %s %s", namespace, podName),
+	})
+
 	// Copy commands section
 	items = append(items, PodActionItem{
 		Label:       "Copy logs command",
@@ -612,3 +659,64 @@ func PodActions(namespace, podName string, containers []string) []PodActionItem
 
 	return items
 }
+
+// ConfigDataActions opens the ConfigMaps and Secrets a pod mounts or
+// takes environment from.
+func ConfigDataActions(namespace string, related *k8s.RelatedResources) []PodActionItem {
+	if related == nil {
+		return nil
+	}
+	var items []PodActionItem
+	seen := map[string]bool{}
+	add := func(kind, name, description string) {
+		if seen[kind+"/"+name] {
+			return
+		}
+		seen[kind+"/"+name] = true
+		items = append(items, PodActionItem{
+			Label:       fmt.Sprintf("View %s '%s'", kind, name),
+			Description: description,
+			Action:      "view-" + strings.ToLower(kind),
+			Command:     fmt.Sprintf("kubectl get %s -n %s %s -o yaml", strings.ToLower(kind), namespace, name),
+			Target:      name,
+		})
+	}
+	for _, name := range related.ConfigMaps {
+		add("ConfigMap", name, "keys and values")
+	}
+	for _, name := range related.Secrets {
+		add("Secret", name, "masked, reveal per key")
+	}
+	return items
+}
+
+// RightSizingActions returns copy and apply actions for resource
+// recommendations on the workload that owns a pod.
+func RightSizingActions(namespace string, rt k8s.ResourceType, name string, recs []k8s.Recommendation) []PodActionItem {
+	var items []PodActionItem
+	for _, rec := range recs {
+		items = append(items, PodActionItem{
+			Label:       fmt.Sprintf("Copy set resources for '%s'", rec.Container),
+			Description: "to clipboard",
+			Action:      "copy",
+			Command:     k8s.SetResourcesCommand(rt, namespace, name, rec),
+		})
+	}
+
+	if patchCmd, err := k8s.PatchCommand(rt, namespace, name, recs); err == nil {
+		items = append(items, PodActionItem{
+			Label:       "Copy patch command",
+			Description: "all containers, to clipboard",
+			Action:      "copy",
+			Command:     patchCmd,
+		})
+	}
+
+	items = append(items, PodActionItem{
+		Label:       fmt.Sprintf("Apply to %s/%s", strings.ToLower(rt.Kind()), name),
+		Description: "(requires confirmation)",
+		Action:      "apply-resources",
+	})
+
+	return items
+}
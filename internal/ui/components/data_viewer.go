@@ -0,0 +1,298 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// SecretAccessMsg tells the app that secret values were revealed or
+// copied, for the status history.
+type SecretAccessMsg struct {
+	// Action is "revealed" or "copied"
+	Action string
+	// What was accessed, e.g. "secret shop/db key password"
+	What string
+}
+
+// certificateWarning is how close to expiry a certificate is flagged.
+const certificateWarning = 30 * 24 * time.Hour
+
+// DataViewer shows the keys of a ConfigMap or Secret. ConfigMap values are
+// shown with syntax highlighting; Secret values are masked until revealed
+// key by key.
+type DataViewer struct {
+	data     *k8s.ConfigData
+	cursor   int
+	revealed map[string]bool
+	note     string
+	viewport viewport.Model
+	visible  bool
+	width    int
+	height   int
+}
+
+func NewDataViewer() DataViewer {
+	return DataViewer{}
+}
+
+func (v *DataViewer) Show(data *k8s.ConfigData, width, height int) {
+	v.data = data
+	v.cursor = 0
+	v.revealed = map[string]bool{}
+	v.note = ""
+	v.visible = true
+	v.SetSize(width, height)
+}
+
+func (v *DataViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.viewport = viewport.New(max(width-6, 20), max(height-6, 5))
+	v.render()
+}
+
+func (v *DataViewer) Hide() {
+	v.visible = false
+}
+
+func (v DataViewer) IsVisible() bool {
+	return v.visible
+}
+
+func (v DataViewer) secret() bool {
+	return v.data != nil && v.data.Ref.Kind == "Secret"
+}
+
+func (v DataViewer) Update(msg tea.Msg) (DataViewer, tea.Cmd) {
+	if !v.visible {
+		return v, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+
+	var cmd tea.Cmd
+	v.note = ""
+	switch keyMsg.String() {
+	case "esc", "q":
+		v.visible = false
+		return v, nil
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(v.data.Entries)-1 {
+			v.cursor++
+		}
+	case "g":
+		v.cursor = 0
+	case "G":
+		v.cursor = max(len(v.data.Entries)-1, 0)
+	case "enter", " ":
+		cmd = v.toggleReveal()
+	case "y":
+		cmd = v.copy()
+	case "pgup", "ctrl+u", "pgdown", "ctrl+d":
+		// Long values scroll without moving between keys
+		v.viewport, cmd = v.viewport.Update(msg)
+		return v, cmd
+	}
+	v.render()
+	return v, cmd
+}
+
+func (v *DataViewer) toggleReveal() tea.Cmd {
+	if !v.secret() || v.cursor >= len(v.data.Entries) {
+		return nil
+	}
+	key := v.data.Entries[v.cursor].Key
+	v.revealed[key] = !v.revealed[key]
+	if !v.revealed[key] {
+		return nil
+	}
+	what := v.keyName(key)
+	return func() tea.Msg {
+		return SecretAccessMsg{Action: "revealed", What: what}
+	}
+}
+
+func (v *DataViewer) copy() tea.Cmd {
+	if v.cursor >= len(v.data.Entries) {
+		return nil
+	}
+	entry := v.data.Entries[v.cursor]
+	if entry.Format == k8s.DataBinary {
+		v.note = "Binary values cannot be copied"
+		return nil
+	}
+	if err := CopyToClipboard(entry.Value); err != nil {
+		v.note = "Copy failed: " + err.Error()
+		return nil
+	}
+	v.note = "Copied " + entry.Key
+	if !v.secret() {
+		return nil
+	}
+	what := v.keyName(entry.Key)
+	return func() tea.Msg {
+		return SecretAccessMsg{Action: "copied", What: what}
+	}
+}
+
+func (v DataViewer) keyName(key string) string {
+	ref := v.data.Ref
+	return fmt.Sprintf("%s %s/%s key %s", strings.ToLower(ref.Kind), ref.Namespace, ref.Name, key)
+}
+
+// render rebuilds the content, keeping the key under the cursor visible.
+func (v *DataViewer) render() {
+	if v.data == nil {
+		return
+	}
+	var b strings.Builder
+	row := 0
+	lines := 0
+	write := func(s string) {
+		b.WriteString(s + "\n")
+		lines++
+	}
+
+	if len(v.data.Certificates) > 0 {
+		write(styles.SubtitleStyle.Render("Certificates"))
+		for _, c := range v.data.Certificates {
+			for _, l := range certificateLines(c, time.Now()) {
+				write(l)
+			}
+		}
+		write("")
+	}
+
+	if len(v.data.Entries) == 0 {
+		write(styles.StatusMuted.Render("No data"))
+	}
+	width := v.viewport.Width
+	for i, e := range v.data.Entries {
+		cursor := "  "
+		if i == v.cursor {
+			cursor = styles.CursorStyle.Render("> ")
+			row = lines
+		}
+		header := cursor + styles.SyntaxKey.Render(e.Key) +
+			styles.StatusMuted.Render(fmt.Sprintf("  %s, %d bytes", e.Format, e.Size))
+		hidden := v.secret() && !v.revealed[e.Key]
+		if hidden {
+			header += "  " + styles.StatusMuted.Render(secretMask)
+		}
+		write(header)
+
+		switch {
+		case hidden:
+		case e.Format == k8s.DataBinary:
+			write("    " + styles.StatusMuted.Render("binary, not shown"))
+		default:
+			for _, line := range strings.Split(strings.TrimRight(e.Value, "\n"), "\n") {
+				write("    " + highlightDataLine(styles.Truncate(line, width-4), e.Format))
+			}
+		}
+	}
+
+	v.viewport.SetContent(b.String())
+	if row < v.viewport.YOffset {
+		v.viewport.SetYOffset(row)
+	} else if row >= v.viewport.YOffset+v.viewport.Height {
+		v.viewport.SetYOffset(row - v.viewport.Height + 1)
+	}
+}
+
+func certificateLines(c k8s.CertificateInfo, now time.Time) []string {
+	expiry := styles.EventNormal
+	switch {
+	case now.After(c.NotAfter) || now.Before(c.NotBefore):
+		expiry = styles.StatusError
+	case c.NotAfter.Sub(now) < certificateWarning:
+		expiry = styles.EventWarning
+	}
+
+	title := "  " + styles.SyntaxKey.Render(c.Key) + "  " + c.Subject
+	if c.IsCA {
+		title += styles.StatusMuted.Render("  (CA)")
+	}
+	lines := []string{title, "    issuer:  " + c.Issuer}
+	if sans := append(append([]string{}, c.DNSNames...), c.IPs...); len(sans) > 0 {
+		lines = append(lines, "    SANs:    "+strings.Join(sans, ", "))
+	}
+	lines = append(lines, fmt.Sprintf("    valid:   %s to %s %s",
+		c.NotBefore.Format("2006-01-02"), c.NotAfter.Format("2006-01-02"),
+		expiry.Render("("+c.Expiry(now)+")")))
+	return lines
+}
+
+// highlightDataLine colors a line of a value in its detected syntax.
+func highlightDataLine(line string, format k8s.DataFormat) string {
+	switch format {
+	case k8s.DataJSON:
+		return highlightManifestLine(line, k8s.ManifestJSON)
+	case k8s.DataYAML:
+		return highlightManifestLine(line, k8s.ManifestYAML)
+	case k8s.DataProperties:
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!") {
+			return styles.StatusMuted.Render(line)
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			return styles.SyntaxKey.Render(key) + styles.SyntaxPunct.Render("=") + styles.SyntaxString.Render(value)
+		}
+	}
+	return line
+}
+
+func (v DataViewer) View() string {
+	if !v.visible || v.data == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(styles.Primary).
+		Background(styles.Surface).
+		Padding(0, 1).
+		Width(v.width - 4)
+	title := strings.ToLower(v.data.Ref.Kind) + "/" + v.data.Ref.Name
+	if v.data.Type != "" {
+		title += " (" + v.data.Type + ")"
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+	b.WriteString(v.viewport.View())
+	b.WriteString("\n")
+
+	footerStyle := lipgloss.NewStyle().
+		Foreground(styles.Muted).
+		Background(styles.Surface).
+		Padding(0, 1).
+		Width(v.width - 4)
+	footer := "j/k key • y copy • q/esc close"
+	if v.secret() {
+		footer = "j/k key • enter reveal/hide • y copy • q/esc close"
+	}
+	if v.note != "" {
+		footer = lipgloss.NewStyle().Foreground(styles.Success).Bold(true).Render(v.note) + " • " + footer
+	}
+	b.WriteString(footerStyle.Render(footer))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.Primary).
+		Background(styles.Background)
+	return boxStyle.Render(b.String())
+}
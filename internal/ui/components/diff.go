@@ -0,0 +1,65 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// FormatDiff colors a unified diff: additions green, removals red and hunk
+// headers cyan.
+func FormatDiff(diff []k8s.DiffLine) string {
+	var b strings.Builder
+	for _, l := range diff {
+		switch l.Op {
+		case k8s.DiffAdded:
+			b.WriteString(styles.EventNormal.Render("+ " + l.Text))
+		case k8s.DiffRemoved:
+			b.WriteString(styles.LogError.Render("- " + l.Text))
+		case k8s.DiffHunk:
+			b.WriteString(styles.SyntaxKey.Render(l.Text))
+		default:
+			b.WriteString("  " + l.Text)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// FormatDrift lists the drifted fields of an object: ~ for a changed value
+// with both sides, + for what only the live object has and - for what it
+// lacks.
+func FormatDrift(report *k8s.DriftReport) string {
+	var b strings.Builder
+	b.WriteString(styles.StatusMuted.Render("Live object compared with " + report.Source))
+	b.WriteString("\n\n")
+	for _, f := range report.Fields {
+		switch f.Change {
+		case k8s.DriftAdded:
+			b.WriteString(styles.EventWarning.Render("+ "+f.Path) + "  " + driftValue(f.Live))
+		case k8s.DriftRemoved:
+			b.WriteString(styles.LogError.Render("- "+f.Path) + "  " + driftValue(f.Desired))
+		default:
+			b.WriteString(styles.SyntaxKey.Render("~ "+f.Path) + "\n")
+			b.WriteString("    desired: " + styles.EventNormal.Render(driftValue(f.Desired)) + "\n")
+			b.WriteString("    live:    " + styles.LogError.Render(driftValue(f.Live)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// driftValue prints scalars as they are and objects and lists as JSON.
+func driftValue(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(v)
+}
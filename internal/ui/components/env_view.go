@@ -0,0 +1,221 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// LoadEnvMsg asks the app to resolve the environment of a pod's
+// containers; the answer goes to ManifestPanel.SetEnv.
+type LoadEnvMsg struct {
+	Namespace string
+	Pod       string
+}
+
+// secretMask replaces Secret values until they are revealed. It has a
+// fixed length so it does not give away the value's.
+const secretMask = "••••••••"
+
+// envView is the environment mode of the manifest panel: what one
+// container receives, with problems that keep it from starting on top.
+type envView struct {
+	namespace string
+	pod       string
+	envs      []k8s.ContainerEnv
+	err       error
+	loading   bool
+
+	container int
+	reveal    bool
+}
+
+// open asks for the environment of pod, keeping the container shown when
+// it is the same pod.
+func (e *envView) open(pod *k8s.PodInfo) tea.Cmd {
+	if pod.Namespace != e.namespace || pod.Name != e.pod {
+		*e = envView{namespace: pod.Namespace, pod: pod.Name, container: -1}
+	}
+	e.err = nil
+	e.loading = true
+	ns, name := pod.Namespace, pod.Name
+	return func() tea.Msg {
+		return LoadEnvMsg{Namespace: ns, Pod: name}
+	}
+}
+
+func (e *envView) setEnv(namespace, pod string, envs []k8s.ContainerEnv, err error) {
+	if namespace != e.namespace || pod != e.pod {
+		return
+	}
+	e.loading = false
+	e.err = err
+	if err != nil {
+		return
+	}
+	e.envs = envs
+	if e.container < 0 || e.container >= len(envs) {
+		e.container = e.firstContainer()
+	}
+}
+
+// firstContainer is the first container with problems, or else the first
+// app container.
+func (e envView) firstContainer() int {
+	first := -1
+	for i, c := range e.envs {
+		if c.ProblemCount() > 0 {
+			return i
+		}
+		if first < 0 && !c.Init {
+			first = i
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	return first
+}
+
+func (e *envView) update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "c":
+		if len(e.envs) > 0 {
+			e.container = (e.container + 1) % len(e.envs)
+		}
+	case "s":
+		e.reveal = !e.reveal
+		names := e.secretNames()
+		if e.reveal && len(names) > 0 {
+			what := fmt.Sprintf("env of pod %s/%s: %s", e.namespace, e.pod, strings.Join(names, ", "))
+			return func() tea.Msg {
+				return SecretAccessMsg{Action: "revealed", What: what}
+			}
+		}
+	}
+	return nil
+}
+
+// secretNames lists the Secret-backed variables that revealing shows, e.g.
+// "DB_PASSWORD (secret/db:password)", with the container when the pod has
+// several.
+func (e envView) secretNames() []string {
+	var names []string
+	for _, c := range e.envs {
+		for _, v := range c.Vars {
+			if !v.Secret || v.Problem != "" || v.Note != "" {
+				continue
+			}
+			source := v.Source
+			if source == "" {
+				// A literal that expands a Secret-backed variable
+				source = "expands a secret"
+			}
+			name := v.Name + " (" + source + ")"
+			if len(e.envs) > 1 {
+				name = c.Container + "/" + name
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (e envView) header() string {
+	var b strings.Builder
+	if e.container >= 0 && e.container < len(e.envs) {
+		c := e.envs[e.container]
+		b.WriteString(" " + c.Container)
+		if c.Init {
+			b.WriteString(styles.SubtitleStyle.Render(" init"))
+		}
+		if len(e.envs) > 1 {
+			b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf(" %d/%d", e.container+1, len(e.envs))))
+		}
+		if n := c.ProblemCount(); n > 0 {
+			b.WriteString(styles.StatusError.Render(fmt.Sprintf(" %d problems", n)))
+		}
+	}
+	if e.reveal {
+		b.WriteString(styles.SubtitleStyle.Render(" +secrets"))
+	}
+	b.WriteString(styles.HelpDescStyle.Render(" (c:container s:secrets)"))
+	return b.String()
+}
+
+func (e envView) content(width int) string {
+	switch {
+	case e.loading && e.envs == nil:
+		return styles.StatusMuted.Render("Resolving environment...")
+	case e.err != nil:
+		return styles.StatusError.Render("Could not resolve environment: " + e.err.Error())
+	case e.container < 0 || e.container >= len(e.envs):
+		return styles.StatusMuted.Render("No containers")
+	}
+
+	c := e.envs[e.container]
+	var b strings.Builder
+
+	if c.ProblemCount() > 0 {
+		b.WriteString(styles.StatusError.Render("Keeping the container from starting (CreateContainerConfigError):"))
+		b.WriteString("\n")
+		for _, p := range c.Problems {
+			b.WriteString(styles.LogError.Render(styles.Truncate("  ✗ "+p, width)) + "\n")
+		}
+		for _, v := range c.Vars {
+			if v.Problem != "" {
+				b.WriteString(styles.LogError.Render(styles.Truncate("  ✗ "+v.Name+": "+v.Problem, width)) + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	for _, n := range c.Notes {
+		b.WriteString(styles.StatusMuted.Render(styles.Truncate("  "+n, width)) + "\n")
+	}
+
+	if len(c.Vars) == 0 {
+		b.WriteString(styles.StatusMuted.Render("No environment variables"))
+		return b.String()
+	}
+
+	nameWidth := 0
+	for _, v := range c.Vars {
+		if len(v.Name) > nameWidth {
+			nameWidth = len(v.Name)
+		}
+	}
+	if nameWidth > 32 {
+		nameWidth = 32
+	}
+
+	for _, v := range c.Vars {
+		name := styles.SyntaxKey.Render(styles.PadRight(styles.Truncate(v.Name, nameWidth), nameWidth))
+		room := width - nameWidth - len([]rune(v.Source)) - 5
+		if room < 10 {
+			room = 10
+		}
+
+		var value string
+		switch {
+		case v.Problem != "":
+			value = styles.LogError.Render(styles.Truncate("✗ "+v.Problem, room))
+		case v.Note != "":
+			value = styles.StatusMuted.Render(styles.Truncate(v.Note, room))
+		case v.Secret && !e.reveal:
+			value = styles.StatusMuted.Render(secretMask)
+		default:
+			// Multi-line values such as certificates stay on one row
+			value = styles.Truncate(strings.ReplaceAll(v.Value, "\n", `\n`), room)
+		}
+
+		b.WriteString(name + " = " + value)
+		if v.Source != "" {
+			b.WriteString("  " + styles.StatusMuted.Render(v.Source))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -4,24 +4,44 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/doganarif/k9sight/internal/k8s"
 	"github.com/doganarif/k9sight/internal/ui/styles"
 )
 
+// eventTypes are the type filter choices; empty means all types.
+var eventTypes = []string{"", "Warning", "Normal"}
+
+// ShowEventDetailMsg asks the parent view to show the full event.
+type ShowEventDetailMsg struct {
+	Event k8s.EventInfo
+}
+
 type EventsPanel struct {
-	events    []k8s.EventInfo
-	viewport  viewport.Model
-	ready     bool
-	width     int
-	height    int
-	cursor    int
-	showAll   bool
+	events      []k8s.EventInfo
+	viewport    viewport.Model
+	ready       bool
+	width       int
+	height      int
+	cursor      int
+	filter      k8s.EventFilter
+	grouped     bool // collapse identical reason+message entries
+	searching   bool // true when search input is active
+	searchInput textinput.Model
 }
 
 func NewEventsPanel() EventsPanel {
-	return EventsPanel{}
+	ti := textinput.New()
+	ti.Placeholder = "Search events..."
+	ti.CharLimit = 100
+	ti.Width = 30
+
+	return EventsPanel{
+		filter:      k8s.EventFilter{Type: "Warning"},
+		searchInput: ti,
+	}
 }
 
 func (e EventsPanel) Init() tea.Cmd {
@@ -33,17 +53,74 @@ func (e EventsPanel) Update(msg tea.Msg) (EventsPanel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Handle search mode
+		if e.searching {
+			switch msg.String() {
+			case "esc", "enter":
+				e.searching = false
+				e.searchInput.Blur()
+			default:
+				e.searchInput, cmd = e.searchInput.Update(msg)
+			}
+			// Live search as you type
+			e.filter.Query = e.searchInput.Value()
+			e.cursor = 0
+			e.updateContent()
+			return e, cmd
+		}
+
 		switch msg.String() {
+		case "/":
+			e.searching = true
+			e.searchInput.Focus()
+			return e, textinput.Blink
+		case "c":
+			e.filter = k8s.EventFilter{}
+			e.searchInput.SetValue("")
+			e.cursor = 0
+			e.updateContent()
+			return e, nil
 		case "w":
-			e.showAll = !e.showAll
+			if e.filter.Type == "Warning" {
+				e.filter.Type = ""
+			} else {
+				e.filter.Type = "Warning"
+			}
+			e.cursor = 0
+			e.updateContent()
+		case "t":
+			e.filter.Type = nextFilterValue(eventTypes[1:], e.filter.Type)
+			e.cursor = 0
+			e.updateContent()
+		case "o":
+			e.filter.Reason = nextFilterValue(k8s.EventReasons(e.events), e.filter.Reason)
+			e.cursor = 0
 			e.updateContent()
+		case "s":
+			e.filter.Source = nextFilterValue(k8s.EventSources(e.events), e.filter.Source)
+			e.cursor = 0
+			e.updateContent()
+		case "m":
+			e.grouped = !e.grouped
+			e.cursor = 0
+			e.updateContent()
+		case "enter":
+			if event := e.SelectedEvent(); event != nil {
+				selected := *event
+				return e, func() tea.Msg {
+					return ShowEventDetailMsg{Event: selected}
+				}
+			}
+			return e, nil
 		case "j", "down":
 			if e.cursor < len(e.getDisplayedEvents())-1 {
 				e.cursor++
+				e.updateContent()
 			}
 		case "k", "up":
 			if e.cursor > 0 {
 				e.cursor--
+				e.updateContent()
 			}
 		}
 	}
@@ -65,20 +142,70 @@ func (e EventsPanel) View() string {
 		header.WriteString(styles.EventWarning.Render(fmt.Sprintf(" [%d warnings]", warningCount)))
 	}
 
-	if !e.showAll {
+	if e.searching {
+		header.WriteString("\n")
+		header.WriteString(styles.SubtitleStyle.Render("/ "))
+		header.WriteString(e.searchInput.View())
+	} else if desc := e.filterDescription(); desc != "" {
+		header.WriteString(styles.SubtitleStyle.Render(" (" + desc + ")"))
+	} else if !e.grouped && e.filter.Type == "Warning" {
 		header.WriteString(styles.SubtitleStyle.Render(" (warnings only, press 'w' for all)"))
 	}
+	if e.grouped {
+		header.WriteString(styles.SubtitleStyle.Render(" [grouped]"))
+	}
 	header.WriteString("\n")
 
 	return header.String() + e.viewport.View()
 }
 
+// filterDescription summarizes active filters other than the default
+// warnings-only view, e.g. "Normal, reason BackOff, /pull".
+func (e EventsPanel) filterDescription() string {
+	var parts []string
+	if e.filter.Type != "" && (e.filter.Type != "Warning" || e.filter.Reason != "" || e.filter.Source != "" || e.filter.Query != "") {
+		parts = append(parts, e.filter.Type)
+	}
+	if e.filter.Reason != "" {
+		parts = append(parts, "reason "+e.filter.Reason)
+	}
+	if e.filter.Source != "" {
+		parts = append(parts, "source "+e.filter.Source)
+	}
+	if e.filter.Query != "" {
+		parts = append(parts, "/"+e.filter.Query)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ") + ", c to clear"
+}
+
+func (e *EventsPanel) SetShowAll(showAll bool) {
+	if showAll {
+		e.filter.Type = ""
+	} else {
+		e.filter.Type = "Warning"
+	}
+	e.updateContent()
+}
+
 func (e *EventsPanel) SetEvents(events []k8s.EventInfo) {
 	e.events = events
 	e.cursor = 0
 	e.updateContent()
 }
 
+// UpsertEvent merges a single event delivered by a watch, keeping the
+// cursor on the same position.
+func (e *EventsPanel) UpsertEvent(event k8s.EventInfo) {
+	e.events = k8s.MergeEvent(e.events, event)
+	if e.cursor >= len(e.getDisplayedEvents()) {
+		e.cursor = 0
+	}
+	e.updateContent()
+}
+
 func (e *EventsPanel) SetSize(width, height int) {
 	e.width = width
 	e.height = height - 2
@@ -103,7 +230,11 @@ func (e *EventsPanel) updateContent() {
 	events := e.getDisplayedEvents()
 
 	if len(events) == 0 {
-		content.WriteString(styles.StatusMuted.Render("No events found"))
+		if e.filter.Query != "" || e.filter.Reason != "" || e.filter.Source != "" {
+			content.WriteString(styles.StatusMuted.Render("No events match filter"))
+		} else {
+			content.WriteString(styles.StatusMuted.Render("No events found"))
+		}
 	} else {
 		for i, event := range events {
 			line := e.formatEvent(event, i == e.cursor)
@@ -116,17 +247,11 @@ func (e *EventsPanel) updateContent() {
 }
 
 func (e EventsPanel) getDisplayedEvents() []k8s.EventInfo {
-	if e.showAll {
-		return e.events
-	}
-
-	var warnings []k8s.EventInfo
-	for _, event := range e.events {
-		if event.Type == "Warning" {
-			warnings = append(warnings, event)
-		}
+	events := k8s.FilterEvents(e.events, e.filter)
+	if e.grouped {
+		return k8s.GroupEvents(events)
 	}
-	return warnings
+	return events
 }
 
 func (e EventsPanel) formatEvent(event k8s.EventInfo, selected bool) string {
@@ -152,7 +277,14 @@ func (e EventsPanel) formatEvent(event k8s.EventInfo, selected bool) string {
 	b.WriteString(styles.LogContainer.Render(fmt.Sprintf("%-20s", styles.Truncate(event.Reason, 20))))
 	b.WriteString(" ")
 
-	maxMsgLen := e.width - 40
+	count := ""
+	if event.Count > 1 {
+		count = fmt.Sprintf("x%d", event.Count)
+	}
+	b.WriteString(styles.LogTimestamp.Render(fmt.Sprintf("%-5s", count)))
+	b.WriteString(" ")
+
+	maxMsgLen := e.width - 46
 	if maxMsgLen < 20 {
 		maxMsgLen = 20
 	}
@@ -180,6 +312,10 @@ func (e EventsPanel) SelectedEvent() *k8s.EventInfo {
 	return nil
 }
 
+func (e EventsPanel) IsSearching() bool {
+	return e.searching
+}
+
 func (e EventsPanel) EventCount() int {
 	return len(e.events)
 }
@@ -187,3 +323,57 @@ func (e EventsPanel) EventCount() int {
 func (e EventsPanel) WarningCount() int {
 	return e.warningCount()
 }
+
+// FormatEventDetail renders every field of an event for the detail view,
+// with the message left untruncated.
+func FormatEventDetail(event k8s.EventInfo) string {
+	var b strings.Builder
+
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(styles.HelpKeyStyle.Render(styles.PadRight(label, 12)))
+		b.WriteString(" ")
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	field("Type", event.Type)
+	field("Reason", event.Reason)
+	field("Object", objectDetail(event.Regarding))
+	if event.Related != nil {
+		field("Related", objectDetail(*event.Related))
+	}
+	field("Action", event.Action)
+	field("Source", event.Source)
+	field("Reporter", event.ReportingInstance)
+	if event.Count > 1 {
+		field("Count", fmt.Sprintf("%d", event.Count))
+	}
+	if !event.FirstSeen.IsZero() {
+		field("First seen", event.FirstSeen.Local().Format("2006-01-02 15:04:05"))
+	}
+	if !event.LastSeen.IsZero() {
+		field("Last seen", event.LastSeen.Local().Format("2006-01-02 15:04:05")+" ("+event.Age+" ago)")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.PanelTitleStyle.Render("Message"))
+	b.WriteString("\n")
+	b.WriteString(event.Message)
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func objectDetail(ref k8s.ObjectReference) string {
+	s := ref.String()
+	if ref.Namespace != "" {
+		s = ref.Namespace + "/" + s
+	}
+	if ref.FieldPath != "" {
+		s += " (" + ref.FieldPath + ")"
+	}
+	return s
+}
@@ -43,15 +43,24 @@ func defaultHelpEntries() [][]HelpEntry {
 			{Key: "/", Desc: "search/filter"},
 			{Key: "c", Desc: "clear filter"},
 			{Key: "r", Desc: "refresh"},
+			{Key: "I", Desc: "status history"},
 		},
 		{
 			{Key: "n", Desc: "change namespace"},
 			{Key: "t", Desc: "change resource type"},
+			{Key: "E", Desc: "workload events"},
+			{Key: "W", Desc: "warnings feed"},
+			{Key: "T", Desc: "top pods by usage"},
+			{Key: "!", Desc: "triage: most broken pods"},
+			{Key: "a", Desc: "node actions (cordon/drain)"},
+			{Key: "e", Desc: "edit YAML, diff before apply"},
+			{Key: "D", Desc: "drift from manifest"},
 		},
 		{
 			{Key: "tab", Desc: "next panel"},
 			{Key: "S-tab", Desc: "prev panel"},
 			{Key: "1-4", Desc: "focus panel"},
+			{Key: "B", Desc: "support bundle"},
 		},
 		{
 			{Key: "f", Desc: "follow logs"},
@@ -59,6 +68,19 @@ func defaultHelpEntries() [][]HelpEntry {
 			{Key: "w", Desc: "wrap lines"},
 			{Key: "v", Desc: "fullscreen"},
 		},
+		{
+			{Key: "o/s/t", Desc: "filter events by reason/source/type"},
+			{Key: "m", Desc: "merge identical events"},
+			{Key: "w", Desc: "metrics window (prometheus)"},
+			{Key: "o", Desc: "right-size resources"},
+		},
+		{
+			{Key: "d", Desc: "manifest mode (env, raw YAML/JSON)"},
+			{Key: "c/s", Desc: "env: container/secrets"},
+			{Key: "o/f/s", Desc: "raw: object/format/status"},
+			{Key: "z/Z", Desc: "raw: fold block/all"},
+			{Key: "n/N", Desc: "raw: next/prev match"},
+		},
 		{
 			{Key: "?", Desc: "toggle help"},
 			{Key: "q", Desc: "quit"},
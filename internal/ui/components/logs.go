@@ -41,6 +41,8 @@ type LogsPanel struct {
 	containers   []string // list of container names
 	containerIdx int      // -1 = all, 0+ = specific container
 	showPrevious bool     // show previous container logs
+	showHistory  bool     // show logs from the historical log provider
+	hasHistory   bool     // a historical log provider is configured
 	searching    bool     // true when search input is active
 	searchInput  textinput.Model
 	timeFilter   TimeFilter
@@ -120,6 +122,11 @@ func (l LogsPanel) Update(msg tea.Msg) (LogsPanel, tea.Cmd) {
 		case "P":
 			l.showPrevious = !l.showPrevious
 			// Note: actual previous logs fetch handled by dashboard
+		case "H":
+			if l.hasHistory {
+				l.showHistory = !l.showHistory
+				// Note: actual history fetch handled by app
+			}
 		case "T":
 			l.cycleTimeFilter()
 			l.updateContent()
@@ -156,7 +163,10 @@ func (l LogsPanel) View() string {
 	if l.showPrevious {
 		header.WriteString(styles.EventWarning.Render(" [Previous]"))
 	}
-	if l.following && !l.showPrevious {
+	if l.showHistory {
+		header.WriteString(styles.EventWarning.Render(fmt.Sprintf(" [History %s]", formatWindow(l.HistoryWindow()))))
+	}
+	if l.following && !l.showPrevious && !l.showHistory {
 		header.WriteString(styles.StatusRunning.Render(" [Following]"))
 	}
 
@@ -232,6 +242,19 @@ func (l *LogsPanel) prevContainer() {
 	l.updateContent()
 }
 
+// SelectContainer shows only the named container's logs. It reports false
+// when the pod has no such container.
+func (l *LogsPanel) SelectContainer(name string) bool {
+	for i, c := range l.containers {
+		if c == name {
+			l.containerIdx = i
+			l.updateContent()
+			return true
+		}
+	}
+	return false
+}
+
 func (l LogsPanel) SelectedContainer() string {
 	if l.containerIdx >= 0 && l.containerIdx < len(l.containers) {
 		return l.containers[l.containerIdx]
@@ -243,6 +266,34 @@ func (l LogsPanel) ShowPrevious() bool {
 	return l.showPrevious
 }
 
+func (l LogsPanel) ShowHistory() bool {
+	return l.showHistory
+}
+
+// SetHistoryAvailable enables the history toggle when a log provider is configured.
+func (l *LogsPanel) SetHistoryAvailable(available bool) {
+	l.hasHistory = available
+	if !available {
+		l.showHistory = false
+	}
+}
+
+// HistoryWindow is how far back historical logs are queried. It follows the
+// time filter, defaulting to one hour when no filter is set.
+func (l LogsPanel) HistoryWindow() time.Duration {
+	if d := l.getTimeFilterDuration(); d > 0 {
+		return d
+	}
+	return time.Hour
+}
+
+func formatWindow(d time.Duration) string {
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
 func (l *LogsPanel) cycleTimeFilter() {
 	l.timeFilter = (l.timeFilter + 1) % 5
 }
@@ -345,6 +396,12 @@ func (l LogsPanel) formatLogLine(log k8s.LogLine) string {
 		b.WriteString(" ")
 	}
 
+	// Historical logs can come from several pods of the workload
+	if log.Pod != "" && l.showHistory {
+		b.WriteString(styles.LogContainer.Render(fmt.Sprintf("[%s]", log.Pod)))
+		b.WriteString(" ")
+	}
+
 	// Show container name when viewing all containers
 	if log.Container != "" && l.containerIdx == -1 && len(l.containers) > 1 {
 		b.WriteString(styles.LogContainer.Render(fmt.Sprintf("[%s]", log.Container)))
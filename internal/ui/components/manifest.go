@@ -16,12 +16,16 @@ const (
 	ManifestViewSummary ManifestViewMode = iota
 	ManifestViewDetails
 	ManifestViewResources
+	ManifestViewEnv
+	ManifestViewRaw
 )
 
 var manifestViewModeLabels = map[ManifestViewMode]string{
 	ManifestViewSummary:   "Summary",
 	ManifestViewDetails:   "Details",
 	ManifestViewResources: "Resources",
+	ManifestViewEnv:       "Env",
+	ManifestViewRaw:       "Raw",
 }
 
 type ManifestPanel struct {
@@ -33,10 +37,16 @@ type ManifestPanel struct {
 	width     int
 	height    int
 	viewMode  ManifestViewMode
+
+	// Resolved container environment shown in the env mode
+	env envView
+
+	// Live object shown in the raw mode, one of rawSources
+	raw rawManifest
 }
 
 func NewManifestPanel() ManifestPanel {
-	return ManifestPanel{}
+	return ManifestPanel{raw: newRawManifest()}
 }
 
 func (m ManifestPanel) Init() tea.Cmd {
@@ -48,11 +58,39 @@ func (m ManifestPanel) Update(msg tea.Msg) (ManifestPanel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.viewMode == ManifestViewRaw && m.raw.searching {
+			cmd = m.raw.update(msg, m.viewport.Height)
+			m.updateContent()
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "d":
-			m.viewMode = (m.viewMode + 1) % 3
+			m.viewMode = (m.viewMode + 1) % 5
+			if m.viewMode == ManifestViewEnv && m.pod != nil {
+				cmd = m.env.open(m.pod)
+			}
+			if m.viewMode == ManifestViewRaw && m.pod != nil {
+				cmd = m.raw.open(m.rawSources()[0])
+			}
 			m.updateContent()
-			return m, nil
+			return m, cmd
+		}
+
+		if m.viewMode == ManifestViewEnv && (msg.String() == "c" || msg.String() == "s") {
+			cmd = m.env.update(msg)
+			m.updateContent()
+			return m, cmd
+		}
+
+		if m.viewMode == ManifestViewRaw {
+			if msg.String() == "o" && m.pod != nil {
+				cmd = m.raw.open(m.nextRawSource())
+			} else {
+				cmd = m.raw.update(msg, m.viewport.Height/2)
+			}
+			m.updateContent()
+			return m, cmd
 		}
 	}
 
@@ -60,6 +98,37 @@ func (m ManifestPanel) Update(msg tea.Msg) (ManifestPanel, tea.Cmd) {
 	return m, cmd
 }
 
+// rawSources lists what the raw mode can show: the pod, the workload that
+// owns it and its related Services and ConfigMaps.
+func (m ManifestPanel) rawSources() []k8s.ObjectReference {
+	ns := m.pod.Namespace
+	sources := []k8s.ObjectReference{{Kind: "Pod", Namespace: ns, Name: m.pod.Name}}
+	if rt, name, ok := k8s.PodWorkload(m.pod); ok {
+		sources = append(sources, k8s.ObjectReference{Kind: rt.Kind(), Namespace: ns, Name: name})
+	} else if m.pod.OwnerKind != "" && m.pod.OwnerKind != "Node" {
+		sources = append(sources, k8s.ObjectReference{Kind: m.pod.OwnerKind, Namespace: ns, Name: m.pod.OwnerRef})
+	}
+	if m.related != nil {
+		for _, svc := range m.related.Services {
+			sources = append(sources, k8s.ObjectReference{Kind: "Service", Namespace: ns, Name: svc.Name})
+		}
+		for _, cm := range m.related.ConfigMaps {
+			sources = append(sources, k8s.ObjectReference{Kind: "ConfigMap", Namespace: ns, Name: cm})
+		}
+	}
+	return sources
+}
+
+func (m ManifestPanel) nextRawSource() k8s.ObjectReference {
+	sources := m.rawSources()
+	for i, ref := range sources {
+		if ref == m.raw.ref {
+			return sources[(i+1)%len(sources)]
+		}
+	}
+	return sources[0]
+}
+
 func (m ManifestPanel) View() string {
 	if !m.ready {
 		return styles.PanelStyle.Render("Loading manifest...")
@@ -68,17 +137,78 @@ func (m ManifestPanel) View() string {
 	var header strings.Builder
 	header.WriteString(styles.PanelTitleStyle.Render("Pod Details"))
 	header.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf(" [%s]", manifestViewModeLabels[m.viewMode])))
-	header.WriteString(styles.HelpDescStyle.Render(" (d:cycle)"))
+	switch m.viewMode {
+	case ManifestViewRaw:
+		header.WriteString(m.rawHeader())
+	case ManifestViewEnv:
+		header.WriteString(m.env.header())
+	default:
+		header.WriteString(styles.HelpDescStyle.Render(" (d:cycle)"))
+	}
 	header.WriteString("\n")
 
 	return header.String() + m.viewport.View()
 }
 
+func (m ManifestPanel) rawHeader() string {
+	var b strings.Builder
+	b.WriteString(" " + strings.ToLower(m.raw.ref.String()))
+	b.WriteString(styles.SubtitleStyle.Render(" " + m.raw.format.String()))
+	if m.raw.showAll {
+		b.WriteString(styles.SubtitleStyle.Render(" +status"))
+	}
+	switch {
+	case m.raw.searching:
+		b.WriteString(styles.SubtitleStyle.Render(" / "))
+		b.WriteString(m.raw.searchInput.View())
+	case m.raw.query != "":
+		b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf(" /%s (%d, n/N)", m.raw.query, m.raw.matchCount())))
+	default:
+		b.WriteString(styles.HelpDescStyle.Render(" (o:object f:json/yaml s:status z:fold /:search)"))
+	}
+	return b.String()
+}
+
 func (m *ManifestPanel) SetPod(pod *k8s.PodInfo) {
+	changed := m.pod == nil || pod == nil || m.pod.Name != pod.Name || m.pod.Namespace != pod.Namespace
 	m.pod = pod
+	if changed {
+		m.raw = newRawManifest()
+		m.env = envView{}
+		if m.viewMode == ManifestViewRaw || m.viewMode == ManifestViewEnv {
+			m.viewMode = ManifestViewSummary
+		}
+	}
+	m.updateContent()
+}
+
+// SetObject delivers the object asked for with LoadManifestMsg.
+func (m *ManifestPanel) SetObject(ref k8s.ObjectReference, fields map[string]interface{}, err error) {
+	m.raw.setObject(ref, fields, err)
+	m.updateContent()
+}
+
+// SetEnv delivers the environment asked for with LoadEnvMsg.
+func (m *ManifestPanel) SetEnv(namespace, pod string, envs []k8s.ContainerEnv, err error) {
+	m.env.setEnv(namespace, pod, envs, err)
 	m.updateContent()
 }
 
+// EnvShown reports whether the env mode is active, for reloading on
+// refresh.
+func (m ManifestPanel) EnvShown() bool {
+	return m.viewMode == ManifestViewEnv && m.pod != nil
+}
+
+// RawObject returns the object the raw mode shows, if it is active.
+func (m ManifestPanel) RawObject() (k8s.ObjectReference, bool) {
+	return m.raw.ref, m.viewMode == ManifestViewRaw && m.raw.ref.Name != ""
+}
+
+func (m ManifestPanel) IsSearching() bool {
+	return m.viewMode == ManifestViewRaw && m.raw.searching
+}
+
 func (m *ManifestPanel) SetRelated(related *k8s.RelatedResources) {
 	m.related = related
 	m.updateContent()
@@ -112,9 +242,28 @@ func (m *ManifestPanel) updateContent() {
 	var content strings.Builder
 
 	switch m.viewMode {
+	case ManifestViewRaw:
+		text, row := m.raw.content(m.width)
+		m.viewport.SetContent(text)
+		// Keep the cursor on screen
+		if row < m.viewport.YOffset {
+			m.viewport.SetYOffset(row)
+		} else if row >= m.viewport.YOffset+m.viewport.Height {
+			m.viewport.SetYOffset(row - m.viewport.Height + 1)
+		}
+		return
+
+	case ManifestViewEnv:
+		m.viewport.SetContent(m.env.content(m.width))
+		return
+
 	case ManifestViewSummary:
 		// Summary: Basic pod info and debug hints
 		content.WriteString(m.renderPodInfo())
+		if terminations := m.renderTerminations(); terminations != "" {
+			content.WriteString("\n")
+			content.WriteString(terminations)
+		}
 		if len(m.helpers) > 0 {
 			content.WriteString("\n")
 			content.WriteString(m.renderHelpers())
@@ -169,18 +318,14 @@ func (m ManifestPanel) renderHelpers() string {
 
 	b.WriteString(styles.EventWarning.Render("Debug Hints\n"))
 	for _, helper := range m.helpers {
-		severity := styles.StatusMuted
-		switch helper.Severity {
-		case "High":
-			severity = styles.StatusError
-		case "Warning":
-			severity = styles.EventWarning
-		}
-
+		severity := styles.GetSeverityStyle(helper.Severity)
 		b.WriteString(severity.Render(fmt.Sprintf("  [%s] %s\n", helper.Severity, helper.Issue)))
 		for _, suggestion := range helper.Suggestions {
 			b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("    • %s\n", suggestion)))
 		}
+		for _, doc := range helper.Docs {
+			b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("    ↗ %s\n", doc)))
+		}
 	}
 
 	return b.String()
@@ -202,6 +347,12 @@ func (m ManifestPanel) renderContainers() string {
 		b.WriteString("\n")
 		b.WriteString(fmt.Sprintf("    Ready:    %v\n", c.Ready))
 		b.WriteString(fmt.Sprintf("    Restarts: %d\n", c.RestartCount))
+		if c.Terminated != nil {
+			b.WriteString(m.renderTermination("Exit:", c.Terminated))
+		}
+		if c.LastTerminated != nil {
+			b.WriteString(m.renderTermination("Last exit:", c.LastTerminated))
+		}
 
 		if len(c.Ports) > 0 {
 			ports := make([]string, len(c.Ports))
@@ -215,6 +366,53 @@ func (m ManifestPanel) renderContainers() string {
 	return b.String()
 }
 
+// renderTerminations lists how each container last ended, with the
+// termination message, for the summary.
+func (m ManifestPanel) renderTerminations() string {
+	var b strings.Builder
+	for _, c := range m.pod.Containers {
+		t := c.LastTermination()
+		if t == nil || (t.ExitCode == 0 && c.Terminated == nil) {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(styles.SubtitleStyle.Render("Terminations\n"))
+		}
+		b.WriteString(styles.LogContainer.Render(fmt.Sprintf("  %s\n", c.Name)))
+		b.WriteString(m.renderTermination("Exit:", t))
+	}
+	return b.String()
+}
+
+const maxTerminationMessageLines = 8
+
+func (m ManifestPanel) renderTermination(label string, t *k8s.TerminationInfo) string {
+	var b strings.Builder
+
+	style := styles.StatusError
+	if t.ExitCode == 0 {
+		style = styles.StatusMuted
+	}
+	b.WriteString(fmt.Sprintf("    %-10s%s", label, style.Render(t.Summary())))
+	if !t.FinishedAt.IsZero() {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf(" (%s ago)", t.Age)))
+	}
+	b.WriteString("\n")
+
+	if t.Message != "" {
+		b.WriteString("    Message:\n")
+		lines := strings.Split(t.Message, "\n")
+		for i, line := range lines {
+			if i == maxTerminationMessageLines {
+				b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("      … %d more lines\n", len(lines)-i)))
+				break
+			}
+			b.WriteString("      " + styles.Truncate(line, m.width-8) + "\n")
+		}
+	}
+	return b.String()
+}
+
 func (m ManifestPanel) renderRelated() string {
 	var b strings.Builder
 
@@ -243,6 +441,10 @@ func (m ManifestPanel) renderRelated() string {
 		b.WriteString(fmt.Sprintf("  Secrets: %s\n", strings.Join(m.related.Secrets, ", ")))
 	}
 
+	if len(m.related.ConfigMaps) > 0 || len(m.related.Secrets) > 0 {
+		b.WriteString(styles.HelpDescStyle.Render("  (a: open from pod actions)") + "\n")
+	}
+
 	return b.String()
 }
 
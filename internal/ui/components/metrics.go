@@ -3,9 +3,11 @@ package components
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/doganarif/k9sight/internal/k8s"
 	"github.com/doganarif/k9sight/internal/ui/styles"
 )
@@ -18,8 +20,31 @@ type MetricsPanel struct {
 	width     int
 	height    int
 	available bool
+
+	// Recorded samples per container for the sparklines
+	history       map[string][]k8s.MetricSample
+	historyWindow time.Duration
+
+	// Range data from a metrics provider such as Prometheus. Without one
+	// the panel falls back to the recorded metrics-server samples.
+	hasRange    bool
+	rangeWindow int
+	rangeData   *k8s.PodRangeMetrics
+	rangeErr    error
+}
+
+// ShowRightSizingMsg asks the dashboard to offer the panel's resource
+// recommendations for copying or applying.
+type ShowRightSizingMsg struct {
+	Recommendations []k8s.Recommendation
 }
 
+// metricsWindows are the ranges w cycles through when a metrics provider
+// is configured.
+var metricsWindows = []time.Duration{15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+const chartHeight = 3
+
 func NewMetricsPanel() MetricsPanel {
 	return MetricsPanel{}
 }
@@ -29,6 +54,32 @@ func (m MetricsPanel) Init() tea.Cmd {
 }
 
 func (m MetricsPanel) Update(msg tea.Msg) (MetricsPanel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "o" {
+		var recs []k8s.Recommendation
+		if m.pod != nil {
+			for _, c := range m.pod.Containers {
+				if rec, ok := m.recommend(c); ok {
+					recs = append(recs, rec)
+				}
+			}
+		}
+		if len(recs) == 0 {
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return ShowRightSizingMsg{Recommendations: recs}
+		}
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "w" && m.hasRange {
+		m.rangeWindow = (m.rangeWindow + 1) % len(metricsWindows)
+		// Note: the fetch for the new window is handled by app
+		m.rangeData = nil
+		m.rangeErr = nil
+		m.updateContent()
+		return m, nil
+	}
+
 	var cmd tea.Cmd
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
@@ -41,8 +92,12 @@ func (m MetricsPanel) View() string {
 
 	var header strings.Builder
 	header.WriteString(styles.PanelTitleStyle.Render("Resource Usage"))
-	if !m.available {
+	if m.hasRange {
+		header.WriteString(styles.SubtitleStyle.Render(" (last " + formatWindow(m.RangeWindow()) + ", w window)"))
+	} else if !m.available {
 		header.WriteString(styles.SubtitleStyle.Render(" (metrics-server not available)"))
+	} else if len(m.history) > 0 {
+		header.WriteString(styles.SubtitleStyle.Render(" (last " + formatWindow(m.historyWindow) + ")"))
 	}
 	header.WriteString("\n")
 
@@ -55,7 +110,42 @@ func (m *MetricsPanel) SetMetrics(metrics *k8s.PodMetrics) {
 	m.updateContent()
 }
 
+// SetHistory sets the samples recorded over window, keyed by container.
+func (m *MetricsPanel) SetHistory(history map[string][]k8s.MetricSample, window time.Duration) {
+	m.history = history
+	m.historyWindow = window
+	m.updateContent()
+}
+
+// SetRangeAvailable enables window selection and range charts when a
+// metrics provider is configured.
+func (m *MetricsPanel) SetRangeAvailable(available bool) {
+	m.hasRange = available
+	if !available {
+		m.rangeData = nil
+		m.rangeErr = nil
+	}
+	m.updateContent()
+}
+
+// SetRange sets the provider data for the current window. On error the
+// panel keeps showing the metrics-server samples.
+func (m *MetricsPanel) SetRange(data *k8s.PodRangeMetrics, err error) {
+	m.rangeData = data
+	m.rangeErr = err
+	m.updateContent()
+}
+
+// RangeWindow is how far back range data is queried.
+func (m MetricsPanel) RangeWindow() time.Duration {
+	return metricsWindows[m.rangeWindow]
+}
+
 func (m *MetricsPanel) SetPod(pod *k8s.PodInfo) {
+	if m.pod == nil || pod == nil || m.pod.Namespace != pod.Namespace || m.pod.Name != pod.Name {
+		m.rangeData = nil
+		m.rangeErr = nil
+	}
 	m.pod = pod
 	m.updateContent()
 }
@@ -88,9 +178,16 @@ func (m *MetricsPanel) updateContent() {
 		return
 	}
 
+	if m.hasRange && m.rangeErr != nil {
+		content.WriteString(styles.StatusError.Render("  Prometheus: " + m.rangeErr.Error()))
+		content.WriteString("\n\n")
+	}
+
 	content.WriteString(styles.SubtitleStyle.Render("Container Resources:\n\n"))
 
-	for _, c := range m.pod.Containers {
+	usage := k8s.CalculateResourceUsage(m.metrics, m.pod)
+
+	for i, c := range m.pod.Containers {
 		content.WriteString(styles.LogContainer.Render(fmt.Sprintf("  %s\n", c.Name)))
 
 		content.WriteString(fmt.Sprintf("    CPU Request:    %s\n", formatResourceValue(c.Resources.CPURequest)))
@@ -98,17 +195,48 @@ func (m *MetricsPanel) updateContent() {
 		content.WriteString(fmt.Sprintf("    Memory Request: %s\n", formatResourceValue(c.Resources.MemoryRequest)))
 		content.WriteString(fmt.Sprintf("    Memory Limit:   %s\n", formatResourceValue(c.Resources.MemoryLimit)))
 
-		if m.metrics != nil {
-			for _, cm := range m.metrics.Containers {
-				if cm.Name == c.Name {
-					content.WriteString("\n")
-					content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    CPU Usage:      %s\n", cm.CPUUsage)))
-					content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    Memory Usage:   %s\n", cm.MemoryUsage)))
-					break
-				}
-			}
+		if usage != nil {
+			u := usage.Containers[i]
+			content.WriteString("\n")
+			content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    CPU Usage:      %s\n", k8s.FormatCPU(u.CPUMilli))))
+			content.WriteString(renderUsageBar("of request", u.CPURequestPercent, u.CPURequestMilli > 0))
+			content.WriteString(renderUsageBar("of limit", u.CPULimitPercent, u.CPULimitMilli > 0))
+			content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    Memory Usage:   %s\n", k8s.FormatMemory(u.MemoryBytes))))
+			content.WriteString(renderUsageBar("of request", u.MemRequestPercent, u.MemoryRequestBytes > 0))
+			content.WriteString(renderUsageBar("of limit", u.MemLimitPercent, u.MemoryLimitBytes > 0))
+		}
+
+		if r := m.rangeData.Container(c.Name); m.hasRange && r != nil {
+			content.WriteString("\n")
+			content.WriteString(m.renderRange(c, r))
+		} else if m.hasRange && m.rangeErr == nil {
+			content.WriteString(styles.StatusMuted.Render("\n    Loading history...\n"))
+		} else if samples := m.history[c.Name]; len(samples) > 1 {
+			content.WriteString("\n")
+			content.WriteString(m.renderTrend(c, samples))
 		}
 
+		content.WriteString(m.renderRecommendation(c))
+
+		content.WriteString("\n")
+	}
+
+	if usage != nil && len(usage.Containers) > 1 {
+		t := usage.Total
+		content.WriteString(styles.LogContainer.Render("  Pod Total\n"))
+		content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    CPU:            %s\n", usage.CPUUsed)))
+		content.WriteString(renderUsageBar("of request", t.CPURequestPercent, t.CPURequestMilli > 0))
+		content.WriteString(renderUsageBar("of limit", t.CPULimitPercent, t.CPULimitMilli > 0))
+		content.WriteString(styles.StatusRunning.Render(fmt.Sprintf("    Memory:         %s\n", usage.MemUsed)))
+		content.WriteString(renderUsageBar("of request", t.MemRequestPercent, t.MemoryRequestBytes > 0))
+		content.WriteString(renderUsageBar("of limit", t.MemLimitPercent, t.MemoryLimitBytes > 0))
+		content.WriteString("\n")
+	}
+
+	if m.hasRange && m.rangeData != nil && (len(m.rangeData.NetworkRx) > 0 || len(m.rangeData.NetworkTx) > 0) {
+		content.WriteString(styles.LogContainer.Render("  Pod Network\n"))
+		content.WriteString(m.renderSeriesLine("Rx", m.rangeData.NetworkRx, 0, formatRate, styles.StatusRunning))
+		content.WriteString(m.renderSeriesLine("Tx", m.rangeData.NetworkTx, 0, formatRate, styles.StatusRunning))
 		content.WriteString("\n")
 	}
 
@@ -116,7 +244,7 @@ func (m *MetricsPanel) updateContent() {
 		content.WriteString(styles.StatusMuted.Render("\n  Waiting for metrics data..."))
 	}
 
-	issues := m.checkResourceIssues()
+	issues := m.checkResourceIssues(usage)
 	if len(issues) > 0 {
 		content.WriteString(styles.EventWarning.Render("\n  Potential Issues:\n"))
 		for _, issue := range issues {
@@ -127,7 +255,217 @@ func (m *MetricsPanel) updateContent() {
 	m.viewport.SetContent(content.String())
 }
 
-func (m MetricsPanel) checkResourceIssues() []string {
+// renderTrend draws CPU and memory sparklines with min/avg/max. Each line
+// is scaled against the container's limit when one is set.
+func (m MetricsPanel) renderTrend(c k8s.ContainerInfo, samples []k8s.MetricSample) string {
+	const labelWidth = 46 // indent, label and the min/avg/max text
+	width := m.width - labelWidth
+	if width < 10 {
+		width = 10
+	}
+
+	cpu := make([]int64, len(samples))
+	mem := make([]int64, len(samples))
+	for i, s := range samples {
+		cpu[i] = s.CPUMilli
+		mem[i] = s.MemoryBytes
+	}
+
+	cpuStats := k8s.CPUStats(samples)
+	memStats := k8s.MemoryStats(samples)
+
+	var b strings.Builder
+	b.WriteString("    CPU    ")
+	b.WriteString(styles.StatusRunning.Render(Sparkline(cpu, width, k8s.CPUMilli(c.Resources.CPULimit))))
+	b.WriteString(styles.StatusMuted.Render(fmt.Sprintf(" min %s avg %s max %s\n",
+		k8s.FormatCPU(cpuStats.Min), k8s.FormatCPU(cpuStats.Avg), k8s.FormatCPU(cpuStats.Max))))
+
+	memStyle := styles.StatusRunning
+	memLimit := k8s.MemoryBytes(c.Resources.MemoryLimit)
+	if memLimit > 0 && memStats.Max*10 >= memLimit*9 {
+		memStyle = styles.StatusError
+	}
+	b.WriteString("    Memory ")
+	b.WriteString(memStyle.Render(Sparkline(mem, width, memLimit)))
+	b.WriteString(styles.StatusMuted.Render(fmt.Sprintf(" min %s avg %s max %s\n",
+		k8s.FormatMemory(memStats.Min), k8s.FormatMemory(memStats.Avg), k8s.FormatMemory(memStats.Max))))
+
+	return b.String()
+}
+
+// renderRange draws provider data for a container: CPU and memory charts
+// scaled to the limit, plus throttling and restart lines.
+func (m MetricsPanel) renderRange(c k8s.ContainerInfo, r *k8s.ContainerRangeMetrics) string {
+	var b strings.Builder
+
+	cpuLimit := float64(k8s.CPUMilli(c.Resources.CPULimit)) / 1000
+	b.WriteString(m.renderChart("CPU", r.CPU, cpuLimit, formatCores))
+
+	memLimit := float64(k8s.MemoryBytes(c.Resources.MemoryLimit))
+	b.WriteString(m.renderChart("Memory", r.Memory, memLimit, formatBytes))
+
+	throttleStyle := styles.StatusRunning
+	if k8s.SeriesMax(r.Throttling) >= 0.25 {
+		throttleStyle = styles.StatusPending
+	}
+	b.WriteString(m.renderSeriesLine("Throttled", r.Throttling, 1, formatFraction, throttleStyle))
+
+	if len(r.Restarts) > 0 {
+		restartStyle := styles.StatusRunning
+		first, last := r.Restarts[0].Value, r.Restarts[len(r.Restarts)-1].Value
+		if last > first {
+			restartStyle = styles.StatusError
+		}
+		b.WriteString(fmt.Sprintf("    %-10s", "Restarts"))
+		b.WriteString(restartStyle.Render(FloatSparkline(k8s.SeriesValues(r.Restarts), m.chartWidth(), 0)))
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf(" +%.0f\n", last-first)))
+	}
+
+	return b.String()
+}
+
+func (m MetricsPanel) chartWidth() int {
+	const labelWidth = 32 // indent, label and the max text
+	width := m.width - labelWidth
+	if width < 10 {
+		width = 10
+	}
+	return width
+}
+
+// renderChart draws a multi-row chart. It turns red once the maximum gets
+// within 10% of the limit.
+func (m MetricsPanel) renderChart(label string, points []k8s.SeriesPoint, limit float64, format func(float64) string) string {
+	if len(points) == 0 {
+		return fmt.Sprintf("    %-10s", label) + styles.StatusMuted.Render("no data") + "\n"
+	}
+
+	max := k8s.SeriesMax(points)
+	style := styles.StatusRunning
+	if limit > 0 && max >= limit*0.9 {
+		style = styles.StatusError
+	}
+
+	summary := "max " + format(max)
+	if limit > 0 {
+		summary += " / limit " + format(limit)
+	}
+
+	var b strings.Builder
+	rows := Chart(k8s.SeriesValues(points), m.chartWidth(), chartHeight, limit)
+	for i, row := range rows {
+		prefix := strings.Repeat(" ", 14)
+		if i == 0 {
+			prefix = fmt.Sprintf("    %-10s", label)
+		}
+		b.WriteString(prefix + style.Render(row))
+		if i == 0 {
+			b.WriteString(styles.StatusMuted.Render(" " + summary))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (m MetricsPanel) renderSeriesLine(label string, points []k8s.SeriesPoint, ceiling float64, format func(float64) string, style lipgloss.Style) string {
+	line := fmt.Sprintf("    %-10s", label)
+	if len(points) == 0 {
+		return line + styles.StatusMuted.Render("no data") + "\n"
+	}
+	line += style.Render(FloatSparkline(k8s.SeriesValues(points), m.chartWidth(), ceiling))
+	return line + styles.StatusMuted.Render(" max "+format(k8s.SeriesMax(points))) + "\n"
+}
+
+func formatCores(v float64) string {
+	return k8s.FormatCPU(int64(v * 1000))
+}
+
+func formatBytes(v float64) string {
+	return k8s.FormatMemory(int64(v))
+}
+
+func formatRate(v float64) string {
+	return k8s.FormatMemory(int64(v)) + "/s"
+}
+
+func formatFraction(v float64) string {
+	return fmt.Sprintf("%.0f%%", v*100)
+}
+
+// recommend suggests resources for c from the provider range when there
+// is one, otherwise from the recorded session samples.
+func (m MetricsPanel) recommend(c k8s.ContainerInfo) (k8s.Recommendation, bool) {
+	if r := m.rangeData.Container(c.Name); m.hasRange && r != nil {
+		return k8s.RecommendFromRange(c, r)
+	}
+	return k8s.RecommendFromSamples(c, m.history[c.Name])
+}
+
+func (m MetricsPanel) renderRecommendation(c k8s.ContainerInfo) string {
+	rec, ok := m.recommend(c)
+	if !ok {
+		if rec.Samples == 0 {
+			return ""
+		}
+		return styles.StatusMuted.Render(fmt.Sprintf("    Right-size  waiting for samples (%d/%d)\n", rec.Samples, k8s.MinRecommendSamples))
+	}
+
+	// Values that differ from the current spec are highlighted
+	changed := func(same bool, suggested string) string {
+		if same {
+			return styles.StatusMuted.Render(suggested)
+		}
+		return styles.StatusPending.Render(suggested)
+	}
+
+	var b strings.Builder
+	b.WriteString("    Right-size  requests cpu ")
+	b.WriteString(changed(k8s.CPUMilli(rec.Current.CPURequest) == rec.CPURequestMilli, rec.CPURequest()))
+	b.WriteString(" mem ")
+	b.WriteString(changed(k8s.MemoryBytes(rec.Current.MemoryRequest) == rec.MemoryRequestBytes, rec.MemoryRequest()))
+	b.WriteString("  limits cpu ")
+	b.WriteString(changed(k8s.CPUMilli(rec.Current.CPULimit) == rec.CPULimitMilli, rec.CPULimit()))
+	b.WriteString(" mem ")
+	b.WriteString(changed(k8s.MemoryBytes(rec.Current.MemoryLimit) == rec.MemoryLimitBytes, rec.MemoryLimit()))
+	b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("  (p95 %s/%s, o apply)\n",
+		k8s.FormatCPU(rec.CPUP95Milli), k8s.FormatMemory(rec.MemP95Bytes))))
+	return b.String()
+}
+
+const usageBarWidth = 10
+
+// renderUsageBar draws a percentage bar colored by threshold: green below
+// 70%, amber below 90%, red above.
+func renderUsageBar(label string, percent float64, set bool) string {
+	prefix := fmt.Sprintf("      %-11s ", label)
+	if !set {
+		return prefix + styles.StatusMuted.Render("not set") + "\n"
+	}
+
+	filled := int(percent * usageBarWidth / 100)
+	if filled > usageBarWidth {
+		filled = usageBarWidth
+	}
+	if filled < 0 {
+		filled = 0
+	}
+
+	style := usageStyle(percent)
+	bar := style.Render(strings.Repeat("█", filled)) + styles.StatusMuted.Render(strings.Repeat("░", usageBarWidth-filled))
+	return prefix + bar + style.Render(fmt.Sprintf(" %4.0f%%", percent)) + "\n"
+}
+
+func usageStyle(percent float64) lipgloss.Style {
+	switch {
+	case percent >= 90:
+		return styles.StatusError
+	case percent >= 70:
+		return styles.StatusPending
+	}
+	return styles.StatusRunning
+}
+
+func (m MetricsPanel) checkResourceIssues(usage *k8s.ResourceUsageSummary) []string {
 	if m.pod == nil {
 		return nil
 	}
@@ -135,18 +473,18 @@ func (m MetricsPanel) checkResourceIssues() []string {
 	var issues []string
 
 	for _, c := range m.pod.Containers {
-		if c.Resources.MemoryLimit == "" || c.Resources.MemoryLimit == "0" {
+		if k8s.MemoryBytes(c.Resources.MemoryLimit) == 0 {
 			issues = append(issues, fmt.Sprintf("Container '%s' has no memory limit", c.Name))
 		}
-		if c.Resources.CPULimit == "" || c.Resources.CPULimit == "0" {
+		if k8s.CPUMilli(c.Resources.CPULimit) == 0 {
 			issues = append(issues, fmt.Sprintf("Container '%s' has no CPU limit", c.Name))
 		}
-		if c.Resources.MemoryRequest == "" || c.Resources.MemoryRequest == "0" {
+		if k8s.MemoryBytes(c.Resources.MemoryRequest) == 0 {
 			issues = append(issues, fmt.Sprintf("Container '%s' has no memory request", c.Name))
 		}
 	}
 
-	return issues
+	return append(issues, k8s.UsageIssues(usage)...)
 }
 
 func formatResourceValue(v string) string {
@@ -35,6 +35,9 @@ type Navigator struct {
 	searchQuery  string
 	resourceType k8s.ResourceType
 	keys         keys.KeyMap
+
+	// Drifted fields per workload name, for the drift marker
+	drift map[string]int
 }
 
 func NewNavigator() Navigator {
@@ -145,7 +148,7 @@ func (n Navigator) maxItems() int {
 	case ModeNamespace:
 		return len(n.filteredNamespaces())
 	case ModeResourceType:
-		return len(k8s.AllResourceTypes)
+		return len(k8s.SelectableResourceTypes)
 	}
 	return 0
 }
@@ -228,7 +231,7 @@ func (n Navigator) renderWorkloads() string {
 	var b strings.Builder
 
 	// Header
-	header := fmt.Sprintf("  %-32s %-10s %-15s %-8s", "NAME", "READY", "STATUS", "AGE")
+	header := fmt.Sprintf("  %-32s %-10s %-15s %-8s %s", "NAME", "READY", "STATUS", "AGE", "HINT")
 	b.WriteString(styles.TableHeaderStyle.Render(header))
 	b.WriteString("\n")
 
@@ -253,15 +256,48 @@ func (n Navigator) renderWorkloadRow(w k8s.WorkloadInfo, selected bool) string {
 
 	name := styles.Truncate(w.Name, 32)
 	statusStyle := styles.GetStatusStyle(w.Status)
+	hint := n.renderWorkloadHint(w)
 
 	if selected {
 		rowStyle := lipgloss.NewStyle().Background(styles.Surface)
-		return rowStyle.Render(fmt.Sprintf("%s%-32s %-10s %-15s %-8s",
-			cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age))
+		return rowStyle.Render(fmt.Sprintf("%s%-32s %-10s %-15s %-8s %s",
+			cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age, hint))
+	}
+
+	return fmt.Sprintf("%s%-32s %-10s %-15s %-8s %s",
+		cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age, hint)
+}
+
+// renderWorkloadHint shows the most severe workload health hint, with a
+// count when there are more, after a ≠N marker when N fields drifted from
+// the manifest.
+func (n Navigator) renderWorkloadHint(w k8s.WorkloadInfo) string {
+	// Name, ready, status and age take 71 columns
+	width := n.width - 73
+	if width < 10 {
+		return ""
+	}
+
+	var drift string
+	if count := n.drift[w.Name]; count > 0 {
+		drift = fmt.Sprintf("≠%d", count)
+		width -= len([]rune(drift)) + 1
+		drift = styles.SyntaxLiteral.Render(drift) + " "
 	}
 
-	return fmt.Sprintf("%s%-32s %-10s %-15s %-8s",
-		cursor, name, w.Ready, statusStyle.Render(w.Status), w.Age)
+	hints := k8s.AnalyzeWorkload(&w)
+	if len(hints) == 0 {
+		if drift != "" {
+			return drift + styles.StatusMuted.Render(styles.Truncate("drifted from manifest", width))
+		}
+		return ""
+	}
+
+	text := hints[0].Issue
+	if len(hints) > 1 {
+		text += fmt.Sprintf(" (+%d)", len(hints)-1)
+	}
+	return drift + styles.GetSeverityStyle(hints[0].Severity).Render(styles.Truncate(text, width))
 }
 
 func (n Navigator) renderPods() string {
@@ -346,7 +382,7 @@ func (n Navigator) renderNamespaces() string {
 func (n Navigator) renderResourceTypes() string {
 	var b strings.Builder
 
-	for i, rt := range k8s.AllResourceTypes {
+	for i, rt := range k8s.SelectableResourceTypes {
 		cursor := "  "
 		if i == n.cursor {
 			cursor = styles.CursorStyle.Render("> ")
@@ -471,9 +507,18 @@ func (n *Navigator) SetNamespaces(namespaces []string) {
 }
 
 func (n *Navigator) SetResourceType(rt k8s.ResourceType) {
+	if rt != n.resourceType {
+		n.drift = nil
+	}
 	n.resourceType = rt
 }
 
+// SetDrift sets the number of drifted fields of each workload by name;
+// workloads without an entry are not marked.
+func (n *Navigator) SetDrift(drift map[string]int) {
+	n.drift = drift
+}
+
 func (n *Navigator) SetMode(mode NavigatorMode) {
 	n.mode = mode
 	n.cursor = 0
@@ -510,8 +555,8 @@ func (n Navigator) SelectedNamespace() string {
 }
 
 func (n Navigator) SelectedResourceType() k8s.ResourceType {
-	if n.cursor >= 0 && n.cursor < len(k8s.AllResourceTypes) {
-		return k8s.AllResourceTypes[n.cursor]
+	if n.cursor >= 0 && n.cursor < len(k8s.SelectableResourceTypes) {
+		return k8s.SelectableResourceTypes[n.cursor]
 	}
 	return k8s.ResourceDeployments
 }
@@ -0,0 +1,419 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/keys"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// NodesView lists nodes with their allocation and usage. Opening a node
+// shows its conditions, taints and resources above the pods it runs.
+type NodesView struct {
+	nodes     []k8s.NodeInfo
+	cursor    int
+	err       error
+	detail    string // name of the node shown in detail, "" for the list
+	pods      []k8s.PodInfo
+	podCursor int
+	podsErr   error
+	width     int
+	height    int
+	keys      keys.KeyMap
+}
+
+func NewNodesView() NodesView {
+	return NodesView{
+		keys: keys.DefaultKeyMap(),
+	}
+}
+
+func (n NodesView) Init() tea.Cmd {
+	return nil
+}
+
+func (n NodesView) Update(msg tea.Msg) (NodesView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return n, nil
+	}
+
+	cursor, total := &n.cursor, len(n.nodes)
+	if n.detail != "" {
+		cursor, total = &n.podCursor, len(n.pods)
+	}
+
+	switch {
+	case key.Matches(keyMsg, n.keys.Up):
+		if *cursor > 0 {
+			*cursor--
+		}
+	case key.Matches(keyMsg, n.keys.Down):
+		if *cursor < total-1 {
+			*cursor++
+		}
+	case key.Matches(keyMsg, n.keys.Home):
+		*cursor = 0
+	case key.Matches(keyMsg, n.keys.End):
+		if total > 0 {
+			*cursor = total - 1
+		}
+	}
+	return n, nil
+}
+
+func (n NodesView) View() string {
+	if n.detail != "" {
+		return n.renderDetail()
+	}
+
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Primary).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+
+	b.WriteString(iconStyle.Render("◧"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render("NODES"))
+	b.WriteString(styles.StatusMuted.Render("   (enter details, a actions)"))
+	b.WriteString("\n\n")
+
+	if n.err != nil {
+		b.WriteString(styles.StatusError.Render("  Error: " + n.err.Error()))
+		return b.String()
+	}
+	if len(n.nodes) == 0 {
+		b.WriteString(styles.StatusMuted.Render("  No nodes found"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-30s %-26s %-14s %-7s %-7s %-7s %-7s %-9s %-10s %s",
+		"NAME", "STATUS", "ROLES", "CPU", "CPU/R", "MEM", "MEM/R", "PODS", "VERSION", "AGE")
+	b.WriteString(styles.TableHeaderStyle.Render(header))
+	b.WriteString("\n")
+
+	start, end := scrollWindow(n.cursor, len(n.nodes), n.height-8)
+	for i := start; i < end; i++ {
+		b.WriteString(n.renderRow(n.nodes[i], i == n.cursor))
+		b.WriteString("\n")
+	}
+
+	if start > 0 || end < len(n.nodes) {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d/%d", n.cursor+1, len(n.nodes))))
+	} else {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d nodes", len(n.nodes))))
+	}
+
+	return b.String()
+}
+
+func (n NodesView) renderRow(node k8s.NodeInfo, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = styles.CursorStyle.Render("> ")
+	}
+
+	roles := strings.Join(node.Roles, ",")
+	if roles == "" {
+		roles = "<none>"
+	}
+
+	row := fmt.Sprintf("%-30s ", styles.Truncate(node.Name, 30))
+	row += nodeStatusStyle(node.Status).Render(fmt.Sprintf("%-26s", styles.Truncate(node.Status, 26))) + " "
+	row += fmt.Sprintf("%-14s ", styles.Truncate(roles, 14))
+	row += formatTopPercent(node.CPUUsagePercent(), node.HasMetrics) + " "
+	row += formatTopPercent(node.CPURequestPercent(), node.CPUAllocatableMilli > 0) + " "
+	row += formatTopPercent(node.MemoryUsagePercent(), node.HasMetrics) + " "
+	row += formatTopPercent(node.MemoryRequestPercent(), node.MemoryAllocatableBytes > 0) + " "
+	row += fmt.Sprintf("%-9s ", fmt.Sprintf("%d/%d", node.PodCount, node.PodCapacity))
+	row += fmt.Sprintf("%-10s ", styles.Truncate(node.KubeletVersion, 10))
+	row += node.Age
+
+	if selected {
+		return lipgloss.NewStyle().Background(styles.Surface).Render(cursor + row)
+	}
+	return cursor + row
+}
+
+func (n NodesView) renderDetail() string {
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Primary).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+	sectionStyle := lipgloss.NewStyle().Foreground(styles.Secondary).Bold(true)
+
+	node := n.DetailNode()
+	b.WriteString(iconStyle.Render("◧"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render("NODE " + n.detail))
+	if node != nil {
+		b.WriteString("  ")
+		b.WriteString(nodeStatusStyle(node.Status).Render(node.Status))
+	}
+	b.WriteString(styles.StatusMuted.Render("   (enter open pod, a actions, esc back)"))
+	b.WriteString("\n\n")
+
+	if node == nil {
+		b.WriteString(styles.StatusMuted.Render("  Node no longer exists"))
+		return b.String()
+	}
+
+	roles := strings.Join(node.Roles, ",")
+	if roles == "" {
+		roles = "<none>"
+	}
+	b.WriteString(fmt.Sprintf("  Roles: %s   Kubelet: %s   Runtime: %s   IP: %s   Age: %s\n",
+		roles, node.KubeletVersion, node.ContainerRuntime, node.InternalIP, node.Age))
+	b.WriteString(styles.StatusMuted.Render("  OS: " + node.OSImage))
+	b.WriteString("\n")
+	if len(node.Taints) > 0 {
+		b.WriteString("  Taints: " + strings.Join(node.Taints, ", ") + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(sectionStyle.Render("  CONDITIONS"))
+	b.WriteString("\n")
+	for _, c := range node.Conditions {
+		b.WriteString(renderNodeCondition(c))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(sectionStyle.Render(fmt.Sprintf("  %-14s %-22s %s", "RESOURCES", "CPU", "MEMORY")))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  %-14s %-22s %s\n", "Capacity", k8s.FormatCPU(node.CPUCapacityMilli), k8s.FormatMemory(node.MemoryCapacityBytes)))
+	b.WriteString(fmt.Sprintf("  %-14s %-22s %s\n", "Allocatable", k8s.FormatCPU(node.CPUAllocatableMilli), k8s.FormatMemory(node.MemoryAllocatableBytes)))
+	b.WriteString(renderNodeResourceRow("Requests", node.CPURequestMilli, node.CPURequestPercent(), node.MemoryRequestBytes, node.MemoryRequestPercent()))
+	b.WriteString(renderNodeResourceRow("Limits", node.CPULimitMilli, node.CPULimitPercent(), node.MemoryLimitBytes, node.MemoryLimitPercent()))
+	if node.HasMetrics {
+		b.WriteString(renderNodeResourceRow("Usage", node.CPUUsageMilli, node.CPUUsagePercent(), node.MemoryUsageBytes, node.MemoryUsagePercent()))
+	} else {
+		b.WriteString(fmt.Sprintf("  %-14s ", "Usage") + styles.StatusMuted.Render("metrics-server not available") + "\n")
+	}
+
+	if problems := node.Problems(); len(problems) > 0 {
+		b.WriteString(styles.EventWarning.Render("\n  Potential Issues:\n"))
+		for _, p := range problems {
+			b.WriteString(styles.EventWarning.Render("  • " + p + "\n"))
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString(sectionStyle.Render(fmt.Sprintf("  PODS (%d)", len(n.pods))))
+	b.WriteString("\n")
+	b.WriteString(n.renderPods())
+
+	return b.String()
+}
+
+func (n NodesView) renderPods() string {
+	if n.podsErr != nil {
+		return styles.StatusError.Render("  Error: " + n.podsErr.Error())
+	}
+	if len(n.pods) == 0 {
+		return styles.StatusMuted.Render("  No pods on this node")
+	}
+
+	var b strings.Builder
+	header := fmt.Sprintf("  %-20s %-40s %-8s %-18s %-8s %s", "NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE")
+	b.WriteString(styles.TableHeaderStyle.Render(header))
+	b.WriteString("\n")
+
+	// The node summary above takes roughly 24 lines
+	start, end := scrollWindow(n.podCursor, len(n.pods), n.height-24)
+	for i := start; i < end; i++ {
+		p := n.pods[i]
+		cursor := "  "
+		if i == n.podCursor {
+			cursor = styles.CursorStyle.Render("> ")
+		}
+		restarts := fmt.Sprintf("%-8d", p.Restarts)
+		if p.Restarts > 0 {
+			restarts = styles.StatusError.Render(restarts)
+		}
+		row := fmt.Sprintf("%-20s %-40s %-8s ", styles.Truncate(p.Namespace, 20), styles.Truncate(p.Name, 40), p.Ready) +
+			styles.GetStatusStyle(p.Status).Render(fmt.Sprintf("%-18s", p.Status)) + " " + restarts + " " + p.Age
+		if i == n.podCursor {
+			b.WriteString(lipgloss.NewStyle().Background(styles.Surface).Render(cursor + row))
+		} else {
+			b.WriteString(cursor + row)
+		}
+		b.WriteString("\n")
+	}
+	if start > 0 || end < len(n.pods) {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d/%d", n.podCursor+1, len(n.pods))))
+	}
+	return b.String()
+}
+
+func renderNodeCondition(c k8s.NodeCondition) string {
+	// Ready should be True; every other condition (pressure, network) should be False
+	healthy := c.Status == "False"
+	if c.Type == "Ready" {
+		healthy = c.Status == "True"
+	}
+	style := styles.StatusRunning
+	if !healthy {
+		style = styles.StatusError
+	}
+
+	line := fmt.Sprintf("  %-20s ", c.Type) + style.Render(fmt.Sprintf("%-8s", c.Status))
+	detail := c.Reason
+	if !healthy && c.Message != "" {
+		detail = c.Message
+	}
+	if detail != "" {
+		line += " " + styles.StatusMuted.Render(styles.Truncate(detail, 80))
+	}
+	return line
+}
+
+func renderNodeResourceRow(label string, cpu int64, cpuPercent float64, mem int64, memPercent float64) string {
+	cpuText := fmt.Sprintf("%-8s", k8s.FormatCPU(cpu)) + usageStyle(cpuPercent).Render(fmt.Sprintf("%-14s", fmt.Sprintf("(%.0f%%)", cpuPercent)))
+	memText := fmt.Sprintf("%-8s", k8s.FormatMemory(mem)) + usageStyle(memPercent).Render(fmt.Sprintf("(%.0f%%)", memPercent))
+	return fmt.Sprintf("  %-14s ", label) + cpuText + " " + memText + "\n"
+}
+
+// nodeStatusStyle colors a node status, where a cordoned node shows as
+// "Ready,SchedulingDisabled".
+func nodeStatusStyle(status string) lipgloss.Style {
+	if strings.HasSuffix(status, ",SchedulingDisabled") {
+		if strings.HasPrefix(status, "Ready") {
+			return styles.StatusPending
+		}
+		return styles.StatusError
+	}
+	return styles.GetStatusStyle(status)
+}
+
+// scrollWindow returns the visible [start, end) rows keeping the cursor
+// centered once the list is longer than maxVisible.
+func scrollWindow(cursor, total, maxVisible int) (int, int) {
+	if maxVisible < 5 {
+		maxVisible = 15
+	}
+	if total <= maxVisible {
+		return 0, total
+	}
+
+	start := cursor - maxVisible/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxVisible
+	if end > total {
+		end = total
+		start = end - maxVisible
+	}
+	return start, end
+}
+
+// SetNodes replaces the list, keeping the cursor on the same node.
+func (n *NodesView) SetNodes(nodes []k8s.NodeInfo, err error) {
+	n.err = err
+	if err != nil {
+		return
+	}
+
+	var selected string
+	if node := n.SelectedNode(); node != nil {
+		selected = node.Name
+	}
+
+	n.nodes = nodes
+	n.cursor = 0
+	for i, node := range nodes {
+		if node.Name == selected {
+			n.cursor = i
+			break
+		}
+	}
+}
+
+// SelectedNode is the node under the cursor in the list.
+func (n NodesView) SelectedNode() *k8s.NodeInfo {
+	if n.cursor >= 0 && n.cursor < len(n.nodes) {
+		node := n.nodes[n.cursor]
+		return &node
+	}
+	return nil
+}
+
+// OpenNode switches to the detail of the named node.
+func (n *NodesView) OpenNode(name string) {
+	if n.detail != name {
+		n.pods = nil
+		n.podCursor = 0
+		n.podsErr = nil
+	}
+	n.detail = name
+}
+
+// CloseDetail goes back to the node list.
+func (n *NodesView) CloseDetail() {
+	n.detail = ""
+	n.pods = nil
+	n.podCursor = 0
+}
+
+// DetailNode returns the node shown in detail, or nil in the list.
+func (n NodesView) DetailNode() *k8s.NodeInfo {
+	if n.detail == "" {
+		return nil
+	}
+	for i := range n.nodes {
+		if n.nodes[i].Name == n.detail {
+			node := n.nodes[i]
+			return &node
+		}
+	}
+	return nil
+}
+
+// DetailName is the node shown in detail, or "" in the list.
+func (n NodesView) DetailName() string {
+	return n.detail
+}
+
+// SetPods sets the pods of node if it is still the one shown.
+func (n *NodesView) SetPods(node string, pods []k8s.PodInfo, err error) {
+	if node != n.detail {
+		return
+	}
+	n.podsErr = err
+	if err != nil {
+		return
+	}
+
+	var selected string
+	if p := n.SelectedPod(); p != nil {
+		selected = p.Namespace + "/" + p.Name
+	}
+	n.pods = pods
+	n.podCursor = 0
+	for i, p := range pods {
+		if p.Namespace+"/"+p.Name == selected {
+			n.podCursor = i
+			break
+		}
+	}
+}
+
+// SelectedPod is the pod under the cursor in the node detail.
+func (n NodesView) SelectedPod() *k8s.PodInfo {
+	if n.detail != "" && n.podCursor >= 0 && n.podCursor < len(n.pods) {
+		pod := n.pods[n.podCursor]
+		return &pod
+	}
+	return nil
+}
+
+func (n *NodesView) SetSize(width, height int) {
+	n.width = width
+	n.height = height
+}
@@ -0,0 +1,430 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// LoadManifestMsg asks the app to fetch the object shown in the raw
+// manifest view; the answer goes to ManifestPanel.SetObject.
+type LoadManifestMsg struct {
+	Ref k8s.ObjectReference
+}
+
+// rawManifest is the live object view of the manifest panel: YAML or JSON
+// with syntax highlighting, indentation folding and search.
+type rawManifest struct {
+	ref     k8s.ObjectReference
+	fields  map[string]interface{}
+	err     error
+	loading bool
+
+	format  k8s.ManifestFormat
+	showAll bool // include managedFields and status
+
+	lines  []string
+	folded map[int]bool // fold heads, by line
+	cursor int          // line index, always on a visible line
+
+	query       string
+	searching   bool
+	searchInput textinput.Model
+}
+
+func newRawManifest() rawManifest {
+	ti := textinput.New()
+	ti.Placeholder = "Search manifest..."
+	ti.CharLimit = 100
+	ti.Width = 30
+	return rawManifest{folded: map[int]bool{}, searchInput: ti}
+}
+
+func loadManifest(ref k8s.ObjectReference) tea.Cmd {
+	return func() tea.Msg {
+		return LoadManifestMsg{Ref: ref}
+	}
+}
+
+// open switches to ref and asks for it.
+func (r *rawManifest) open(ref k8s.ObjectReference) tea.Cmd {
+	if ref != r.ref {
+		r.ref = ref
+		r.fields = nil
+		r.lines = nil
+		r.folded = map[int]bool{}
+		r.cursor = 0
+	}
+	r.err = nil
+	r.loading = true
+	return loadManifest(ref)
+}
+
+func (r *rawManifest) setObject(ref k8s.ObjectReference, fields map[string]interface{}, err error) {
+	if ref != r.ref {
+		return
+	}
+	r.loading = false
+	r.err = err
+	if err != nil {
+		return
+	}
+	r.fields = fields
+	r.render()
+}
+
+// render prints the object again, keeping folds and the cursor when the
+// text did not change, e.g. on a reload.
+func (r *rawManifest) render() {
+	text, err := k8s.FormatManifest(r.fields, r.format, !r.showAll)
+	if err != nil {
+		r.err = err
+		return
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if strings.Join(lines, "\n") != strings.Join(r.lines, "\n") {
+		r.folded = map[int]bool{}
+		r.cursor = 0
+	}
+	r.lines = lines
+}
+
+func (r *rawManifest) update(msg tea.KeyMsg, pageSize int) tea.Cmd {
+	if r.searching {
+		switch msg.String() {
+		case "esc":
+			r.searching = false
+			r.searchInput.Blur()
+			r.searchInput.SetValue("")
+			r.query = ""
+			return nil
+		case "enter":
+			r.searching = false
+			r.searchInput.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		r.searchInput, cmd = r.searchInput.Update(msg)
+		// Live search from the cursor as you type
+		r.query = r.searchInput.Value()
+		r.jumpToMatch(r.cursor, 1)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "/":
+		r.searching = true
+		r.searchInput.Focus()
+		return textinput.Blink
+	case "n":
+		r.jumpToMatch(r.cursor+1, 1)
+	case "N":
+		r.jumpToMatch(r.cursor-1, -1)
+	case "f":
+		r.format = (r.format + 1) % 2
+		r.render()
+	case "s":
+		r.showAll = !r.showAll
+		r.render()
+	case "enter", " ", "z":
+		r.toggleFold(r.cursor)
+	case "Z":
+		r.toggleAllFolds()
+	case "j", "down":
+		r.move(1)
+	case "k", "up":
+		r.move(-1)
+	case "ctrl+d", "pgdown":
+		r.move(pageSize)
+	case "ctrl+u", "pgup":
+		r.move(-pageSize)
+	case "g", "home":
+		r.cursor = 0
+	case "G", "end":
+		if visible := r.visibleLines(); len(visible) > 0 {
+			r.cursor = visible[len(visible)-1]
+		}
+	}
+	return nil
+}
+
+// visibleLines returns the indexes of the lines not hidden by a fold.
+func (r rawManifest) visibleLines() []int {
+	var visible []int
+	for i := 0; i < len(r.lines); i++ {
+		visible = append(visible, i)
+		if r.folded[i] {
+			i = foldEnd(r.lines, i)
+		}
+	}
+	return visible
+}
+
+func (r *rawManifest) move(delta int) {
+	visible := r.visibleLines()
+	pos := r.cursorPosition(visible) + delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= len(visible) {
+		pos = len(visible) - 1
+	}
+	if pos >= 0 {
+		r.cursor = visible[pos]
+	}
+}
+
+func (r rawManifest) cursorPosition(visible []int) int {
+	for pos, i := range visible {
+		if i == r.cursor {
+			return pos
+		}
+	}
+	return 0
+}
+
+func (r *rawManifest) toggleFold(i int) {
+	if i >= len(r.lines) {
+		return
+	}
+	if r.folded[i] {
+		delete(r.folded, i)
+	} else if foldEnd(r.lines, i) > i {
+		r.folded[i] = true
+	}
+}
+
+// toggleAllFolds unfolds everything, or folds every top-level block when
+// nothing is folded.
+func (r *rawManifest) toggleAllFolds() {
+	if len(r.folded) > 0 {
+		r.folded = map[int]bool{}
+		return
+	}
+	if len(r.lines) < 2 {
+		return
+	}
+	// JSON nests everything in the root object
+	level := 0
+	if r.format == k8s.ManifestJSON {
+		level = indentOf(r.lines[1])
+	}
+	for i, line := range r.lines {
+		if indentOf(line) == level && foldEnd(r.lines, i) > i {
+			r.folded[i] = true
+		}
+	}
+	r.cursor = r.foldHead(r.cursor)
+}
+
+// foldHead returns the visible line showing i: i itself or the fold it is
+// hidden in.
+func (r rawManifest) foldHead(i int) int {
+	for _, v := range r.visibleLines() {
+		if v > i {
+			break
+		}
+		if v == i || (r.folded[v] && i <= foldEnd(r.lines, v)) {
+			return v
+		}
+	}
+	return i
+}
+
+// jumpToMatch moves the cursor to the first line matching the query from
+// line from in direction dir, wrapping around, and unfolds what hides it.
+func (r *rawManifest) jumpToMatch(from, dir int) {
+	if r.query == "" || len(r.lines) == 0 {
+		return
+	}
+	query := strings.ToLower(r.query)
+	for n := 0; n < len(r.lines); n++ {
+		i := ((from+dir*n)%len(r.lines) + len(r.lines)) % len(r.lines)
+		if strings.Contains(strings.ToLower(r.lines[i]), query) {
+			for head := range r.folded {
+				if head < i && i <= foldEnd(r.lines, head) {
+					delete(r.folded, head)
+				}
+			}
+			r.cursor = i
+			return
+		}
+	}
+}
+
+func (r rawManifest) matchCount() int {
+	if r.query == "" {
+		return 0
+	}
+	query := strings.ToLower(r.query)
+	count := 0
+	for _, line := range r.lines {
+		if strings.Contains(strings.ToLower(line), query) {
+			count++
+		}
+	}
+	return count
+}
+
+// content renders the visible lines and returns the cursor's row.
+func (r rawManifest) content(width int) (string, int) {
+	switch {
+	case r.loading && r.lines == nil:
+		return styles.StatusMuted.Render("Loading " + strings.ToLower(r.ref.String()) + "..."), 0
+	case r.err != nil:
+		return styles.StatusError.Render(fmt.Sprintf("Could not get %s: %v", strings.ToLower(r.ref.String()), r.err)), 0
+	}
+
+	var b strings.Builder
+	visible := r.visibleLines()
+	row := 0
+	for pos, i := range visible {
+		if i == r.cursor {
+			row = pos
+			b.WriteString(styles.CursorStyle.Render("> "))
+		} else {
+			b.WriteString("  ")
+		}
+
+		line := styles.Truncate(r.lines[i], width-2)
+		if r.query != "" && strings.Contains(strings.ToLower(line), strings.ToLower(r.query)) {
+			b.WriteString(highlightMatches(line, r.query))
+		} else {
+			b.WriteString(highlightManifestLine(line, r.format))
+		}
+		if r.folded[i] {
+			b.WriteString(styles.SyntaxPunct.Render(fmt.Sprintf(" … %d lines", foldEnd(r.lines, i)-i)))
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), row
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// foldEnd returns the last line of the block opened at line i, or i when
+// nothing is nested under it. YAML lists sit at the indentation of their
+// key, and JSON blocks end with a bracket at the opening indentation.
+func foldEnd(lines []string, i int) int {
+	base := indentOf(lines[i])
+	isKey := strings.HasSuffix(lines[i], ":")
+	end := i
+	for j := i + 1; j < len(lines); j++ {
+		indent := indentOf(lines[j])
+		trimmed := strings.TrimSpace(lines[j])
+		if indent > base || (isKey && indent == base && strings.HasPrefix(trimmed, "- ")) {
+			end = j
+			continue
+		}
+		if end > i && indent == base && (strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]")) {
+			end = j
+		}
+		break
+	}
+	return end
+}
+
+// highlightManifestLine colors keys, strings and literals of one line of
+// YAML or JSON.
+func highlightManifestLine(line string, format k8s.ManifestFormat) string {
+	indent := line[:indentOf(line)]
+	rest := line[len(indent):]
+
+	if format == k8s.ManifestJSON {
+		if strings.HasPrefix(rest, `"`) {
+			if idx := strings.Index(rest, `": `); idx > 0 {
+				return indent + styles.SyntaxKey.Render(rest[:idx+1]) + styles.SyntaxPunct.Render(":") + " " + highlightValue(rest[idx+3:], true)
+			}
+		}
+		return indent + highlightValue(rest, true)
+	}
+
+	var b strings.Builder
+	b.WriteString(indent)
+	for strings.HasPrefix(rest, "- ") {
+		b.WriteString(styles.SyntaxPunct.Render("- "))
+		rest = rest[2:]
+	}
+	if strings.HasSuffix(rest, ":") && !strings.Contains(rest, ": ") {
+		return b.String() + styles.SyntaxKey.Render(strings.TrimSuffix(rest, ":")) + styles.SyntaxPunct.Render(":")
+	}
+	if idx := strings.Index(rest, ": "); idx > 0 && !strings.HasPrefix(rest, `"`) && !strings.HasPrefix(rest, "'") {
+		b.WriteString(styles.SyntaxKey.Render(rest[:idx]))
+		b.WriteString(styles.SyntaxPunct.Render(":"))
+		b.WriteString(" ")
+		rest = rest[idx+2:]
+	}
+	b.WriteString(highlightValue(rest, false))
+	return b.String()
+}
+
+// highlightValue colors a scalar: strings in one color, numbers, booleans
+// and null in another, JSON punctuation muted.
+func highlightValue(value string, json bool) string {
+	if value == "" {
+		return ""
+	}
+	suffix := ""
+	if json {
+		if strings.HasSuffix(value, ",") {
+			value, suffix = strings.TrimSuffix(value, ","), ","
+		}
+		switch value {
+		case "{", "}", "[", "]", "{}", "[]":
+			return styles.SyntaxPunct.Render(value + suffix)
+		}
+	}
+	if suffix != "" {
+		suffix = styles.SyntaxPunct.Render(suffix)
+	}
+	switch value {
+	case "|", "|-", ">", ">-", "{}", "[]":
+		return styles.SyntaxPunct.Render(value) + suffix
+	case "true", "false", "null":
+		return styles.SyntaxLiteral.Render(value) + suffix
+	}
+	if isNumber(value) {
+		return styles.SyntaxLiteral.Render(value) + suffix
+	}
+	return styles.SyntaxString.Render(value) + suffix
+}
+
+func isNumber(s string) bool {
+	digits := 0
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '-' && i == 0, c == '.', c == 'e', c == 'E', c == '+':
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// highlightMatches marks every case-insensitive occurrence of query.
+func highlightMatches(line, query string) string {
+	var b strings.Builder
+	lower, q := strings.ToLower(line), strings.ToLower(query)
+	// Offsets only carry over when lowering kept the byte lengths
+	if len(lower) != len(line) {
+		return line
+	}
+	for {
+		idx := strings.Index(lower, q)
+		if idx < 0 {
+			b.WriteString(line)
+			return b.String()
+		}
+		b.WriteString(line[:idx])
+		b.WriteString(styles.SearchMatch.Render(line[idx : idx+len(q)]))
+		line, lower = line[idx+len(q):], lower[idx+len(q):]
+	}
+}
@@ -18,6 +18,12 @@ type ResultViewer struct {
 	ready    bool
 	width    int
 	height   int
+
+	// Set by ShowConfirm: the question answered with y/n and the
+	// ConfirmResult it is sent as
+	prompt string
+	action string
+	data   interface{}
 }
 
 func NewResultViewer() ResultViewer {
@@ -37,6 +43,16 @@ func (r ResultViewer) Update(msg tea.Msg) (ResultViewer, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if r.action != "" {
+			switch msg.String() {
+			case "y", "Y", "esc", "q", "n", "N":
+				r.visible = false
+				confirmed := msg.String() == "y" || msg.String() == "Y"
+				return r, func() tea.Msg {
+					return ConfirmResult{Confirmed: confirmed, Action: r.action, Data: r.data}
+				}
+			}
+		}
 		switch msg.String() {
 		case "esc", "q":
 			r.visible = false
@@ -91,6 +107,10 @@ func (r ResultViewer) View() string {
 	}
 
 	footer := "j/k scroll • g/G top/bottom • q/esc close" + scrollInfo
+	if r.action != "" {
+		footer = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true).Render(r.prompt+" y/n") +
+			" • j/k scroll • g/G top/bottom" + scrollInfo
+	}
 	b.WriteString(footerStyle.Render(footer))
 
 	// Wrap in a box
@@ -103,6 +123,7 @@ func (r ResultViewer) View() string {
 }
 
 func (r *ResultViewer) Show(title, content string, width, height int) {
+	r.prompt, r.action, r.data = "", "", nil
 	r.title = title
 	r.width = width
 	r.height = height
@@ -117,6 +138,14 @@ func (r *ResultViewer) Show(title, content string, width, height int) {
 	r.ready = true
 }
 
+// ShowConfirm shows content with a yes/no question below it, e.g. a diff
+// to apply. The answer is sent as a ConfirmResult carrying action and
+// data, like ConfirmDialog's.
+func (r *ResultViewer) ShowConfirm(title, content, prompt string, width, height int, action string, data interface{}) {
+	r.Show(title, content, width, height)
+	r.prompt, r.action, r.data = prompt, action, data
+}
+
 func (r *ResultViewer) Hide() {
 	r.visible = false
 }
@@ -0,0 +1,128 @@
+package components
+
+import (
+	"strings"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as block characters scaled
+// against ceiling, or against the largest value when ceiling is lower.
+// Passing a limit as ceiling makes a climb toward it visible.
+func Sparkline(values []int64, width int, ceiling int64) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	top := ceiling
+	for _, v := range values {
+		if v > top {
+			top = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if top > 0 && v > 0 {
+			idx = int(v * int64(len(sparkBlocks)-1) / top)
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// resample fits values into width columns, keeping the largest value of
+// each bucket so short spikes survive downsampling.
+func resample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for i := range out {
+		start := i * len(values) / width
+		end := (i + 1) * len(values) / width
+		max := values[start]
+		for _, v := range values[start+1 : end] {
+			if v > max {
+				max = v
+			}
+		}
+		out[i] = max
+	}
+	return out
+}
+
+// FloatSparkline is Sparkline for a whole range of float values, which are
+// resampled to width instead of cut to the most recent ones.
+func FloatSparkline(values []float64, width int, ceiling float64) string {
+	if width <= 0 || len(values) == 0 {
+		return ""
+	}
+	values = resample(values, width)
+
+	top := ceiling
+	for _, v := range values {
+		if v > top {
+			top = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if top > 0 && v > 0 {
+			idx = int(v * float64(len(sparkBlocks)-1) / top)
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
+
+// Chart renders values as a bar chart height rows tall, top row first,
+// scaled like FloatSparkline. Each row uses eighth blocks so a chart has
+// height*8 levels.
+func Chart(values []float64, width, height int, ceiling float64) []string {
+	if width <= 0 || height <= 0 || len(values) == 0 {
+		return nil
+	}
+	values = resample(values, width)
+
+	top := ceiling
+	for _, v := range values {
+		if v > top {
+			top = v
+		}
+	}
+
+	levels := make([]int, len(values))
+	for i, v := range values {
+		if top > 0 && v > 0 {
+			levels[i] = int(v * float64(height*8) / top)
+			if levels[i] == 0 {
+				levels[i] = 1
+			}
+		}
+	}
+
+	rows := make([]string, height)
+	for r := 0; r < height; r++ {
+		base := (height - 1 - r) * 8
+		var b strings.Builder
+		for _, level := range levels {
+			switch fill := level - base; {
+			case fill >= 8:
+				b.WriteRune('█')
+			case fill > 0:
+				b.WriteRune(sparkBlocks[fill-1])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
@@ -0,0 +1,304 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/keys"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+var topColumnLabels = map[k8s.TopSortColumn]string{
+	k8s.SortByCPU:           "CPU",
+	k8s.SortByCPURequest:    "CPU/R",
+	k8s.SortByCPULimit:      "CPU/L",
+	k8s.SortByMemory:        "MEM",
+	k8s.SortByMemoryRequest: "MEM/R",
+	k8s.SortByMemoryLimit:   "MEM/L",
+	k8s.SortByRestarts:      "RESTARTS",
+	k8s.SortByName:          "NAME",
+}
+
+// TopView lists pods by resource usage, like kubectl top with requests
+// and limits alongside.
+type TopView struct {
+	usages        []k8s.PodUsage
+	namespace     string
+	allNamespaces bool
+	sortColumn    k8s.TopSortColumn
+	ascending     bool
+	cursor        int
+	width         int
+	height        int
+	err           error
+	keys          keys.KeyMap
+}
+
+func NewTopView() TopView {
+	return TopView{
+		sortColumn: k8s.SortByCPU,
+		keys:       keys.DefaultKeyMap(),
+	}
+}
+
+func (t TopView) Init() tea.Cmd {
+	return nil
+}
+
+func (t TopView) Update(msg tea.Msg) (TopView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, t.keys.Up):
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case key.Matches(keyMsg, t.keys.Down):
+		if t.cursor < len(t.usages)-1 {
+			t.cursor++
+		}
+	case key.Matches(keyMsg, t.keys.Home):
+		t.cursor = 0
+	case key.Matches(keyMsg, t.keys.End):
+		if len(t.usages) > 0 {
+			t.cursor = len(t.usages) - 1
+		}
+	case key.Matches(keyMsg, t.keys.ToggleAllEvents):
+		t.allNamespaces = !t.allNamespaces
+		t.usages = nil
+		t.cursor = 0
+	default:
+		switch keyMsg.String() {
+		case "s":
+			t.sortColumn = nextSortColumn(t.sortColumn)
+			t.ascending = t.sortColumn == k8s.SortByName
+			t.sortUsages()
+		case "S":
+			t.ascending = !t.ascending
+			t.sortUsages()
+		}
+	}
+	return t, nil
+}
+
+func nextSortColumn(current k8s.TopSortColumn) k8s.TopSortColumn {
+	for i, c := range k8s.TopSortColumns {
+		if c == current {
+			return k8s.TopSortColumns[(i+1)%len(k8s.TopSortColumns)]
+		}
+	}
+	return k8s.SortByCPU
+}
+
+func (t *TopView) sortUsages() {
+	k8s.SortPodUsages(t.usages, t.sortColumn, t.ascending)
+}
+
+func (t TopView) View() string {
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Primary).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+
+	scope := "namespace " + t.namespace
+	if t.allNamespaces {
+		scope = "all namespaces"
+	}
+	b.WriteString(iconStyle.Render("▤"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render("TOP"))
+	b.WriteString(styles.StatusMuted.Render("  " + scope))
+	b.WriteString(styles.StatusMuted.Render("   (s sort column, S reverse, A all namespaces)"))
+	b.WriteString("\n\n")
+
+	if t.err != nil {
+		b.WriteString(styles.StatusError.Render("  Error: " + t.err.Error()))
+		return b.String()
+	}
+	if len(t.usages) == 0 {
+		b.WriteString(styles.StatusMuted.Render("  No pods found"))
+		return b.String()
+	}
+	if !t.hasMetrics() {
+		b.WriteString(styles.EventWarning.Render("  metrics-server not available, usage columns are empty"))
+		b.WriteString("\n")
+	}
+
+	header := "  "
+	if t.allNamespaces {
+		header += fmt.Sprintf("%-18s ", "NAMESPACE")
+	}
+	header += fmt.Sprintf("%-36s %-8s %-7s %-7s %-9s %-7s %-7s %-9s %s",
+		t.columnLabel(k8s.SortByName),
+		t.columnLabel(k8s.SortByCPU),
+		t.columnLabel(k8s.SortByCPURequest),
+		t.columnLabel(k8s.SortByCPULimit),
+		t.columnLabel(k8s.SortByMemory),
+		t.columnLabel(k8s.SortByMemoryRequest),
+		t.columnLabel(k8s.SortByMemoryLimit),
+		t.columnLabel(k8s.SortByRestarts),
+		"STATUS",
+	)
+	b.WriteString(styles.TableHeaderStyle.Render(header))
+	b.WriteString("\n")
+
+	start, end := t.visibleRange(len(t.usages))
+	for i := start; i < end; i++ {
+		b.WriteString(t.renderRow(t.usages[i], i == t.cursor))
+		b.WriteString("\n")
+	}
+
+	if start > 0 || end < len(t.usages) {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d/%d", t.cursor+1, len(t.usages))))
+	} else {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d pods", len(t.usages))))
+	}
+
+	return b.String()
+}
+
+func (t TopView) columnLabel(column k8s.TopSortColumn) string {
+	label := topColumnLabels[column]
+	if column != t.sortColumn {
+		return label
+	}
+	if t.ascending {
+		return label + "▲"
+	}
+	return label + "▼"
+}
+
+func (t TopView) hasMetrics() bool {
+	for _, u := range t.usages {
+		if u.HasMetrics {
+			return true
+		}
+	}
+	return false
+}
+
+func (t TopView) renderRow(u k8s.PodUsage, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = styles.CursorStyle.Render("> ")
+	}
+
+	row := ""
+	if t.allNamespaces {
+		row += fmt.Sprintf("%-18s ", styles.Truncate(u.Pod.Namespace, 18))
+	}
+	row += fmt.Sprintf("%-36s ", styles.Truncate(u.Pod.Name, 36))
+
+	if u.HasMetrics {
+		row += fmt.Sprintf("%-8s ", k8s.FormatCPU(u.Usage.CPUMilli))
+		row += formatTopPercent(u.Usage.CPURequestPercent, u.Usage.CPURequestMilli > 0) + " "
+		row += formatTopPercent(u.Usage.CPULimitPercent, u.Usage.CPULimitMilli > 0) + " "
+		row += fmt.Sprintf("%-9s ", k8s.FormatMemory(u.Usage.MemoryBytes))
+		row += formatTopPercent(u.Usage.MemRequestPercent, u.Usage.MemoryRequestBytes > 0) + " "
+		row += formatTopPercent(u.Usage.MemLimitPercent, u.Usage.MemoryLimitBytes > 0) + " "
+	} else {
+		row += fmt.Sprintf("%-8s %-7s %-7s %-9s %-7s %-7s ", "-", "-", "-", "-", "-", "-")
+	}
+
+	restarts := fmt.Sprintf("%-9d", u.Pod.Restarts)
+	if u.Pod.Restarts > 0 {
+		restarts = styles.StatusError.Render(restarts)
+	}
+	row += restarts + " "
+	row += styles.GetStatusStyle(u.Pod.Status).Render(u.Pod.Status)
+
+	if selected {
+		return lipgloss.NewStyle().Background(styles.Surface).Render(cursor + row)
+	}
+	return cursor + row
+}
+
+func formatTopPercent(percent float64, set bool) string {
+	if !set {
+		return styles.StatusMuted.Render(fmt.Sprintf("%-7s", "-"))
+	}
+	return usageStyle(percent).Render(fmt.Sprintf("%-7s", fmt.Sprintf("%.0f%%", percent)))
+}
+
+func (t TopView) visibleRange(total int) (int, int) {
+	maxVisible := t.height - 8
+	if maxVisible < 5 {
+		maxVisible = 15
+	}
+	if total <= maxVisible {
+		return 0, total
+	}
+
+	start := t.cursor - maxVisible/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxVisible
+	if end > total {
+		end = total
+		start = end - maxVisible
+	}
+	return start, end
+}
+
+// SetNamespace sets the namespace shown when not listing all namespaces.
+func (t *TopView) SetNamespace(namespace string) {
+	if t.namespace != namespace {
+		t.usages = nil
+		t.cursor = 0
+	}
+	t.namespace = namespace
+}
+
+// ListNamespace is the namespace to list; empty means all.
+func (t TopView) ListNamespace() string {
+	if t.allNamespaces {
+		return ""
+	}
+	return t.namespace
+}
+
+// SetUsages replaces the rows, keeping the cursor on the same pod when it
+// is still present after re-sorting.
+func (t *TopView) SetUsages(usages []k8s.PodUsage, err error) {
+	t.err = err
+	if err != nil {
+		return
+	}
+
+	var selected string
+	if u := t.SelectedPod(); u != nil {
+		selected = u.Namespace + "/" + u.Name
+	}
+
+	t.usages = usages
+	t.sortUsages()
+
+	t.cursor = 0
+	for i, u := range t.usages {
+		if u.Pod.Namespace+"/"+u.Pod.Name == selected {
+			t.cursor = i
+			break
+		}
+	}
+}
+
+func (t TopView) SelectedPod() *k8s.PodInfo {
+	if t.cursor >= 0 && t.cursor < len(t.usages) {
+		pod := t.usages[t.cursor].Pod
+		return &pod
+	}
+	return nil
+}
+
+func (t *TopView) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+}
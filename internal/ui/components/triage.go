@@ -0,0 +1,233 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/keys"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// TriageView ranks the pods of a namespace or the whole cluster by how
+// broken they look, with the most pressing hint for each.
+type TriageView struct {
+	results       []k8s.PodTriage
+	namespace     string
+	allNamespaces bool
+	cursor        int
+	width         int
+	height        int
+	err           error
+	keys          keys.KeyMap
+}
+
+func NewTriageView() TriageView {
+	return TriageView{
+		keys: keys.DefaultKeyMap(),
+	}
+}
+
+func (t TriageView) Init() tea.Cmd {
+	return nil
+}
+
+func (t TriageView) Update(msg tea.Msg) (TriageView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, t.keys.Up):
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case key.Matches(keyMsg, t.keys.Down):
+		if t.cursor < len(t.results)-1 {
+			t.cursor++
+		}
+	case key.Matches(keyMsg, t.keys.Home):
+		t.cursor = 0
+	case key.Matches(keyMsg, t.keys.End):
+		if len(t.results) > 0 {
+			t.cursor = len(t.results) - 1
+		}
+	case key.Matches(keyMsg, t.keys.ToggleAllEvents):
+		t.allNamespaces = !t.allNamespaces
+		t.results = nil
+		t.cursor = 0
+	}
+	return t, nil
+}
+
+func (t TriageView) View() string {
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Error).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+
+	scope := "namespace " + t.namespace
+	if t.allNamespaces {
+		scope = "all namespaces"
+	}
+	b.WriteString(iconStyle.Render("!"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render("TRIAGE"))
+	b.WriteString(styles.StatusMuted.Render("  " + scope))
+	b.WriteString(styles.StatusMuted.Render("   (r rescan, A all namespaces, enter open pod)"))
+	b.WriteString("\n\n")
+
+	if t.err != nil {
+		b.WriteString(styles.StatusError.Render("  Error: " + t.err.Error()))
+		return b.String()
+	}
+	if len(t.results) == 0 {
+		b.WriteString(styles.StatusRunning.Render("  Nothing looks broken"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-9s ", "SEVERITY")
+	if t.allNamespaces {
+		header += fmt.Sprintf("%-18s ", "NAMESPACE")
+	}
+	header += fmt.Sprintf("%-36s %-18s %-8s %-6s %s", "POD", "STATUS", "RESTARTS", "WARN", "HINT")
+	b.WriteString(styles.TableHeaderStyle.Render(header))
+	b.WriteString("\n")
+
+	start, end := t.visibleRange(len(t.results))
+	for i := start; i < end; i++ {
+		b.WriteString(t.renderRow(t.results[i], i == t.cursor))
+		b.WriteString("\n")
+	}
+
+	if start > 0 || end < len(t.results) {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d/%d", t.cursor+1, len(t.results))))
+	} else {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d pods need attention", len(t.results))))
+	}
+
+	return b.String()
+}
+
+func (t TriageView) renderRow(r k8s.PodTriage, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = styles.CursorStyle.Render("> ")
+	}
+
+	severity := r.Severity()
+	row := styles.GetSeverityStyle(severity).Render(fmt.Sprintf("%-9s", severity)) + " "
+	used := 2 + 10
+	if t.allNamespaces {
+		row += fmt.Sprintf("%-18s ", styles.Truncate(r.Pod.Namespace, 18))
+		used += 19
+	}
+	row += fmt.Sprintf("%-36s ", styles.Truncate(r.Pod.Name, 36))
+	row += styles.GetStatusStyle(r.Pod.Status).Render(fmt.Sprintf("%-18s", styles.Truncate(r.Pod.Status, 18))) + " "
+
+	restarts := fmt.Sprintf("%-8d", r.Pod.Restarts)
+	if r.Pod.Restarts > 0 {
+		restarts = styles.StatusError.Render(restarts)
+	}
+	row += restarts + " "
+
+	warnings := fmt.Sprintf("%-6s", "-")
+	if n := r.WarningCount(); n > 0 {
+		warnings = styles.EventWarning.Render(fmt.Sprintf("%-6d", n))
+	}
+	row += warnings + " "
+	used += 37 + 19 + 9 + 7
+
+	hint := r.TopHint()
+	if extra := len(r.Helpers) - 1; extra > 0 {
+		hint += fmt.Sprintf(" (+%d)", extra)
+	}
+	maxHintLen := t.width - used
+	if maxHintLen < 20 {
+		maxHintLen = 20
+	}
+	row += styles.LogNormal.Render(styles.Truncate(hint, maxHintLen))
+
+	if selected {
+		return lipgloss.NewStyle().Background(styles.Surface).Render(cursor + row)
+	}
+	return cursor + row
+}
+
+func (t TriageView) visibleRange(total int) (int, int) {
+	maxVisible := t.height - 8
+	if maxVisible < 5 {
+		maxVisible = 15
+	}
+	if total <= maxVisible {
+		return 0, total
+	}
+
+	start := t.cursor - maxVisible/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxVisible
+	if end > total {
+		end = total
+		start = end - maxVisible
+	}
+	return start, end
+}
+
+// SetNamespace sets the namespace scanned when not scanning all namespaces.
+func (t *TriageView) SetNamespace(namespace string) {
+	if t.namespace != namespace {
+		t.results = nil
+		t.cursor = 0
+	}
+	t.namespace = namespace
+}
+
+// ScanNamespace is the namespace to scan; empty means all.
+func (t TriageView) ScanNamespace() string {
+	if t.allNamespaces {
+		return ""
+	}
+	return t.namespace
+}
+
+// SetResults replaces the ranking, keeping the cursor on the same pod when
+// it is still listed.
+func (t *TriageView) SetResults(results []k8s.PodTriage, err error) {
+	t.err = err
+	if err != nil {
+		return
+	}
+
+	var selected string
+	if p := t.SelectedPod(); p != nil {
+		selected = p.Namespace + "/" + p.Name
+	}
+
+	t.results = results
+	t.cursor = 0
+	for i, r := range t.results {
+		if r.Pod.Namespace+"/"+r.Pod.Name == selected {
+			t.cursor = i
+			break
+		}
+	}
+}
+
+func (t TriageView) SelectedPod() *k8s.PodInfo {
+	if t.cursor >= 0 && t.cursor < len(t.results) {
+		pod := t.results[t.cursor].Pod
+		return &pod
+	}
+	return nil
+}
+
+func (t *TriageView) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+}
@@ -0,0 +1,302 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/keys"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// warningAges are the age filter choices; zero means no limit.
+var warningAges = []time.Duration{0, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// WarningsView is a feed of Warning events for a namespace or the whole
+// cluster, grouped by reason and involved object.
+type WarningsView struct {
+	events        []k8s.EventInfo
+	namespace     string
+	allNamespaces bool
+	reason        string
+	kind          string
+	ageIndex      int
+	cursor        int
+	width         int
+	height        int
+	err           error
+	keys          keys.KeyMap
+}
+
+func NewWarningsView() WarningsView {
+	return WarningsView{
+		keys: keys.DefaultKeyMap(),
+	}
+}
+
+func (w WarningsView) Init() tea.Cmd {
+	return nil
+}
+
+func (w WarningsView) Update(msg tea.Msg) (WarningsView, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return w, nil
+	}
+
+	total := len(w.groups())
+	switch {
+	case key.Matches(keyMsg, w.keys.Up):
+		if w.cursor > 0 {
+			w.cursor--
+		}
+	case key.Matches(keyMsg, w.keys.Down):
+		if w.cursor < total-1 {
+			w.cursor++
+		}
+	case key.Matches(keyMsg, w.keys.Home):
+		w.cursor = 0
+	case key.Matches(keyMsg, w.keys.End):
+		if total > 0 {
+			w.cursor = total - 1
+		}
+	case key.Matches(keyMsg, w.keys.ToggleAllEvents):
+		w.allNamespaces = !w.allNamespaces
+		w.events = nil
+		w.cursor = 0
+	case key.Matches(keyMsg, w.keys.Clear):
+		w.reason = ""
+		w.kind = ""
+		w.ageIndex = 0
+		w.cursor = 0
+	default:
+		switch keyMsg.String() {
+		case "o":
+			w.reason = nextFilterValue(k8s.WarningReasons(w.events), w.reason)
+			w.cursor = 0
+		case "i":
+			w.kind = nextFilterValue(k8s.WarningKinds(w.events), w.kind)
+			w.cursor = 0
+		case "a":
+			w.ageIndex = (w.ageIndex + 1) % len(warningAges)
+			w.cursor = 0
+		}
+	}
+	return w, nil
+}
+
+// nextFilterValue cycles "" → values[0] → ... → values[n-1] → "".
+func nextFilterValue(values []string, current string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, v := range values {
+		if v == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+func (w WarningsView) filter() k8s.WarningFilter {
+	return k8s.WarningFilter{
+		Reason: w.reason,
+		Kind:   w.kind,
+		MaxAge: warningAges[w.ageIndex],
+	}
+}
+
+func (w WarningsView) groups() []k8s.WarningGroup {
+	return k8s.GroupWarnings(k8s.FilterWarnings(w.events, w.filter(), time.Now()))
+}
+
+func (w WarningsView) View() string {
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+
+	scope := "namespace " + w.namespace
+	if w.allNamespaces {
+		scope = "all namespaces"
+	}
+	b.WriteString(iconStyle.Render("⚠"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render("WARNINGS"))
+	b.WriteString(styles.StatusMuted.Render("  " + scope))
+	b.WriteString("\n")
+
+	b.WriteString(w.renderFilters())
+	b.WriteString("\n\n")
+
+	if w.err != nil {
+		b.WriteString(styles.StatusError.Render("  Error: " + w.err.Error()))
+		return b.String()
+	}
+
+	groups := w.groups()
+	if len(groups) == 0 {
+		b.WriteString(styles.StatusMuted.Render("  No warnings found"))
+		return b.String()
+	}
+
+	header := fmt.Sprintf("  %-6s %-22s %-36s ", "COUNT", "REASON", "OBJECT")
+	if w.allNamespaces {
+		header += fmt.Sprintf("%-18s ", "NAMESPACE")
+	}
+	header += fmt.Sprintf("%-6s %s", "LAST", "MESSAGE")
+	b.WriteString(styles.TableHeaderStyle.Render(header))
+	b.WriteString("\n")
+
+	start, end := w.visibleRange(len(groups))
+	for i := start; i < end; i++ {
+		b.WriteString(w.renderRow(groups[i], i == w.cursor))
+		b.WriteString("\n")
+	}
+
+	if start > 0 || end < len(groups) {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d/%d", w.cursor+1, len(groups))))
+	} else {
+		b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("\n  %d groups", len(groups))))
+	}
+
+	return b.String()
+}
+
+func (w WarningsView) renderFilters() string {
+	labelStyle := styles.HelpDescStyle
+	valueStyle := lipgloss.NewStyle().Foreground(styles.Secondary).Bold(true)
+
+	value := func(v string) string {
+		if v == "" {
+			return "any"
+		}
+		return v
+	}
+	age := "any"
+	if d := warningAges[w.ageIndex]; d > 0 {
+		age = formatWindow(d)
+	}
+
+	return "  " +
+		labelStyle.Render("Reason ") + valueStyle.Render(value(w.reason)) +
+		labelStyle.Render("  Kind ") + valueStyle.Render(value(w.kind)) +
+		labelStyle.Render("  Age ") + valueStyle.Render(age) +
+		styles.StatusMuted.Render("   (o reason, i kind, a age, A all namespaces, c clear)")
+}
+
+func (w WarningsView) renderRow(g k8s.WarningGroup, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = styles.CursorStyle.Render("> ")
+	}
+
+	row := fmt.Sprintf("%-6s %s %-36s ",
+		fmt.Sprintf("x%d", g.Count),
+		styles.EventWarning.Render(fmt.Sprintf("%-22s", styles.Truncate(g.Reason, 22))),
+		styles.Truncate(g.Object.String(), 36),
+	)
+	used := 2 + 6 + 1 + 22 + 1 + 36 + 1
+	if w.allNamespaces {
+		row += fmt.Sprintf("%-18s ", styles.Truncate(g.Object.Namespace, 18))
+		used += 19
+	}
+	row += styles.LogTimestamp.Render(fmt.Sprintf("%-6s", g.Age())) + " "
+	used += 7
+
+	maxMsgLen := w.width - used
+	if maxMsgLen < 20 {
+		maxMsgLen = 20
+	}
+	row += styles.LogNormal.Render(styles.Truncate(g.Message, maxMsgLen))
+
+	if selected {
+		return lipgloss.NewStyle().Background(styles.Surface).Render(cursor + row)
+	}
+	return cursor + row
+}
+
+func (w WarningsView) visibleRange(total int) (int, int) {
+	maxVisible := w.height - 8
+	if maxVisible < 5 {
+		maxVisible = 15
+	}
+	if total <= maxVisible {
+		return 0, total
+	}
+
+	start := w.cursor - maxVisible/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxVisible
+	if end > total {
+		end = total
+		start = end - maxVisible
+	}
+	return start, end
+}
+
+// SetNamespace sets the namespace shown when not watching all namespaces.
+func (w *WarningsView) SetNamespace(namespace string) {
+	if w.namespace != namespace {
+		w.events = nil
+		w.cursor = 0
+	}
+	w.namespace = namespace
+}
+
+// WatchNamespace is the namespace to list and watch; empty means all.
+func (w WarningsView) WatchNamespace() string {
+	if w.allNamespaces {
+		return ""
+	}
+	return w.namespace
+}
+
+func (w *WarningsView) SetEvents(events []k8s.EventInfo, err error) {
+	w.err = err
+	if err != nil {
+		return
+	}
+	w.events = events
+	w.clampCursor()
+}
+
+// AddEvent merges an event delivered by a watch.
+func (w *WarningsView) AddEvent(event k8s.EventInfo) {
+	if !k8s.IsWarningEvent(event) {
+		return
+	}
+	w.events = k8s.MergeEvent(w.events, event)
+	w.clampCursor()
+}
+
+func (w *WarningsView) clampCursor() {
+	if total := len(w.groups()); w.cursor >= total {
+		w.cursor = total - 1
+	}
+	if w.cursor < 0 {
+		w.cursor = 0
+	}
+}
+
+func (w WarningsView) SelectedGroup() *k8s.WarningGroup {
+	groups := w.groups()
+	if w.cursor >= 0 && w.cursor < len(groups) {
+		return &groups[w.cursor]
+	}
+	return nil
+}
+
+func (w *WarningsView) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+}
@@ -0,0 +1,190 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doganarif/k9sight/internal/k8s"
+	"github.com/doganarif/k9sight/internal/ui/styles"
+)
+
+// WorkloadView shows a workload together with the events of every object
+// in its owner chain.
+type WorkloadView struct {
+	workload *k8s.WorkloadInfo
+	objects  []k8s.ObjectReference
+	events   EventsPanel
+	detail   ResultViewer
+	width    int
+	height   int
+	err      error
+}
+
+func NewWorkloadView() WorkloadView {
+	events := NewEventsPanel()
+	events.SetShowAll(true)
+	return WorkloadView{
+		events: events,
+	}
+}
+
+func (w WorkloadView) Init() tea.Cmd {
+	return nil
+}
+
+func (w WorkloadView) Update(msg tea.Msg) (WorkloadView, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if detail, ok := msg.(ShowEventDetailMsg); ok {
+		w.detail.Show("Event: "+detail.Event.Reason, FormatEventDetail(detail.Event), w.width-4, w.height)
+		return w, nil
+	}
+	if w.detail.IsVisible() {
+		w.detail, cmd = w.detail.Update(msg)
+		return w, cmd
+	}
+
+	w.events, cmd = w.events.Update(msg)
+	return w, cmd
+}
+
+func (w WorkloadView) View() string {
+	if w.workload == nil {
+		return styles.StatusMuted.Render("  No workload selected")
+	}
+	if w.detail.IsVisible() {
+		return lipgloss.Place(w.width, w.height, lipgloss.Center, lipgloss.Center, w.detail.View())
+	}
+
+	var b strings.Builder
+
+	iconStyle := lipgloss.NewStyle().Foreground(styles.Primary).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(styles.Text).Bold(true)
+	statusStyle := styles.GetStatusStyle(w.workload.Status)
+
+	b.WriteString(iconStyle.Render("◈"))
+	b.WriteString(" ")
+	b.WriteString(titleStyle.Render(strings.ToUpper(w.workload.Type.Kind()) + " " + w.workload.Name))
+	b.WriteString("  ")
+	b.WriteString(statusStyle.Render(w.workload.Status))
+	b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("  ready %s  age %s", w.workload.Ready, w.workload.Age)))
+	b.WriteString("\n")
+
+	if w.err != nil {
+		b.WriteString(styles.StatusError.Render("  Error: " + w.err.Error()))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(styles.SubtitleStyle.Render("  Objects: " + summarizeObjects(w.objects)))
+		b.WriteString("\n")
+	}
+	health := w.renderHealth()
+	b.WriteString(health)
+	b.WriteString("\n")
+
+	eventsHeight := w.height - 4 - strings.Count(health, "\n")
+	w.events.SetSize(w.width-4, eventsHeight)
+	b.WriteString(styles.ActivePanelStyle.Width(w.width - 4).Height(eventsHeight).Render(w.events.View()))
+
+	return b.String()
+}
+
+// maxHealthHints bounds the health section so the events keep most of
+// the screen.
+const maxHealthHints = 4
+
+// renderHealth lists the workload health hints with their first
+// suggestion, followed by any condition that is not in its healthy state.
+func (w WorkloadView) renderHealth() string {
+	hints := k8s.AnalyzeWorkload(w.workload)
+	if len(hints) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, h := range hints {
+		if i == maxHealthHints {
+			b.WriteString(styles.StatusMuted.Render(fmt.Sprintf("  +%d more\n", len(hints)-i)))
+			break
+		}
+		b.WriteString(styles.GetSeverityStyle(h.Severity).Render(fmt.Sprintf("  [%s] %s", h.Severity, h.Issue)))
+		b.WriteString("\n")
+		if len(h.Suggestions) > 0 {
+			b.WriteString(styles.StatusMuted.Render("      " + styles.Truncate(h.Suggestions[0], w.width-10)))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// summarizeObjects renders counts per kind in owner chain order, e.g.
+// "1 Deployment, 2 ReplicaSets, 4 Pods".
+func summarizeObjects(objects []k8s.ObjectReference) string {
+	if len(objects) == 0 {
+		return "loading..."
+	}
+
+	var kinds []string
+	counts := map[string]int{}
+	for _, o := range objects {
+		if counts[o.Kind] == 0 {
+			kinds = append(kinds, o.Kind)
+		}
+		counts[o.Kind]++
+	}
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		label := kind
+		if counts[kind] > 1 {
+			label += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", counts[kind], label))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (w *WorkloadView) SetWorkload(workload *k8s.WorkloadInfo) {
+	w.workload = workload
+	w.objects = nil
+	w.err = nil
+	w.events.SetEvents(nil)
+}
+
+// UpdateWorkload replaces the workload with a fresher copy of itself,
+// keeping the loaded events.
+func (w *WorkloadView) UpdateWorkload(workload *k8s.WorkloadInfo) {
+	if w.workload != nil && workload != nil && w.workload.Name == workload.Name {
+		w.workload = workload
+	}
+}
+
+func (w *WorkloadView) SetData(objects []k8s.ObjectReference, events []k8s.EventInfo, err error) {
+	w.err = err
+	if err != nil {
+		return
+	}
+	w.objects = objects
+	w.events.SetEvents(events)
+}
+
+func (w *WorkloadView) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+	w.events.SetSize(width-4, height-4)
+}
+
+// HasActiveOverlay reports whether esc and enter belong to the view itself.
+func (w WorkloadView) HasActiveOverlay() bool {
+	return w.detail.IsVisible() || w.events.IsSearching()
+}
+
+func (w WorkloadView) IsSearching() bool {
+	return w.events.IsSearching()
+}
+
+func (w WorkloadView) Workload() *k8s.WorkloadInfo {
+	return w.workload
+}
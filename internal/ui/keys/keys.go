@@ -21,6 +21,7 @@ type KeyMap struct {
 	Refresh key.Binding
 	Search  key.Binding
 	Clear   key.Binding
+	History key.Binding
 
 	// Panel navigation
 	NextPanel key.Binding
@@ -33,6 +34,9 @@ type KeyMap struct {
 	// Mode switches
 	Namespace    key.Binding
 	ResourceType key.Binding
+	Warnings     key.Binding
+	Top          key.Binding
+	Triage       key.Binding
 
 	// Log actions
 	ToggleFollow key.Binding
@@ -48,10 +52,14 @@ type KeyMap struct {
 	// Pod actions
 	CopyCommands key.Binding
 	PodActions   key.Binding
+	Bundle       key.Binding
 
 	// Workload actions
-	Scale   key.Binding
-	Restart key.Binding
+	Scale          key.Binding
+	Restart        key.Binding
+	WorkloadEvents key.Binding
+	Edit           key.Binding
+	Drift          key.Binding
 }
 
 func DefaultKeyMap() KeyMap {
@@ -119,6 +127,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "clear filter"),
 		),
+		History: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "status history"),
+		),
 
 		// Panel navigation
 		NextPanel: key.NewBinding(
@@ -155,6 +167,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("t"),
 			key.WithHelp("t", "type"),
 		),
+		Warnings: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "warnings"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "top"),
+		),
+		Triage: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "triage"),
+		),
 
 		// Log actions
 		ToggleFollow: key.NewBinding(
@@ -191,6 +215,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("a"),
 			key.WithHelp("a", "pod actions"),
 		),
+		Bundle: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "support bundle"),
+		),
 
 		// Workload actions
 		Scale: key.NewBinding(
@@ -201,5 +229,17 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("R"),
 			key.WithHelp("R", "restart"),
 		),
+		WorkloadEvents: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "workload events"),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "edit YAML"),
+		),
+		Drift: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "drift"),
+		),
 	}
 }
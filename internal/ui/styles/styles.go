@@ -155,6 +155,24 @@ var (
 			Foreground(Text).
 			Background(Surface).
 			Padding(0, 1)
+
+	// Search matches inside text
+	SearchMatch = lipgloss.NewStyle().
+			Foreground(Background).
+			Background(Warning)
+
+	// Raw manifest syntax
+	SyntaxKey = lipgloss.NewStyle().
+			Foreground(Secondary)
+
+	SyntaxString = lipgloss.NewStyle().
+			Foreground(Success)
+
+	SyntaxLiteral = lipgloss.NewStyle().
+			Foreground(Accent)
+
+	SyntaxPunct = lipgloss.NewStyle().
+			Foreground(Muted)
 )
 
 func GetStatusStyle(status string) lipgloss.Style {
@@ -170,6 +188,18 @@ func GetStatusStyle(status string) lipgloss.Style {
 	}
 }
 
+// GetSeverityStyle colors a debug hint severity.
+func GetSeverityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case "High":
+		return StatusError
+	case "Medium", "Warning":
+		return EventWarning
+	default:
+		return StatusMuted
+	}
+}
+
 func RenderWithWidth(s lipgloss.Style, content string, width int) string {
 	return s.Width(width).Render(content)
 }
@@ -3,6 +3,7 @@ package views
 import (
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -43,6 +44,12 @@ type Dashboard struct {
 	namespace     string // Current namespace for kubectl commands
 	context       string // Current context for kubectl commands
 	pendingAction *components.PodActionItem // Action waiting for confirmation
+
+	// Recommendations offered by the right-sizing menu
+	pendingResources *ApplyResourcesRequest
+
+	// ConfigMaps and Secrets offered in the pod actions menu
+	related *k8s.RelatedResources
 }
 
 func NewDashboard() Dashboard {
@@ -72,6 +79,46 @@ type DeletePodRequest struct {
 	PodName   string
 }
 
+// EditResourceRequest is sent to app.go to edit an object in $EDITOR
+type EditResourceRequest struct {
+	Ref k8s.ObjectReference
+}
+
+// ViewConfigDataRequest is sent to app.go to open a ConfigMap or Secret
+type ViewConfigDataRequest struct {
+	Ref k8s.ObjectReference
+}
+
+// ApplyResourcesRequest is sent to app.go to patch the requests and limits
+// of the workload owning the dashboard's pod
+type ApplyResourcesRequest struct {
+	Namespace       string
+	ResourceType    k8s.ResourceType
+	Name            string
+	Recommendations []k8s.Recommendation
+}
+
+// RightSizingRequest is sent to app.go to check recommendations against
+// the workload's pod template before they are offered
+type RightSizingRequest struct {
+	Request ApplyResourcesRequest
+}
+
+// RightSizingResult carries the recommendations for containers in the pod
+// template; Skipped are containers only the pod has, such as sidecars
+// injected at admission
+type RightSizingResult struct {
+	Request ApplyResourcesRequest
+	Skipped []string
+	Err     error
+}
+
+// ApplyResourcesResult reports the outcome of an ApplyResourcesRequest
+type ApplyResourcesResult struct {
+	Workload string
+	Err      error
+}
+
 // ExecFinishedMsg is sent when an external command finishes
 type ExecFinishedMsg struct {
 	Err error
@@ -108,6 +155,63 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 		return d, nil
 	}
 
+	// Show the full event selected in the events panel
+	if detail, ok := msg.(components.ShowEventDetailMsg); ok {
+		d.resultViewer.Show("Event: "+detail.Event.Reason, components.FormatEventDetail(detail.Event), d.width-4, d.height-4)
+		return d, nil
+	}
+
+	// Offer the metrics panel's recommendations for the owning workload
+	if sizing, ok := msg.(components.ShowRightSizingMsg); ok {
+		if d.pod == nil {
+			return d, nil
+		}
+		rt, name, found := k8s.PodWorkload(d.pod)
+		if !found {
+			d.statusMsg = "No deployment, statefulset or daemonset owns this pod"
+			return d, nil
+		}
+		req := ApplyResourcesRequest{
+			Namespace:       d.pod.Namespace,
+			ResourceType:    rt,
+			Name:            name,
+			Recommendations: sizing.Recommendations,
+		}
+		d.statusMsg = "Reading pod template..."
+		return d, func() tea.Msg {
+			return RightSizingRequest{Request: req}
+		}
+	}
+
+	// Offer only recommendations for containers in the pod template
+	if sizing, ok := msg.(RightSizingResult); ok {
+		d.statusMsg = ""
+		if sizing.Err != nil {
+			d.statusMsg = "Cannot read pod template: " + sizing.Err.Error()
+			return d, nil
+		}
+		req := sizing.Request
+		if len(sizing.Skipped) > 0 {
+			d.statusMsg = "Skipped " + strings.Join(sizing.Skipped, ", ") + ": not in the pod template (injected)"
+		}
+		if len(req.Recommendations) == 0 {
+			return d, nil
+		}
+		d.pendingResources = &req
+		items := components.RightSizingActions(req.Namespace, req.ResourceType, req.Name, req.Recommendations)
+		d.podActionMenu.Show("Right-size "+strings.ToLower(req.ResourceType.Kind())+"/"+req.Name, items)
+		return d, nil
+	}
+
+	if result, ok := msg.(ApplyResourcesResult); ok {
+		if result.Err != nil {
+			d.statusMsg = "Apply failed: " + result.Err.Error()
+		} else {
+			d.statusMsg = "Resources updated on " + result.Workload
+		}
+		return d, nil
+	}
+
 	// Handle ActionMenuResult (copy commands)
 	if result, ok := msg.(components.ActionMenuResult); ok {
 		if result.Copied && result.Err == nil {
@@ -166,6 +270,31 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 					Content: string(output),
 				}
 			}
+		case "edit":
+			ref := k8s.ObjectReference{Kind: "Pod", Namespace: d.pod.Namespace, Name: d.pod.Name}
+			return d, func() tea.Msg {
+				return EditResourceRequest{Ref: ref}
+			}
+		case "view-configmap", "view-secret":
+			kind := "ConfigMap"
+			if result.Item.Action == "view-secret" {
+				kind = "Secret"
+			}
+			ref := k8s.ObjectReference{Kind: kind, Namespace: d.pod.Namespace, Name: result.Item.Target}
+			return d, func() tea.Msg {
+				return ViewConfigDataRequest{Ref: ref}
+			}
+		case "apply-resources":
+			if d.pendingResources != nil {
+				target := strings.ToLower(d.pendingResources.ResourceType.Kind()) + "/" + d.pendingResources.Name
+				d.confirmDialog.Show(
+					"Apply Resources",
+					"Patch requests and limits of '"+target+"'?\nThis rolls out new pods.",
+					"apply-resources",
+					d.pendingResources,
+				)
+			}
+			return d, nil
 		case "copy":
 			// Copy the command to clipboard
 			err := components.CopyToClipboard(result.Item.Command)
@@ -193,6 +322,13 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 						}
 					}
 				}
+			case "apply-resources":
+				if req, ok := result.Data.(*ApplyResourcesRequest); ok {
+					d.statusMsg = "Applying resources..."
+					return d, func() tea.Msg {
+						return *req
+					}
+				}
 			case "exec", "port-forward":
 				// Execute the pending action
 				if d.pendingAction != nil {
@@ -254,6 +390,18 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 			return d, cmd
 		}
 
+		// Same for the events panel search
+		if d.focus == FocusEvents && d.events.IsSearching() {
+			d.events, cmd = d.events.Update(msg)
+			return d, cmd
+		}
+
+		// And the raw manifest search
+		if d.focus == FocusManifest && d.manifest.IsSearching() {
+			d.manifest, cmd = d.manifest.Update(msg)
+			return d, cmd
+		}
+
 		// Clear status message on any key press
 		d.statusMsg = ""
 
@@ -265,6 +413,7 @@ func (d Dashboard) Update(msg tea.Msg) (Dashboard, tea.Cmd) {
 					containers = append(containers, c.Name)
 				}
 				items := components.PodActions(d.namespace, d.pod.Name, containers)
+				items = append(items, components.ConfigDataActions(d.pod.Namespace, d.related)...)
 				d.podActionMenu.Show("Pod Actions", items)
 			}
 			return d, nil
@@ -479,12 +628,8 @@ func (d *Dashboard) SetPod(pod *k8s.PodInfo) {
 	d.manifest.SetPod(pod)
 	d.metrics.SetPod(pod)
 
-	// Extract container names for logs panel
-	var containerNames []string
-	for _, c := range pod.Containers {
-		containerNames = append(containerNames, c.Name)
-	}
-	d.logs.SetContainers(containerNames)
+	// Init containers come last so their logs can be picked too
+	d.logs.SetContainers(pod.ContainerNames())
 }
 
 func (d *Dashboard) SetLogs(logs []k8s.LogLine) {
@@ -495,14 +640,59 @@ func (d *Dashboard) SetEvents(events []k8s.EventInfo) {
 	d.events.SetEvents(events)
 }
 
+func (d *Dashboard) AddEvent(event k8s.EventInfo) {
+	d.events.UpsertEvent(event)
+}
+
 func (d *Dashboard) SetMetrics(metrics *k8s.PodMetrics) {
 	d.metrics.SetMetrics(metrics)
 }
 
+func (d *Dashboard) SetMetricsHistory(history map[string][]k8s.MetricSample, window time.Duration) {
+	d.metrics.SetHistory(history, window)
+}
+
+func (d *Dashboard) SetMetricsRangeAvailable(available bool) {
+	d.metrics.SetRangeAvailable(available)
+}
+
+func (d *Dashboard) SetMetricsRange(data *k8s.PodRangeMetrics, err error) {
+	d.metrics.SetRange(data, err)
+}
+
+func (d Dashboard) MetricsRangeWindow() time.Duration {
+	return d.metrics.RangeWindow()
+}
+
 func (d *Dashboard) SetRelated(related *k8s.RelatedResources) {
+	d.related = related
 	d.manifest.SetRelated(related)
 }
 
+// SetManifestObject delivers an object asked for with
+// components.LoadManifestMsg.
+func (d *Dashboard) SetManifestObject(ref k8s.ObjectReference, fields map[string]interface{}, err error) {
+	d.manifest.SetObject(ref, fields, err)
+}
+
+// ManifestObject returns the object shown in the manifest panel's raw mode,
+// if it is active, for reloading on refresh.
+func (d Dashboard) ManifestObject() (k8s.ObjectReference, bool) {
+	return d.manifest.RawObject()
+}
+
+// SetManifestEnv delivers an environment asked for with
+// components.LoadEnvMsg.
+func (d *Dashboard) SetManifestEnv(namespace, pod string, envs []k8s.ContainerEnv, err error) {
+	d.manifest.SetEnv(namespace, pod, envs, err)
+}
+
+// ManifestEnvShown reports whether the manifest panel shows the container
+// environment, for reloading on refresh.
+func (d Dashboard) ManifestEnvShown() bool {
+	return d.manifest.EnvShown()
+}
+
 func (d *Dashboard) SetHelpers(helpers []k8s.DebugHelper) {
 	d.manifest.SetHelpers(helpers)
 }
@@ -543,10 +733,28 @@ func (d Dashboard) LogsSelectedContainer() string {
 	return d.logs.SelectedContainer()
 }
 
+// SelectLogsContainer preselects the logs container, see
+// LogsPanel.SelectContainer.
+func (d *Dashboard) SelectLogsContainer(name string) bool {
+	return d.logs.SelectContainer(name)
+}
+
 func (d Dashboard) LogsShowPrevious() bool {
 	return d.logs.ShowPrevious()
 }
 
+func (d Dashboard) LogsShowHistory() bool {
+	return d.logs.ShowHistory()
+}
+
+func (d Dashboard) LogsHistoryWindow() time.Duration {
+	return d.logs.HistoryWindow()
+}
+
+func (d *Dashboard) SetLogsHistoryAvailable(available bool) {
+	d.logs.SetHistoryAvailable(available)
+}
+
 func (d *Dashboard) GetPod() *k8s.PodInfo {
 	return d.pod
 }
@@ -555,6 +763,11 @@ func (d Dashboard) IsLogsSearching() bool {
 	return d.logs.IsSearching()
 }
 
+// IsSearching reports whether any panel has its search input open.
+func (d Dashboard) IsSearching() bool {
+	return d.logs.IsSearching() || d.events.IsSearching() || d.manifest.IsSearching()
+}
+
 func (d Dashboard) HasActiveOverlay() bool {
 	return d.resultViewer.IsVisible() ||
 		d.confirmDialog.IsVisible() ||